@@ -0,0 +1,231 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+)
+
+// GenerateAzAPIModule produces a DPaaS module wrapping azapi_resource for a
+// resource type the azurerm provider does not cover. Variables, examples,
+// tests, tags and the null-label naming are those of an azurerm module; the
+// variables are assembled into the request body in main.tf.
+func GenerateAzAPIModule(res *schema.AzAPIResource, opts Options) (GeneratedModule, error) {
+	mainTf, err := generateAzAPIMainTf(res)
+	if err != nil {
+		return GeneratedModule{}, err
+	}
+	outputsTf, err := generateAzAPIOutputsTf(res)
+	if err != nil {
+		return GeneratedModule{}, err
+	}
+	return generateModule(res.Info, mainTf, outputsTf, opts)
+}
+
+func generateAzAPIMainTf(res *schema.AzAPIResource) (string, error) {
+	info := res.Info
+	r := moduleResource{info: info, label: "this"}
+	f := hclwrite.NewEmptyFile()
+
+	// the resource group ID is built in the subscription azapi deploys to
+	var header string
+	parentID := "var.parent_id"
+	if res.ResourceGroupScoped {
+		header = "data \"azapi_client_config\" \"current\" {}\n\n"
+		parentID = `"/subscriptions/${data.azapi_client_config.current.subscription_id}/resourceGroups/${var.resource_group_name}"`
+	}
+
+	body := f.Body().AppendNewBlock("resource", []string{info.ResourceType, r.label}).Body()
+	var s exprSetter
+	set := r.setter(body, &s)
+	nameVar := info.ShortName + "_name"
+
+	set("count", "local.enabled ? 1 : 0")
+	body.AppendNewline()
+	body.SetAttributeRaw("type", stringTokens(res.TypeVersion()))
+	set("name", fmt.Sprintf("var.%s != null ? var.%s : module.this.id", nameVar, nameVar))
+	set("parent_id", parentID)
+	if hasAttribute(info, "location") {
+		set("location", "var.location")
+	}
+
+	body.AppendNewline()
+	set("body", azapiBodyExpr(r))
+	// optional variables left null are dropped from the request body
+	set("ignore_null_property", "true")
+	if len(res.Outputs) > 0 {
+		var paths []string
+		exported := map[string]bool{}
+		for _, name := range info.ComputedOnlyAttrs {
+			if path := exportPath(res.Outputs[name]); !exported[path] {
+				exported[path] = true
+				paths = append(paths, hclQuote(path))
+			}
+		}
+		set("response_export_values", "["+strings.Join(paths, ", ")+"]")
+	}
+
+	if hasAttribute(info, "tags") {
+		body.AppendNewline()
+		set("tags", "local.tags")
+	}
+	if s.err != nil {
+		return "", fmt.Errorf("%s: %w", r.address(), s.err)
+	}
+	return header + string(hclwrite.Format(f.Bytes())), nil
+}
+
+// azapiBodyExpr renders the request body: the top-level REST properties and
+// a properties object, each set from its variable.
+func azapiBodyExpr(r moduleResource) string {
+	top := map[string]string{}
+	properties := map[string]string{}
+	assign := func(path, expr string) {
+		if segments := restSegments(path); len(segments) == 2 {
+			properties[segments[1]] = expr
+		} else {
+			top[path] = expr
+		}
+	}
+	for _, attr := range r.info.Attributes {
+		if attr.RESTName != "" {
+			assign(attr.RESTName, r.valueExpr(attr))
+		}
+	}
+	for _, block := range r.info.Blocks {
+		assign(block.RESTName, azapiBlockExpr(block, "var."+r.varName(block.Name)))
+	}
+	if len(properties) > 0 {
+		top["properties"] = objectExpr(properties)
+	}
+	return objectExpr(top)
+}
+
+// azapiBlockExpr renders a nested block variable as a REST object, or as a
+// list of objects for the map variables of multi-value blocks, keeping the
+// REST property names.
+func azapiBlockExpr(block schema.ParsedBlock, ref string) string {
+	item := ref
+	if !isSingleBlock(block) {
+		item = block.Name
+	}
+	fields := map[string]string{}
+	for _, attr := range block.Attributes {
+		fields[attr.RESTName] = item + "." + attr.Name
+	}
+	for _, nested := range block.Blocks {
+		fields[nested.RESTName] = azapiBlockExpr(nested, item+"."+nested.Name)
+	}
+	obj := objectExpr(fields)
+
+	switch {
+	case !isSingleBlock(block):
+		return fmt.Sprintf("try(length(%s), 0) > 0 ? [for %s in values(%s) : %s] : null", ref, block.Name, ref, obj)
+	case block.Required:
+		return obj
+	}
+	return fmt.Sprintf("%s == null ? null : %s", ref, obj)
+}
+
+// objectExpr renders an object constructor, one key per line in key order.
+// REST keys are quoted only when they are not valid identifiers.
+func objectExpr(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, k := range keys {
+		key := k
+		if !isIdentifier(k) {
+			key = hclQuote(k)
+		}
+		b.WriteString(fmt.Sprintf("%s = %s\n", key, fields[k]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func isIdentifier(s string) bool {
+	for i, c := range s {
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && (c == '-' || c >= '0' && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return s != ""
+}
+
+// generateAzAPIOutputsTf exports the resource and each read-only REST
+// property, read from the response values the resource exports.
+func generateAzAPIOutputsTf(res *schema.AzAPIResource) (string, error) {
+	info := res.Info
+	address := info.ResourceType + ".this"
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	var s exprSetter
+
+	body.AppendUnstructuredTokens(commentTokens("outputs.tf"))
+	out := body.AppendNewBlock("output", []string{"id"}).Body()
+	out.SetAttributeRaw("description", stringTokens(fmt.Sprintf("The ID of the %s", info.DisplayName)))
+	s.set(out, "value", address)
+
+	for _, name := range info.ComputedOnlyAttrs {
+		body.AppendNewline()
+		out := body.AppendNewBlock("output", []string{name}).Body()
+		out.SetAttributeRaw("description", stringTokens(fmt.Sprintf("The %s of the %s", strings.ReplaceAll(name, "_", " "), info.DisplayName)))
+		s.set(out, "value", address+"[*].output"+restTraversal(res.Outputs[name]))
+	}
+	if s.err != nil {
+		return "", fmt.Errorf("outputs.tf: %w", s.err)
+	}
+	return string(hclwrite.Format(f.Bytes())), nil
+}
+
+// restSegments splits a REST path into its property names. Only the
+// "properties." prefix separates two: a property name may itself contain
+// dots, e.g. "@odata.type".
+func restSegments(path string) []string {
+	if key, ok := strings.CutPrefix(path, "properties."); ok {
+		return []string{"properties", key}
+	}
+	return []string{path}
+}
+
+// restTraversal renders the attribute accesses reading path from an object,
+// indexing the property names that are not identifiers:
+// .properties["@odata.type"].
+func restTraversal(path string) string {
+	var b strings.Builder
+	for _, seg := range restSegments(path) {
+		if isIdentifier(seg) {
+			b.WriteString("." + seg)
+		} else {
+			b.WriteString("[" + hclQuote(seg) + "]")
+		}
+	}
+	return b.String()
+}
+
+// exportPath is the response_export_values entry exporting path. azapi
+// splits entries on dots, so a property whose name contains one is exported
+// with its parent object, or with the whole response at the top level.
+func exportPath(path string) string {
+	var parts []string
+	for _, seg := range restSegments(path) {
+		if strings.Contains(seg, ".") {
+			break
+		}
+		parts = append(parts, seg)
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, ".")
+}
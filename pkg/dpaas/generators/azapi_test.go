@@ -0,0 +1,66 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// containerApps parses schematest.ContainerAppsSpec and names the module as
+// the tools do.
+func containerApps(t *testing.T) *schema.AzAPIResource {
+	t.Helper()
+	res, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "Microsoft.App/containerApps")
+	require.NoError(t, err)
+	res.Info.ModuleName = DefaultTemplatePack().ModuleName(res.Info.ShortName)
+	return res
+}
+
+func TestGenerateAzAPIModule(t *testing.T) {
+	res := containerApps(t)
+	files, err := GenerateAzAPIModule(res, Options{Scenarios: []string{"default", "complete", "disabled"}})
+	require.NoError(t, err)
+
+	main := files["main.tf"]
+	assert.Contains(t, main, `data "azapi_client_config" "current" {}`)
+	assert.Contains(t, main, `resource "azapi_resource" "this"`)
+	assert.Regexp(t, `type\s+= "Microsoft.App/containerApps@2024-03-01"`, main)
+	assert.Regexp(t, `name\s+= var.container_app_name != null \? var.container_app_name : module.this.id`, main)
+	assert.Contains(t, main, `resourceGroups/${var.resource_group_name}`)
+	assert.Regexp(t, `environmentId\s+= var.environment_id`, main)
+	assert.Regexp(t, `kind\s+= try\(var.kind, null\)`, main)
+	assert.Contains(t, main, "configuration = var.configuration == null ? null : {")
+	assert.Regexp(t, `targetPort\s+= var.configuration.ingress.target_port`, main)
+	assert.Contains(t, main, "[for traffic in values(var.configuration.ingress.traffic) : {")
+	assert.Regexp(t, `revisionName\s+= traffic.revision_name`, main)
+	assert.Regexp(t, `response_export_values\s+= \["properties.latestRevisionFqdn"\]`, main)
+	assert.Regexp(t, `tags\s+= local.tags`, main)
+
+	assert.Contains(t, files["outputs.tf"], "azapi_resource.this[*].output.properties.latestRevisionFqdn")
+	assert.Contains(t, files["variables.tf"], `variable "environment_id"`)
+	assert.Contains(t, files["tests/count.tftest.hcl"], `mock_provider "azapi" {}`)
+	assert.Contains(t, files["examples/basic/versions.tf"], `provider "azapi"`)
+}
+
+func TestGenerateAzAPIModule_NonIdentifierOutputs(t *testing.T) {
+	res := containerApps(t)
+	res.Outputs["odata_type"] = "properties.@odata.type"
+	res.Outputs["odata_etag"] = "@odata.etag"
+	res.Info.ComputedOnlyAttrs = append(res.Info.ComputedOnlyAttrs, "odata_etag", "odata_type")
+
+	files, err := GenerateAzAPIModule(res, Options{})
+	require.NoError(t, err)
+	assert.Regexp(t, `response_export_values\s+= \["properties.latestRevisionFqdn", "\*", "properties"\]`, files["main.tf"])
+
+	outputs := files["outputs.tf"]
+	_, diags := hclsyntax.ParseConfig([]byte(outputs), "outputs.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.Contains(t, outputs, `azapi_resource.this[*].output.properties["@odata.type"]`)
+	assert.Contains(t, outputs, `azapi_resource.this[*].output["@odata.etag"]`)
+	assert.Contains(t, outputs, "azapi_resource.this[*].output.properties.latestRevisionFqdn")
+}
@@ -0,0 +1,381 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+)
+
+// Composite is a module wrapping several resources, such as an App Service
+// Plan with a Linux Web App. The first resource is the primary one: it is
+// declared as "this", keeps un-prefixed variables and names the module. The
+// others are declared under their short name with variables prefixed by it,
+// e.g. service_plan_sku_name.
+//
+// Children are collections of child resources, such as storage containers
+// or subnets, created for_each of a map(object) variable named after them
+// (storage_containers). Each child is wired to its parent resource through
+// the argument naming it, e.g. storage_account_id or virtual_network_name.
+type Composite struct {
+	Resources []*schema.ResourceInfo
+	Children  []*schema.ResourceInfo
+	// Relationships wire arguments to other resources of the module, in
+	// addition to the inferred ones.
+	Relationships []Relationship
+}
+
+// Relationship sets an argument of one resource from an attribute of
+// another instead of a variable.
+type Relationship struct {
+	Resource  string // resource type whose argument is set, e.g. "azurerm_linux_web_app"
+	Argument  string // e.g. "service_plan_id"
+	Target    string // resource type referenced, e.g. "azurerm_service_plan"
+	Attribute string // attribute of the target; "id" when empty
+}
+
+func (r Relationship) String() string {
+	return fmt.Sprintf("%s.%s=%s.%s", r.Resource, r.Argument, r.Target, r.Attribute)
+}
+
+// ParseRelationship parses "<type>.<argument>=<type>.<attribute>", e.g.
+// "azurerm_linux_web_app.service_plan_id=azurerm_service_plan.id". The
+// attribute may be left out for "id".
+func ParseRelationship(s string) (Relationship, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok {
+		return Relationship{}, fmt.Errorf("relationship %q: want <type>.<argument>=<type>.<attribute>", s)
+	}
+	var r Relationship
+	if r.Resource, r.Argument, ok = strings.Cut(strings.TrimSpace(from), "."); !ok || r.Resource == "" || r.Argument == "" {
+		return Relationship{}, fmt.Errorf("relationship %q: want <type>.<argument> before '='", s)
+	}
+	r.Target, r.Attribute, _ = strings.Cut(strings.TrimSpace(to), ".")
+	if r.Target == "" {
+		return Relationship{}, fmt.Errorf("relationship %q: want <type>.<attribute> after '='", s)
+	}
+	if r.Attribute == "" {
+		r.Attribute = "id"
+	}
+	return r, nil
+}
+
+// GenerateCompositeModule produces one module declaring every resource of
+// c, with arguments that reference another resource of the module wired to
+// it instead of exposed as variables.
+func GenerateCompositeModule(c Composite, opts Options) (GeneratedModule, error) {
+	resources, iface, err := c.resolve()
+	if err != nil {
+		return nil, err
+	}
+	mainTf, err := generateMainTf(resources)
+	if err != nil {
+		return nil, err
+	}
+	outputsTf, err := generateOutputsTf(resources)
+	if err != nil {
+		return nil, err
+	}
+	return generateModule(iface, mainTf, outputsTf, opts)
+}
+
+// Wiring returns the declared and inferred relationships of c, sorted by
+// resource and argument.
+func (c Composite) Wiring() ([]Relationship, error) {
+	resources, _, err := c.resolve()
+	if err != nil {
+		return nil, err
+	}
+	var wiring []Relationship
+	for _, r := range resources {
+		for _, arg := range sortedKeys(r.refs) {
+			wiring = append(wiring, r.wiring[arg])
+		}
+	}
+	return wiring, nil
+}
+
+// resolve lays out the resource blocks and builds the module's input
+// interface: one ResourceInfo with the primary resource's identity and the
+// variables of every resource, which the rest of the module is generated from.
+func (c Composite) resolve() ([]moduleResource, *schema.ResourceInfo, error) {
+	if len(c.Resources) == 0 {
+		return nil, nil, fmt.Errorf("a composite module needs at least one resource")
+	}
+
+	resources := make([]moduleResource, len(c.Resources))
+	byType := map[string]*moduleResource{}
+	for i, info := range c.Resources {
+		if byType[info.ResourceType] != nil {
+			return nil, nil, fmt.Errorf("%s is listed twice", info.ResourceType)
+		}
+		r := moduleResource{info: info, label: "this", refs: map[string]string{}, wiring: map[string]Relationship{}}
+		if i > 0 {
+			r.label, r.prefix = info.ShortName, info.ShortName+"_"
+		}
+		resources[i] = r
+		byType[info.ResourceType] = &resources[i]
+	}
+	for _, info := range c.Children {
+		if byType[info.ResourceType] != nil {
+			return nil, nil, fmt.Errorf("%s is listed twice", info.ResourceType)
+		}
+		resources = append(resources, moduleResource{
+			info:       info,
+			label:      info.ShortName,
+			prefix:     info.ShortName + "_",
+			refs:       map[string]string{},
+			wiring:     map[string]Relationship{},
+			collection: pluralise(info.ShortName),
+		})
+	}
+	// byType points into resources, which may have been reallocated
+	for i := range resources {
+		byType[resources[i].info.ResourceType] = &resources[i]
+	}
+
+	for _, rel := range c.Relationships {
+		if rel.Attribute == "" {
+			rel.Attribute = "id"
+		}
+		r, t := byType[rel.Resource], byType[rel.Target]
+		switch {
+		case r == nil:
+			return nil, nil, fmt.Errorf("relationship %s: %s is not a resource of the module", rel, rel.Resource)
+		case t == nil:
+			return nil, nil, fmt.Errorf("relationship %s: %s is not a resource of the module", rel, rel.Target)
+		case r == t:
+			return nil, nil, fmt.Errorf("relationship %s: a resource cannot reference itself", rel)
+		case t.collection != "":
+			return nil, nil, fmt.Errorf("relationship %s: %s is a collection of child resources and cannot be referenced", rel, rel.Target)
+		case !hasAttribute(r.info, rel.Argument):
+			return nil, nil, fmt.Errorf("relationship %s: %s has no argument %s", rel, rel.Resource, rel.Argument)
+		case !exportsAttribute(t.info, rel.Attribute):
+			return nil, nil, fmt.Errorf("relationship %s: %s has no attribute %s", rel, rel.Target, rel.Attribute)
+		}
+		r.wire(rel, t)
+	}
+
+	// arguments named after another resource of the module reference it,
+	// e.g. service_plan_id or resource_group_name
+	for i := range resources {
+		r := &resources[i]
+		for _, attr := range r.info.Attributes {
+			if _, ok := r.refs[attr.Name]; ok {
+				continue
+			}
+			for j := range resources {
+				t := &resources[j]
+				if i == j || t.collection != "" {
+					continue
+				}
+				switch attr.Name {
+				case t.info.ShortName + "_id":
+					r.wire(Relationship{r.info.ResourceType, attr.Name, t.info.ResourceType, "id"}, t)
+				case t.info.ShortName + "_name":
+					r.wire(Relationship{r.info.ResourceType, attr.Name, t.info.ResourceType, "name"}, t)
+				}
+			}
+		}
+	}
+
+	// children live in their parent's resource group
+	for i := range resources {
+		r := &resources[i]
+		if r.collection == "" {
+			continue
+		}
+		parent := r.parent(byType)
+		if parent == nil {
+			return nil, nil, fmt.Errorf("%s: no argument references a resource of the module; declare its parent with a relationship", r.info.ResourceType)
+		}
+		if _, wired := r.refs["resource_group_name"]; !wired && hasAttribute(r.info, "resource_group_name") && hasAttribute(parent.info, "resource_group_name") {
+			r.wire(Relationship{r.info.ResourceType, "resource_group_name", parent.info.ResourceType, "resource_group_name"}, parent)
+		}
+	}
+
+	if cycle := findCycle(resources); cycle != "" {
+		return nil, nil, fmt.Errorf("resources reference each other in a cycle: %s", cycle)
+	}
+
+	iface, err := compositeInterface(resources)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resources, iface, nil
+}
+
+// wire sets rel's argument from the target resource.
+func (r *moduleResource) wire(rel Relationship, t *moduleResource) {
+	r.refs[rel.Argument] = fmt.Sprintf("%s[0].%s", t.address(), rel.Attribute)
+	r.wiring[rel.Argument] = rel
+}
+
+// parent returns the resource a child is wired to, preferring a reference
+// by name, which also places the child in the parent's resource group.
+func (r *moduleResource) parent(byType map[string]*moduleResource) *moduleResource {
+	var parent *moduleResource
+	for _, arg := range sortedKeys(r.wiring) {
+		rel := r.wiring[arg]
+		if rel.Attribute == "name" {
+			return byType[rel.Target]
+		}
+		if parent == nil {
+			parent = byType[rel.Target]
+		}
+	}
+	return parent
+}
+
+// pluralise names a collection variable: storage_container → storage_containers.
+func pluralise(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ay") && !strings.HasSuffix(name, "ey"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+func exportsAttribute(info *schema.ResourceInfo, name string) bool {
+	if name == "id" || hasAttribute(info, name) {
+		return true
+	}
+	for _, computed := range info.ComputedOnlyAttrs {
+		if computed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findCycle returns the resources of a reference cycle, e.g.
+// "azurerm_a → azurerm_b → azurerm_a", or "" when there is none.
+func findCycle(resources []moduleResource) string {
+	deps := map[string][]string{}
+	for _, r := range resources {
+		for _, arg := range sortedKeys(r.wiring) {
+			deps[r.info.ResourceType] = append(deps[r.info.ResourceType], r.wiring[arg].Target)
+		}
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(string) string
+	visit = func(n string) string {
+		switch state[n] {
+		case visiting:
+			for i, p := range path {
+				if p == n {
+					return strings.Join(append(path[i:], n), " → ")
+				}
+			}
+		case done:
+			return ""
+		}
+		state[n] = visiting
+		path = append(path, n)
+		for _, d := range deps[n] {
+			if cycle := visit(d); cycle != "" {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = done
+		return ""
+	}
+	for _, r := range resources {
+		if cycle := visit(r.info.ResourceType); cycle != "" {
+			return cycle
+		}
+	}
+	return ""
+}
+
+// compositeInterface builds the ResourceInfo describing the module's
+// inputs. The primary resource's arguments keep their names; the others'
+// are prefixed, and each gets a <short>_name variable like the primary's.
+// A child collection is one optional map block holding its arguments.
+// location and resource_group_name are shared by every resource using them.
+func compositeInterface(resources []moduleResource) (*schema.ResourceInfo, error) {
+	primary := *resources[0].info
+	iface := &primary
+	iface.Attributes, iface.Blocks = nil, nil
+
+	seen := map[string]string{}
+	declare := func(name string, r moduleResource) error {
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("variable %q would be declared for both %s and %s", name, other, r.info.ResourceType)
+		}
+		seen[name] = r.info.ResourceType
+		return nil
+	}
+
+	shared := map[string]bool{}
+	for _, r := range resources {
+		if r.collection != "" {
+			collection := schema.ParsedBlock{Name: r.collection, NestingMode: "map", Blocks: r.info.Blocks}
+			for _, attr := range r.info.Attributes {
+				if _, wired := r.refs[attr.Name]; wired || isStandardVar(attr.Name) {
+					if (attr.Name == "location" || attr.Name == "resource_group_name") && !wired && !shared[attr.Name] {
+						shared[attr.Name] = true
+						iface.Attributes = append(iface.Attributes, attr)
+					}
+					continue
+				}
+				collection.Attributes = append(collection.Attributes, attr)
+			}
+			if err := declare(collection.Name, r); err != nil {
+				return nil, err
+			}
+			iface.Blocks = append(iface.Blocks, collection)
+			continue
+		}
+		for _, attr := range r.info.Attributes {
+			if _, wired := r.refs[attr.Name]; wired {
+				continue
+			}
+			switch {
+			case attr.Name == "location" || attr.Name == "resource_group_name":
+				if shared[attr.Name] {
+					continue
+				}
+				shared[attr.Name] = true
+			case r.prefix != "" && isStandardVar(attr.Name):
+				continue
+			default:
+				attr.Name = r.prefix + attr.Name
+			}
+			if err := declare(getVariableName(attr.Name, iface.ShortName), r); err != nil {
+				return nil, err
+			}
+			iface.Attributes = append(iface.Attributes, attr)
+		}
+		if r.prefix != "" && hasAttribute(r.info, "name") {
+			if _, wired := r.refs["name"]; !wired {
+				name := schema.ParsedAttribute{
+					Name:        r.prefix + "name",
+					TFType:      "string",
+					Description: fmt.Sprintf("Specifies the name of the %s", r.info.DisplayName),
+					Optional:    true,
+				}
+				if err := declare(name.Name, r); err != nil {
+					return nil, err
+				}
+				iface.Attributes = append(iface.Attributes, name)
+			}
+		}
+		for _, block := range r.info.Blocks {
+			block.Name = r.prefix + block.Name
+			if err := declare(block.Name, r); err != nil {
+				return nil, err
+			}
+			iface.Blocks = append(iface.Blocks, block)
+		}
+	}
+	return iface, nil
+}
@@ -0,0 +1,160 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCompositeModule(t *testing.T) {
+	webApp, plan := schematest.LinuxWebAppWithServicePlan()
+	c := Composite{
+		Resources: []*schema.ResourceInfo{webApp, plan},
+		Relationships: []Relationship{
+			{Resource: "azurerm_linux_web_app", Argument: "location", Target: "azurerm_service_plan", Attribute: "location"},
+		},
+	}
+	wiring, err := c.Wiring()
+	require.NoError(t, err)
+	var wired []string
+	for _, w := range wiring {
+		wired = append(wired, w.String())
+	}
+	assert.Equal(t, []string{
+		"azurerm_linux_web_app.location=azurerm_service_plan.location",
+		"azurerm_linux_web_app.service_plan_id=azurerm_service_plan.id",
+	}, wired)
+
+	files, err := GenerateCompositeModule(c, Options{Scenarios: []string{"default", "complete", "disabled"}})
+	require.NoError(t, err)
+
+	main := files["main.tf"]
+	assert.Contains(t, main, `resource "azurerm_linux_web_app" "this"`)
+	assert.Contains(t, main, `resource "azurerm_service_plan" "service_plan"`)
+	assert.Regexp(t, `service_plan_id\s+= azurerm_service_plan.service_plan\[0\].id`, main)
+	assert.Regexp(t, `location\s+= azurerm_service_plan.service_plan\[0\].location`, main)
+	assert.Regexp(t, `sku_name\s+= var.service_plan_sku_name`, main)
+	assert.Contains(t, main, "var.service_plan_name != null ? var.service_plan_name : module.this.id")
+
+	vars := files["variables.tf"]
+	for _, name := range []string{"service_plan_name", "service_plan_os_type", "service_plan_sku_name", "location", "resource_group_name", "site_config"} {
+		assert.Contains(t, vars, `variable "`+name+`"`)
+	}
+	assert.NotContains(t, vars, `variable "service_plan_id"`, "the plan ID is wired, not an input")
+	assert.Contains(t, vars, `contains(["Linux", "Windows", "WindowsContainer"], var.service_plan_os_type)`)
+	assert.Contains(t, files["outputs.tf"], `output "service_plan_id"`)
+	assert.Contains(t, files["tests/default/main.tf"], "service_plan_sku_name")
+	assert.NotContains(t, files["tests/default/main.tf"], "service_plan_id")
+}
+
+func TestGenerateCompositeModule_Rejects(t *testing.T) {
+	webApp, plan := schematest.LinuxWebAppWithServicePlan()
+	for name, c := range map[string]Composite{
+		"no resources": {},
+		"listed twice": {Resources: []*schema.ResourceInfo{webApp, webApp}},
+		"unknown target": {Resources: []*schema.ResourceInfo{webApp, plan}, Relationships: []Relationship{
+			{Resource: "azurerm_linux_web_app", Argument: "service_plan_id", Target: "azurerm_app_service_plan"},
+		}},
+		"unknown argument": {Resources: []*schema.ResourceInfo{webApp, plan}, Relationships: []Relationship{
+			{Resource: "azurerm_linux_web_app", Argument: "plan_id", Target: "azurerm_service_plan"},
+		}},
+		"cycle": {Resources: []*schema.ResourceInfo{webApp, plan}, Relationships: []Relationship{
+			{Resource: "azurerm_service_plan", Argument: "location", Target: "azurerm_linux_web_app", Attribute: "location"},
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := GenerateCompositeModule(c, Options{})
+			assert.Error(t, err)
+		})
+	}
+
+	rel, err := ParseRelationship(" azurerm_linux_web_app.service_plan_id = azurerm_service_plan ")
+	require.NoError(t, err)
+	assert.Equal(t, Relationship{Resource: "azurerm_linux_web_app", Argument: "service_plan_id", Target: "azurerm_service_plan", Attribute: "id"}, rel)
+	_, err = ParseRelationship("azurerm_linux_web_app=azurerm_service_plan.id")
+	assert.Error(t, err)
+}
+
+func TestGenerateCompositeModule_ChildCollections(t *testing.T) {
+	account, container := schematest.StorageAccountWithContainer()
+	c := Composite{Resources: []*schema.ResourceInfo{account}, Children: []*schema.ResourceInfo{container}}
+	wiring, err := c.Wiring()
+	require.NoError(t, err)
+	require.Len(t, wiring, 1)
+	assert.Equal(t, "azurerm_storage_container.storage_account_id=azurerm_storage_account.id", wiring[0].String())
+
+	files, err := GenerateCompositeModule(c, Options{Scenarios: []string{"default", "complete", "disabled"}})
+	require.NoError(t, err)
+
+	main := files["main.tf"]
+	assert.Contains(t, main, `resource "azurerm_storage_container" "storage_container"`)
+	assert.Regexp(t, `for_each\s+= local.enabled \? var.storage_containers : \{\}`, main)
+	assert.Regexp(t, `name\s+= each.key`, main)
+	assert.Regexp(t, `storage_account_id\s+= azurerm_storage_account.this\[0\].id`, main)
+	assert.Regexp(t, `container_access_type\s+= each.value.container_access_type`, main)
+
+	vars := files["variables.tf"]
+	assert.Contains(t, vars, `variable "storage_containers"`)
+	assert.Contains(t, vars, "map(object({")
+	assert.NotContains(t, vars, `variable "storage_container_name"`, "children are named by their map key")
+	assert.NotContains(t, vars, "storage_account_id")
+
+	outputs := files["outputs.tf"]
+	assert.Contains(t, outputs, `output "storage_containers"`)
+	assert.Contains(t, outputs, "for k, v in azurerm_storage_container.storage_container")
+	assert.Regexp(t, `resource_manager_id\s+= v.resource_manager_id`, outputs)
+}
+
+func TestGenerateCompositeModule_ChildWiredByName(t *testing.T) {
+	vnet := &schema.ResourceInfo{
+		ResourceType: "azurerm_virtual_network",
+		ShortName:    "virtual_network",
+		ModuleName:   "expn-tf-azure-virtual-network",
+		DisplayName:  "Virtual Network",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "address_space", TFType: "list(string)", Required: true},
+			{Name: "tags", TFType: "map(string)", Optional: true},
+		},
+	}
+	subnet := &schema.ResourceInfo{
+		ResourceType: "azurerm_subnet",
+		ShortName:    "subnet",
+		DisplayName:  "Subnet",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "virtual_network_name", TFType: "string", Required: true},
+			{Name: "address_prefixes", TFType: "list(string)", Required: true},
+		},
+	}
+	c := Composite{Resources: []*schema.ResourceInfo{vnet}, Children: []*schema.ResourceInfo{subnet}}
+	files, err := GenerateCompositeModule(c, Options{})
+	require.NoError(t, err)
+
+	main := files["main.tf"]
+	assert.Regexp(t, `virtual_network_name\s+= azurerm_virtual_network.this\[0\].name`, main)
+	assert.Regexp(t, `resource_group_name\s+= azurerm_virtual_network.this\[0\].resource_group_name`, main, "subnets live in the network's resource group")
+	assert.Contains(t, files["variables.tf"], `variable "subnets"`)
+
+	// a child must hang off a resource of the module, and cannot be referenced itself
+	_, err = GenerateCompositeModule(Composite{
+		Resources: []*schema.ResourceInfo{{ResourceType: "azurerm_public_ip", ShortName: "public_ip", DisplayName: "Public IP",
+			Attributes: []schema.ParsedAttribute{{Name: "name", TFType: "string", Required: true}}}},
+		Children: []*schema.ResourceInfo{subnet},
+	}, Options{})
+	assert.ErrorContains(t, err, "azurerm_subnet")
+	_, err = GenerateCompositeModule(Composite{
+		Resources: []*schema.ResourceInfo{vnet},
+		Children:  []*schema.ResourceInfo{subnet},
+		Relationships: []Relationship{
+			{Resource: "azurerm_virtual_network", Argument: "name", Target: "azurerm_subnet", Attribute: "name"},
+		},
+	}, Options{})
+	assert.ErrorContains(t, err, "collection of child resources")
+}
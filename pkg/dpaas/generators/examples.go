@@ -0,0 +1,531 @@
+package generators
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+)
+
+// exampleEngine picks the example values written into generated tests so that
+// they are consistent with the resource, with each other and with what the
+// provider validates. For each attribute it tries, in order:
+//
+//  1. the resource's SKU profile (values known to work together)
+//  2. a fixture reference, when the scenario creates its prerequisites
+//  3. the ID format of the referenced resource type for "_id" attributes
+//  4. region-aware values for locations and zones
+//  5. the allowed enum values, preferring secure and general-purpose ones
+//  6. name-based patterns, adjusted to the constraints stated in the docs
+type exampleEngine struct {
+	resourceType string
+	defaults     ScenarioDefaults
+	pack         *TemplatePack
+	region       regionInfo
+	profile      skuProfile
+	fixtures     map[string]bool // fixtures referenced so far; nil when fixtures are off
+}
+
+// newExampleEngine expects resolved options.
+func newExampleEngine(info *schema.ResourceInfo, opts Options) *exampleEngine {
+	return &exampleEngine{
+		resourceType: info.ResourceType,
+		defaults:     opts.Defaults,
+		pack:         opts.Pack,
+		region:       lookupRegion(opts.Defaults.Location),
+		profile:      skuProfiles[info.ResourceType],
+	}
+}
+
+// newFixtureEngine is newExampleEngine for a scenario that creates its
+// prerequisites: references to fixture types resolve to the fixtures.
+func newFixtureEngine(info *schema.ResourceInfo, opts Options) *exampleEngine {
+	e := newExampleEngine(info, opts)
+	e.fixtures = map[string]bool{}
+	return e
+}
+
+// withValue returns a copy of the engine that writes value for the attribute
+// at path, ahead of everything else it would pick.
+func (e *exampleEngine) withValue(path, value string) *exampleEngine {
+	c := *e
+	c.profile.Values = map[string]string{path: value}
+	for k, v := range e.profile.Values {
+		if k != path {
+			c.profile.Values[k] = v
+		}
+	}
+	return &c
+}
+
+// value returns the HCL literal for the attribute at a schema path such as
+// "site_config.minimum_tls_version".
+func (e *exampleEngine) value(path string, attr schema.ParsedAttribute) string {
+	if v, ok := e.profile.Values[path]; ok {
+		return v
+	}
+	if v, ok := e.regionValue(attr); ok {
+		return v
+	}
+
+	switch {
+	case attr.TFType == "bool":
+		return "true"
+	case attr.TFType == "number":
+		return e.numberValue(attr)
+	case attr.TFType == "string":
+		return e.stringValue(attr)
+	case strings.HasPrefix(attr.TFType, "list(") || strings.HasPrefix(attr.TFType, "set("):
+		if strings.HasSuffix(attr.Name, "_ids") && strings.Contains(attr.TFType, "string") {
+			if ref, ok := e.fixtureRef(attr.Name); ok {
+				return fmt.Sprintf("[%s]", ref)
+			}
+			id, _ := schema.AzureResourceID(e.resourceType, attr.Name, e.defaults.SubscriptionID, e.defaults.ResourceGroupName)
+			return fmt.Sprintf("[%s]", hclQuote(id))
+		}
+		return "[]"
+	case strings.HasPrefix(attr.TFType, "map("):
+		return "{}"
+	}
+	return "null"
+}
+
+func (e *exampleEngine) stringValue(attr schema.ParsedAttribute) string {
+	if ref, ok := e.fixtureRef(attr.Name); ok {
+		return ref
+	}
+	if strings.HasSuffix(attr.Name, "_id") {
+		id, _ := schema.AzureResourceID(e.resourceType, attr.Name, e.defaults.SubscriptionID, e.defaults.ResourceGroupName)
+		return hclQuote(id)
+	}
+	if len(attr.EnumValues) > 0 {
+		return hclQuote(preferredEnumValue(attr.EnumValues))
+	}
+
+	c := parseDocsConstraints(attr.Description)
+	if c.Format != "" {
+		return hclQuote(c.Format)
+	}
+	guess := generateStringValue(attr.Name)
+	s, err := strconv.Unquote(guess)
+	if err != nil {
+		return guess
+	}
+	return hclQuote(c.applyToString(s))
+}
+
+func (e *exampleEngine) numberValue(attr schema.ParsedAttribute) string {
+	guess := generateNumberValue(attr.Name)
+	n, err := strconv.Atoi(guess)
+	if err != nil {
+		return guess
+	}
+	return strconv.Itoa(parseDocsConstraints(attr.Description).applyToNumber(n))
+}
+
+// ---------------------------------------------------------------------------
+// Enum preference
+// ---------------------------------------------------------------------------
+
+// preferredEnums are chosen over the first listed value when allowed: secure
+// settings, general-purpose SKUs and the defaults most resources accept.
+var preferredEnums = []string{
+	"TLS1_2", "1.2", "Tls12",
+	"Standard", "standard", "Standard_LRS", "LRS", "StorageV2", "Hot",
+	"SystemAssigned", "Static", "IPv4", "Regional", "Session",
+	"PerGB2018", "GlobalDocumentDB", "Windows", "Linux",
+	"Disabled", "Deny", "None",
+}
+
+// discouragedEnums are only used when nothing else is allowed.
+var discouragedEnums = map[string]bool{
+	"TLS1_0": true, "TLS1_1": true, "1.0": true, "1.1": true, "Tls10": true, "Tls11": true,
+	"Free": true, "Shared": true, "Dynamic": true,
+}
+
+func preferredEnumValue(values []string) string {
+	allowed := map[string]bool{}
+	for _, v := range values {
+		allowed[v] = true
+	}
+	for _, p := range preferredEnums {
+		if allowed[p] {
+			return p
+		}
+	}
+	for _, v := range values {
+		if !discouragedEnums[v] {
+			return v
+		}
+	}
+	return values[0]
+}
+
+// ---------------------------------------------------------------------------
+// Docs constraints
+// ---------------------------------------------------------------------------
+
+// docsConstraints are the limits an attribute description states.
+type docsConstraints struct {
+	Min, Max       *int // numeric range
+	MinLen, MaxLen int  // string length, 0 when unstated
+	MultipleOf     int
+	LowerAlnum     bool   // lowercase letters and numbers only
+	Alnum          bool   // letters and numbers only
+	Format         string // a fixed example for a stated format
+}
+
+var (
+	lengthRangePattern = regexp.MustCompile(`(?i)between (\d+) and (\d+) characters|(\d+)\s*(?:-|to)\s*(\d+) characters`)
+	numberRangePattern = regexp.MustCompile(`(?i)between (-?\d+) and (-?\d+)|from (-?\d+) to (-?\d+)|range (-?\d+)\s*-\s*(-?\d+)`)
+	minPattern         = regexp.MustCompile(`(?i)(?:at least|minimum(?: value)? of|greater than or equal to|no less than) (-?\d+)`)
+	maxPattern         = regexp.MustCompile(`(?i)(?:at most|maximum(?: value)? of|less than or equal to|up to|no more than) (-?\d+)`)
+	multiplePattern    = regexp.MustCompile(`(?i)multiple of (\d+)`)
+	lowerAlnumPattern  = regexp.MustCompile(`(?i)(?:only )?lower ?case (?:letters and numbers|alphanumeric)`)
+	alnumPattern       = regexp.MustCompile(`(?i)(?:only )?alphanumeric`)
+)
+
+// docsFormats map phrases in descriptions to an example in that format.
+var docsFormats = []struct{ phrase, example string }{
+	{"ISO 8601 duration", "PT1H"},
+	{"ISO8601 duration", "PT1H"},
+	{"RFC3339", "2030-01-01T00:00:00Z"},
+	{"RFC 3339", "2030-01-01T00:00:00Z"},
+	{"HH:MM", "00:00"},
+	{"hh:mm", "00:00"},
+	{"CIDR", "10.0.1.0/24"},
+	{"base64", "ZXhhbXBsZQ=="},
+}
+
+func parseDocsConstraints(desc string) docsConstraints {
+	var c docsConstraints
+	if desc == "" {
+		return c
+	}
+	atoi := func(s string) int { n, _ := strconv.Atoi(s); return n }
+
+	if m := lengthRangePattern.FindStringSubmatch(desc); m != nil {
+		if m[1] != "" {
+			c.MinLen, c.MaxLen = atoi(m[1]), atoi(m[2])
+		} else {
+			c.MinLen, c.MaxLen = atoi(m[3]), atoi(m[4])
+		}
+		desc = strings.Replace(desc, m[0], "", 1)
+	}
+	if m := numberRangePattern.FindStringSubmatch(desc); m != nil {
+		for i := 1; i+1 < len(m); i += 2 {
+			if m[i] != "" {
+				lo, hi := atoi(m[i]), atoi(m[i+1])
+				c.Min, c.Max = &lo, &hi
+				break
+			}
+		}
+	}
+	if c.Min == nil {
+		if m := minPattern.FindStringSubmatch(desc); m != nil {
+			lo := atoi(m[1])
+			c.Min = &lo
+		}
+	}
+	if c.Max == nil {
+		if m := maxPattern.FindStringSubmatch(desc); m != nil {
+			hi := atoi(m[1])
+			c.Max = &hi
+		}
+	}
+	if m := multiplePattern.FindStringSubmatch(desc); m != nil {
+		c.MultipleOf = atoi(m[1])
+	}
+	c.LowerAlnum = lowerAlnumPattern.MatchString(desc)
+	c.Alnum = !c.LowerAlnum && alnumPattern.MatchString(desc)
+	for _, f := range docsFormats {
+		if strings.Contains(desc, f.phrase) {
+			c.Format = f.example
+			break
+		}
+	}
+	return c
+}
+
+// applyToNumber moves n into the stated range and onto the stated multiple.
+func (c docsConstraints) applyToNumber(n int) int {
+	if c.Min != nil && n < *c.Min {
+		n = *c.Min
+	}
+	if c.Max != nil && n > *c.Max {
+		n = *c.Max
+	}
+	if c.MultipleOf > 0 && n%c.MultipleOf != 0 {
+		n += c.MultipleOf - n%c.MultipleOf
+		if c.Max != nil && n > *c.Max {
+			n -= c.MultipleOf
+		}
+	}
+	return n
+}
+
+// applyToString adjusts a name-like example to the stated character set and length.
+func (c docsConstraints) applyToString(s string) string {
+	if c.LowerAlnum || c.Alnum {
+		var b strings.Builder
+		for _, r := range s {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				b.WriteRune(r)
+			}
+		}
+		s = b.String()
+		if c.LowerAlnum {
+			s = strings.ToLower(s)
+		}
+	}
+	if c.MaxLen > 0 && len(s) > c.MaxLen {
+		s = s[:c.MaxLen]
+	}
+	for len(s) < c.MinLen {
+		s += "0"
+	}
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// Regions
+// ---------------------------------------------------------------------------
+
+// regionInfo records what tests depend on about an Azure region.
+type regionInfo struct {
+	Name  string
+	Zones bool   // availability zones are supported
+	Pair  string // paired region, used for secondary and failover locations
+}
+
+var regions = []regionInfo{
+	{"East US", true, "West US"},
+	{"East US 2", true, "Central US"},
+	{"Central US", true, "East US 2"},
+	{"West US", false, "East US"},
+	{"West US 2", true, "West Central US"},
+	{"West US 3", true, "East US"},
+	{"North Central US", false, "South Central US"},
+	{"South Central US", true, "North Central US"},
+	{"West Central US", false, "West US 2"},
+	{"Canada Central", true, "Canada East"},
+	{"Brazil South", true, "South Central US"},
+	{"North Europe", true, "West Europe"},
+	{"West Europe", true, "North Europe"},
+	{"UK South", true, "UK West"},
+	{"UK West", false, "UK South"},
+	{"France Central", true, "France South"},
+	{"Germany West Central", true, "Germany North"},
+	{"Switzerland North", true, "Switzerland West"},
+	{"Norway East", true, "Norway West"},
+	{"Sweden Central", true, "Sweden South"},
+	{"Southeast Asia", true, "East Asia"},
+	{"East Asia", true, "Southeast Asia"},
+	{"Australia East", true, "Australia Southeast"},
+	{"Japan East", true, "Japan West"},
+	{"Korea Central", true, "Korea South"},
+	{"Central India", true, "South India"},
+	{"South Africa North", true, "South Africa West"},
+	{"UAE North", true, "UAE Central"},
+}
+
+// lookupRegion accepts display names ("East US 2") and programmatic names
+// ("eastus2"). Unknown regions are assumed to have no zones.
+func lookupRegion(location string) regionInfo {
+	key := strings.ToLower(strings.ReplaceAll(location, " ", ""))
+	for _, r := range regions {
+		if strings.ToLower(strings.ReplaceAll(r.Name, " ", "")) == key {
+			return r
+		}
+	}
+	return regionInfo{Name: location, Pair: location}
+}
+
+// regionValue handles locations, zones and zone redundancy, which must agree
+// with the scenario's region.
+func (e *exampleEngine) regionValue(attr schema.ParsedAttribute) (string, bool) {
+	name := attr.Name
+	switch {
+	case name == "location" && attr.TFType == "string":
+		return hclQuote(e.region.Name), true
+	case strings.HasSuffix(name, "_location") && attr.TFType == "string",
+		name == "secondary_location", name == "failover_location":
+		return hclQuote(e.region.Pair), true
+	case name == "edge_zone":
+		// edge zones exist in a handful of metros only
+		return "null", true
+	case name == "zones" || name == "availability_zones":
+		if !e.region.Zones || !e.profile.ZoneCapable {
+			return "null", true
+		}
+		if strings.HasPrefix(attr.TFType, "list(") || strings.HasPrefix(attr.TFType, "set(") {
+			return `["1", "2", "3"]`, true
+		}
+	case name == "zone" && attr.TFType == "string":
+		if !e.region.Zones || !e.profile.ZoneCapable {
+			return "null", true
+		}
+		return `"1"`, true
+	case attr.TFType == "bool" && (strings.Contains(name, "zone_redundan") || strings.Contains(name, "zone_balancing")):
+		return strconv.FormatBool(e.region.Zones && e.profile.ZoneCapable), true
+	}
+	return "", false
+}
+
+// ---------------------------------------------------------------------------
+// SKU profiles
+// ---------------------------------------------------------------------------
+
+// skuProfile is a set of values known to be valid together for one resource
+// type, keyed by schema path. "null" leaves an argument unset where the
+// chosen SKU does not support it.
+type skuProfile struct {
+	ZoneCapable bool // the chosen SKU can be zonal or zone redundant
+	Values      map[string]string
+}
+
+var skuProfiles = map[string]skuProfile{
+	"azurerm_storage_account": {ZoneCapable: true, Values: map[string]string{
+		"account_tier":                      `"Standard"`,
+		"account_kind":                      `"StorageV2"`,
+		"account_replication_type":          `"ZRS"`,
+		"access_tier":                       `"Hot"`,
+		"min_tls_version":                   `"TLS1_2"`,
+		"is_hns_enabled":                    "false",
+		"nfsv3_enabled":                     "false",
+		"sftp_enabled":                      "false",
+		"local_user_enabled":                "false",
+		"shared_access_key_enabled":         "false",
+		"allow_nested_items_to_be_public":   "false",
+		"public_network_access_enabled":     "false",
+		"infrastructure_encryption_enabled": "true",
+		"queue_encryption_key_type":         `"Service"`,
+		"table_encryption_key_type":         `"Service"`,
+		"large_file_share_enabled":          "true",
+		"dns_endpoint_type":                 `"Standard"`,
+	}},
+	"azurerm_service_plan": {ZoneCapable: true, Values: map[string]string{
+		"os_type":                         `"Windows"`,
+		"sku_name":                        `"P1v3"`,
+		"worker_count":                    "3",
+		"per_site_scaling_enabled":        "false",
+		"maximum_elastic_worker_count":    "null",
+		"premium_plan_auto_scale_enabled": "false",
+		"app_service_environment_id":      "null",
+	}},
+	"azurerm_windows_web_app": {Values: map[string]string{
+		"https_only":                          "true",
+		"public_network_access_enabled":       "false",
+		"client_certificate_enabled":          "false",
+		"client_certificate_mode":             `"Required"`,
+		"site_config.minimum_tls_version":     `"1.2"`,
+		"site_config.scm_minimum_tls_version": `"1.2"`,
+		"site_config.ftps_state":              `"Disabled"`,
+		"site_config.always_on":               "true",
+		"site_config.http2_enabled":           "true",
+	}},
+	"azurerm_linux_web_app": {Values: map[string]string{
+		"https_only":                          "true",
+		"public_network_access_enabled":       "false",
+		"client_certificate_enabled":          "false",
+		"client_certificate_mode":             `"Required"`,
+		"site_config.minimum_tls_version":     `"1.2"`,
+		"site_config.scm_minimum_tls_version": `"1.2"`,
+		"site_config.ftps_state":              `"Disabled"`,
+		"site_config.always_on":               "true",
+		"site_config.http2_enabled":           "true",
+	}},
+	"azurerm_key_vault": {Values: map[string]string{
+		"sku_name":                      `"standard"`,
+		"soft_delete_retention_days":    "90",
+		"purge_protection_enabled":      "true",
+		"enable_rbac_authorization":     "true",
+		"rbac_authorization_enabled":    "true",
+		"public_network_access_enabled": "false",
+	}},
+	"azurerm_kubernetes_cluster": {ZoneCapable: true, Values: map[string]string{
+		"sku_tier":                               `"Standard"`,
+		"dns_prefix":                             `"example-aks"`,
+		"dns_prefix_private_cluster":             "null",
+		"kubernetes_version":                     "null",
+		"private_cluster_enabled":                "true",
+		"default_node_pool.vm_size":              `"Standard_D2s_v3"`,
+		"default_node_pool.node_count":           "3",
+		"default_node_pool.auto_scaling_enabled": "false",
+		"default_node_pool.min_count":            "null",
+		"default_node_pool.max_count":            "null",
+		"default_node_pool.os_disk_type":         `"Managed"`,
+		"default_node_pool.os_sku":               `"AzureLinux"`,
+		"default_node_pool.type":                 `"VirtualMachineScaleSets"`,
+	}},
+	"azurerm_redis_cache": {Values: map[string]string{
+		"sku_name":                  `"Standard"`,
+		"family":                    `"C"`,
+		"capacity":                  "1",
+		"minimum_tls_version":       `"1.2"`,
+		"non_ssl_port_enabled":      "false",
+		"shard_count":               "null",
+		"replicas_per_master":       "null",
+		"replicas_per_primary":      "null",
+		"subnet_id":                 "null",
+		"private_static_ip_address": "null",
+	}},
+	"azurerm_mssql_server": {Values: map[string]string{
+		"version":                       `"12.0"`,
+		"minimum_tls_version":           `"1.2"`,
+		"administrator_login":           `"sqladminuser"`,
+		"public_network_access_enabled": "false",
+	}},
+	"azurerm_mssql_database": {ZoneCapable: true, Values: map[string]string{
+		"sku_name":                    `"GP_Gen5_2"`,
+		"max_size_gb":                 "32",
+		"license_type":                `"LicenseIncluded"`,
+		"read_scale":                  "false",
+		"read_replica_count":          "null",
+		"min_capacity":                "null",
+		"auto_pause_delay_in_minutes": "null",
+		"elastic_pool_id":             "null",
+		"storage_account_type":        `"Zone"`,
+		"create_mode":                 `"Default"`,
+		"creation_source_database_id": "null",
+		"restore_point_in_time":       "null",
+		"recover_database_id":         "null",
+		"restore_dropped_database_id": "null",
+	}},
+	"azurerm_public_ip": {ZoneCapable: true, Values: map[string]string{
+		"sku":                 `"Standard"`,
+		"sku_tier":            `"Regional"`,
+		"allocation_method":   `"Static"`,
+		"ip_version":          `"IPv4"`,
+		"public_ip_prefix_id": "null",
+	}},
+	"azurerm_container_registry": {ZoneCapable: true, Values: map[string]string{
+		"sku":                           `"Premium"`,
+		"admin_enabled":                 "false",
+		"public_network_access_enabled": "false",
+		"anonymous_pull_enabled":        "false",
+		"data_endpoint_enabled":         "true",
+	}},
+	"azurerm_log_analytics_workspace": {Values: map[string]string{
+		"sku":                                `"PerGB2018"`,
+		"retention_in_days":                  "30",
+		"daily_quota_gb":                     "-1",
+		"reservation_capacity_in_gb_per_day": "null",
+	}},
+	"azurerm_cosmosdb_account": {ZoneCapable: true, Values: map[string]string{
+		"offer_type":                           `"Standard"`,
+		"kind":                                 `"GlobalDocumentDB"`,
+		"consistency_policy.consistency_level": `"Session"`,
+		"consistency_policy.max_interval_in_seconds": "null",
+		"consistency_policy.max_staleness_prefix":    "null",
+		"geo_location.failover_priority":             "0",
+		"public_network_access_enabled":              "false",
+	}},
+	"azurerm_bastion_host": {Values: map[string]string{
+		"sku":                `"Standard"`,
+		"scale_units":        "2",
+		"virtual_network_id": "null", // Developer SKU only
+	}},
+}
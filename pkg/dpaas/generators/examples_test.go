@@ -0,0 +1,43 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModule_CompleteScenarioExampleValues(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.Attributes = append(info.Attributes,
+		schema.ParsedAttribute{Name: "minimum_tls_version", TFType: "string", Optional: true, EnumValues: []string{"1.0", "1.1", "1.2"}},
+		schema.ParsedAttribute{Name: "tenant_id", TFType: "string", Optional: true},
+		schema.ParsedAttribute{Name: "virtual_network_subnet_id", TFType: "string", Optional: true},
+		schema.ParsedAttribute{Name: "key_vault_reference_identity_id", TFType: "string", Optional: true},
+		schema.ParsedAttribute{Name: "zone_balancing_enabled", TFType: "bool", Optional: true},
+		schema.ParsedAttribute{Name: "retention_in_days", TFType: "number", Optional: true, Description: "The retention in days. Possible values are between 90 and 730."},
+	)
+	files, err := GenerateModuleWithOptions(info, Options{
+		Scenarios: []string{"complete"},
+		Defaults:  ScenarioDefaults{Location: "westus", SubscriptionID: "00000000-0000-0000-0000-000000000001"},
+	})
+	require.NoError(t, err)
+
+	main := files["tests/complete/main.tf"]
+	_, diags := hclsyntax.ParseConfig([]byte(main), "main.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	scope := "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/eits-Sandbox-mspsandbox-BU-07959a-rg/providers/"
+	assert.Regexp(t, `minimum_tls_version\s+= "1.2"`, main)
+	assert.Regexp(t, `tenant_id\s+= "00000000-0000-0000-0000-000000000000"`, main)
+	assert.Contains(t, main, `"`+scope+`Microsoft.Network/virtualNetworks/example-vnet/subnets/example-subnet"`)
+	assert.Contains(t, main, `"`+scope+`Microsoft.Web/serverFarms/example-asp"`)
+	assert.Contains(t, main, `"`+scope+`Microsoft.ManagedIdentity/userAssignedIdentities/example-uai"`)
+	assert.Regexp(t, `zone_balancing_enabled\s+= false`, main, "West US has no availability zones")
+	assert.Regexp(t, `retention_in_days\s+= 90`, main)
+	assert.NotContains(t, main, "Microsoft.Resources/resources")
+}
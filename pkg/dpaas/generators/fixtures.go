@@ -0,0 +1,153 @@
+package generators
+
+import (
+	"sort"
+	"strings"
+)
+
+// fixture is a prerequisite resource a scenario creates in fixtures.tf so
+// that the module call can reference it instead of a placeholder ID.
+type fixture struct {
+	requires []string
+	config   string // HCL with {label} and {location} placeholders
+}
+
+// fixtures are keyed by the referenced type as it appears in attribute
+// names ("subnet" for subnet_id). Every fixture lives in the fixture
+// resource group, which is always created.
+var fixtures = map[string]fixture{
+	"resource_group": {config: `resource "azurerm_resource_group" "fixture" {
+  name     = "rg-dpaas-{label}-${random_string.fixture.result}"
+  location = {location}
+}
+`},
+	"virtual_network": {config: `resource "azurerm_virtual_network" "fixture" {
+  name                = "vnet-dpaas-{label}"
+  location            = azurerm_resource_group.fixture.location
+  resource_group_name = azurerm_resource_group.fixture.name
+  address_space       = ["10.0.0.0/16"]
+}
+`},
+	"subnet": {requires: []string{"virtual_network"}, config: `resource "azurerm_subnet" "fixture" {
+  name                 = "snet-dpaas-{label}"
+  resource_group_name  = azurerm_resource_group.fixture.name
+  virtual_network_name = azurerm_virtual_network.fixture.name
+  address_prefixes     = ["10.0.1.0/24"]
+}
+`},
+	"key_vault": {config: `data "azurerm_client_config" "fixture" {}
+
+resource "azurerm_key_vault" "fixture" {
+  name                       = "kv-dpaas-${random_string.fixture.result}"
+  location                   = azurerm_resource_group.fixture.location
+  resource_group_name        = azurerm_resource_group.fixture.name
+  tenant_id                  = data.azurerm_client_config.fixture.tenant_id
+  sku_name                   = "standard"
+  enable_rbac_authorization  = true
+  soft_delete_retention_days = 7
+}
+`},
+	"log_analytics_workspace": {config: `resource "azurerm_log_analytics_workspace" "fixture" {
+  name                = "log-dpaas-{label}"
+  location            = azurerm_resource_group.fixture.location
+  resource_group_name = azurerm_resource_group.fixture.name
+  sku                 = "PerGB2018"
+  retention_in_days   = 30
+}
+`},
+}
+
+// fixtureOrder is the order fixtures are written in.
+var fixtureOrder = []string{"resource_group", "virtual_network", "subnet", "key_vault", "log_analytics_workspace"}
+
+// fixtureAddresses maps each fixture to its resource address.
+var fixtureAddresses = map[string]string{
+	"resource_group":          "azurerm_resource_group.fixture",
+	"virtual_network":         "azurerm_virtual_network.fixture",
+	"subnet":                  "azurerm_subnet.fixture",
+	"key_vault":               "azurerm_key_vault.fixture",
+	"log_analytics_workspace": "azurerm_log_analytics_workspace.fixture",
+}
+
+// fixtureTypes are the fixture keys, longest first, so that
+// "log_analytics_workspace" wins over a shorter suffix.
+var fixtureTypes = func() []string {
+	keys := make([]string, 0, len(fixtureAddresses))
+	for k := range fixtureAddresses {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	return keys
+}()
+
+// fixtureRef returns the fixture expression for an argument that references
+// a fixture type, e.g. azurerm_subnet.fixture.id for "subnet_id" or
+// azurerm_virtual_network.fixture.name for "virtual_network_name", and
+// records the fixture as used. It returns false when fixtures are off or the
+// argument references nothing a fixture provides.
+func (e *exampleEngine) fixtureRef(attrName string) (string, bool) {
+	if e.fixtures == nil {
+		return "", false
+	}
+	var name, output string
+	switch {
+	case strings.HasSuffix(attrName, "_id"):
+		name, output = strings.TrimSuffix(attrName, "_id"), "id"
+	case strings.HasSuffix(attrName, "_ids"):
+		name, output = strings.TrimSuffix(attrName, "_ids"), "id"
+	case strings.HasSuffix(attrName, "_name"):
+		name, output = strings.TrimSuffix(attrName, "_name"), "name"
+	default:
+		return "", false
+	}
+	for _, t := range fixtureTypes {
+		// names must match exactly; "storage_account_name" is not a resource group
+		if name == t || (output == "id" && strings.HasSuffix(name, "_"+t)) {
+			e.useFixture(t)
+			return fixtureAddresses[t] + "." + output, true
+		}
+	}
+	return "", false
+}
+
+func (e *exampleEngine) useFixture(name string) {
+	e.fixtures[name] = true
+	for _, r := range fixtures[name].requires {
+		e.useFixture(r)
+	}
+}
+
+// resourceGroupName is the module call's resource_group_name: the fixture
+// resource group when fixtures are on, otherwise the scenario default.
+func (e *exampleEngine) resourceGroupName() string {
+	if ref, ok := e.fixtureRef("resource_group_name"); ok {
+		return ref
+	}
+	return hclQuote(e.defaults.ResourceGroupName)
+}
+
+// generateFixturesTf renders fixtures.tf for the fixtures a scenario's
+// module call used. It returns "" when fixtures are off.
+func generateFixturesTf(e *exampleEngine, label string) string {
+	if e.fixtures == nil {
+		return ""
+	}
+	e.useFixture("resource_group")
+	r := strings.NewReplacer("{label}", label, "{location}", hclQuote(e.defaults.Location))
+
+	var b strings.Builder
+	b.WriteString("# Prerequisite resources for this scenario. The module call references\n")
+	b.WriteString("# them instead of placeholder IDs, so the scenario can be applied.\n\n")
+	b.WriteString("resource \"random_string\" \"fixture\" {\n")
+	b.WriteString("  length  = 6\n")
+	b.WriteString("  special = false\n")
+	b.WriteString("  upper   = false\n")
+	b.WriteString("}\n")
+	for _, name := range fixtureOrder {
+		if e.fixtures[name] {
+			b.WriteString("\n")
+			b.WriteString(r.Replace(fixtures[name].config))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModuleWithOptions_Fixtures(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.Attributes = append(info.Attributes,
+		schema.ParsedAttribute{Name: "virtual_network_subnet_id", TFType: "string", Required: true},
+		schema.ParsedAttribute{Name: "key_vault_reference_identity_id", TFType: "string", Optional: true},
+		schema.ParsedAttribute{Name: "log_analytics_workspace_id", TFType: "string", Optional: true},
+	)
+	files, err := GenerateModuleWithOptions(info, Options{
+		Scenarios: []string{"default", "complete", "disabled"},
+		Fixtures:  true,
+	})
+	require.NoError(t, err)
+
+	for _, path := range []string{"tests/default/fixtures.tf", "tests/complete/fixtures.tf"} {
+		_, diags := hclsyntax.ParseConfig([]byte(files[path]), path, hcl.InitialPos)
+		require.False(t, diags.HasErrors(), diags.Error())
+		assert.Contains(t, files[path], `resource "azurerm_resource_group" "fixture"`)
+		assert.Contains(t, files[path], `resource "azurerm_subnet" "fixture"`)
+		assert.Contains(t, files[path], `resource "azurerm_virtual_network" "fixture"`, "the subnet needs its network")
+	}
+	assert.NotContains(t, files["tests/default/fixtures.tf"], "azurerm_log_analytics_workspace", "optional arguments are not set")
+	assert.Contains(t, files["tests/complete/fixtures.tf"], `resource "azurerm_log_analytics_workspace" "fixture"`)
+	assert.NotContains(t, files["tests/complete/fixtures.tf"], "azurerm_key_vault", "an identity is not a Key Vault")
+	assert.NotContains(t, files, "tests/disabled/fixtures.tf")
+
+	main := files["tests/complete/main.tf"]
+	assert.Regexp(t, `resource_group_name\s+= azurerm_resource_group.fixture.name`, main)
+	assert.Regexp(t, `virtual_network_subnet_id\s+= azurerm_subnet.fixture.id`, main)
+	assert.Regexp(t, `log_analytics_workspace_id\s+= azurerm_log_analytics_workspace.fixture.id`, main)
+	assert.Contains(t, main, "Microsoft.ManagedIdentity/userAssignedIdentities")
+	assert.Contains(t, files["tests/disabled/main.tf"], "Microsoft.Network/virtualNetworks/example-vnet/subnets/example-subnet")
+}
@@ -0,0 +1,332 @@
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ResourceState is the recorded state of one resource instance, read from
+// `terraform show -json` output or a state file.
+type ResourceState struct {
+	Address    string         // e.g. "azurerm_storage_account.logs"
+	Type       string         // e.g. "azurerm_storage_account"
+	Attributes map[string]any // attribute values as recorded in the state
+}
+
+// ID returns the Azure resource ID the resource is imported by.
+func (s *ResourceState) ID() string {
+	id, _ := s.Attributes["id"].(string)
+	return id
+}
+
+// showJSON is the part of `terraform show -json` output read here.
+type showJSON struct {
+	Values *struct {
+		RootModule showModule `json:"root_module"`
+	} `json:"values"`
+}
+
+type showModule struct {
+	Resources []struct {
+		Address string         `json:"address"`
+		Mode    string         `json:"mode"`
+		Type    string         `json:"type"`
+		Values  map[string]any `json:"values"`
+	} `json:"resources"`
+	ChildModules []showModule `json:"child_modules"`
+}
+
+// stateFile is the part of a version 4 state file read here.
+type stateFile struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   any            `json:"index_key"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// ReadResourceState returns the managed resource of type resourceType from
+// raw, either `terraform show -json` output or a state file. address picks
+// the resource when the state holds several of that type.
+func ReadResourceState(raw []byte, resourceType, address string) (*ResourceState, error) {
+	var candidates []*ResourceState
+
+	var show showJSON
+	if err := json.Unmarshal(raw, &show); err != nil {
+		return nil, fmt.Errorf("parse state: %w", err)
+	}
+	var state stateFile
+	_ = json.Unmarshal(raw, &state)
+
+	switch {
+	case show.Values != nil:
+		var walk func(m showModule)
+		walk = func(m showModule) {
+			for _, r := range m.Resources {
+				if r.Mode == "managed" && r.Type == resourceType {
+					candidates = append(candidates, &ResourceState{Address: r.Address, Type: r.Type, Attributes: r.Values})
+				}
+			}
+			for _, child := range m.ChildModules {
+				walk(child)
+			}
+		}
+		walk(show.Values.RootModule)
+	case state.Version > 0:
+		for _, r := range state.Resources {
+			if r.Mode != "managed" || r.Type != resourceType {
+				continue
+			}
+			base := r.Type + "." + r.Name
+			if r.Module != "" {
+				base = r.Module + "." + base
+			}
+			for _, inst := range r.Instances {
+				addr := base
+				switch key := inst.IndexKey.(type) {
+				case float64:
+					addr += fmt.Sprintf("[%d]", int(key))
+				case string:
+					addr += fmt.Sprintf("[%q]", key)
+				}
+				candidates = append(candidates, &ResourceState{Address: addr, Type: r.Type, Attributes: inst.Attributes})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("neither `terraform show -json` output nor a state file")
+	}
+
+	var addresses []string
+	for _, c := range candidates {
+		if address != "" && c.Address == address {
+			return c, nil
+		}
+		addresses = append(addresses, c.Address)
+	}
+	switch {
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("no %s in the state", resourceType)
+	case address != "":
+		return nil, fmt.Errorf("%s is not in the state; found %s", address, strings.Join(addresses, ", "))
+	case len(candidates) > 1:
+		return nil, fmt.Errorf("the state has %d %s resources (%s); pick one by address", len(candidates), resourceType, strings.Join(addresses, ", "))
+	}
+	return candidates[0], nil
+}
+
+// GenerateImport maps a deployed resource onto the module's variables for
+// adoption. terraform.tfvars holds its current values; since tfvars only set
+// root variables, <moduleName>_variables.tf declares one root variable per
+// value and <moduleName>.tf calls the module at source, forwarding each.
+// imports.tf imports the resource into module.<moduleName>, so the first
+// plan shows no replacement. Null and empty optional values are left at the
+// module defaults. Sensitive arguments are declared and forwarded but not
+// written, for the caller to set. An empty source is the built-in pack's.
+func GenerateImport(info *schema.ResourceInfo, state *ResourceState, moduleName, source string) (GeneratedModule, error) {
+	if state.Type != info.ResourceType {
+		return nil, fmt.Errorf("state resource %s is a %s, not a %s", state.Address, state.Type, info.ResourceType)
+	}
+	if state.ID() == "" {
+		return nil, fmt.Errorf("state resource %s has no id", state.Address)
+	}
+	if moduleName == "" {
+		moduleName = info.ShortName
+	}
+	if !hclsyntax.ValidIdentifier(moduleName) {
+		return nil, fmt.Errorf("module name %q is not a valid identifier: use letters, digits, underscores and dashes, starting with a letter", moduleName)
+	}
+	if source == "" {
+		pack, err := LoadTemplatePack("")
+		if err != nil {
+			return nil, err
+		}
+		if source, err = pack.ModuleSource(info); err != nil {
+			return nil, err
+		}
+	}
+
+	tfvars := hclwrite.NewEmptyFile()
+	body := tfvars.Body()
+	body.AppendUnstructuredTokens(commentTokens(fmt.Sprintf("Current values of %s, for importing it into module.%s", state.Address, moduleName)))
+	body.AppendNewline()
+
+	call := hclwrite.NewEmptyFile()
+	module := call.Body().AppendNewBlock("module", []string{moduleName}).Body()
+	module.SetAttributeRaw("source", stringTokens(source))
+	module.AppendNewline()
+
+	// forward declares the root variable for a module variable and passes
+	// it to the module. Every one is required: an imported value left unset
+	// would show up as a change.
+	vars := hclwrite.NewEmptyFile()
+	var s exprSetter
+	forward := func(name, typ string, sensitive bool) {
+		if len(vars.Body().Blocks()) > 0 {
+			vars.Body().AppendNewline()
+		}
+		v := vars.Body().AppendNewBlock("variable", []string{name}).Body()
+		s.set(v, "type", typ)
+		if sensitive {
+			v.SetAttributeRaw("sensitive", hclwrite.TokensForIdentifier("true"))
+		}
+		module.SetAttributeRaw(name, hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: "var"},
+			hcl.TraverseAttr{Name: name},
+		}))
+	}
+
+	var sensitive []string
+	for _, attr := range info.Attributes {
+		if attr.Name == "id" {
+			continue
+		}
+		value, ok := stateValue(state.Attributes[attr.Name], attr.Required)
+		if !ok {
+			continue
+		}
+		name := getVariableName(attr.Name, info.ShortName)
+		if attr.Name == "name" {
+			// keep the deployed name rather than the null-label ID
+			name = info.ShortName + "_name"
+		}
+		if attr.Sensitive {
+			sensitive = append(sensitive, name)
+			forward(name, attr.TFType, true)
+			continue
+		}
+		tokens, err := valueTokens(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", attr.Name, err)
+		}
+		body.SetAttributeRaw(name, tokens)
+		forward(name, attr.TFType, false)
+	}
+
+	for _, block := range info.Blocks {
+		value, ok := blockStateValue(block, state.Attributes[block.Name])
+		if !ok {
+			continue
+		}
+		tokens, err := valueTokens(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", block.Name, err)
+		}
+		body.SetAttributeRaw(block.Name, tokens)
+		forward(block.Name, blockToTypeExpr(block, ""), false)
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if len(sensitive) > 0 {
+		body.AppendNewline()
+		body.AppendUnstructuredTokens(commentTokens("Sensitive, set from a secret store: " + strings.Join(sensitive, ", ")))
+	}
+
+	imports := hclwrite.NewEmptyFile()
+	imp := imports.Body().AppendNewBlock("import", nil).Body()
+	imp.SetAttributeRaw("to", hclwrite.TokensForTraversal(hcl.Traversal{
+		hcl.TraverseRoot{Name: "module"},
+		hcl.TraverseAttr{Name: moduleName},
+		hcl.TraverseAttr{Name: info.ResourceType},
+		hcl.TraverseAttr{Name: "this"},
+		hcl.TraverseIndex{Key: cty.NumberIntVal(0)},
+	}))
+	imp.SetAttributeRaw("id", stringTokens(state.ID()))
+
+	return GeneratedModule{
+		"terraform.tfvars":           string(hclwrite.Format(tfvars.Bytes())),
+		moduleName + ".tf":           string(hclwrite.Format(call.Bytes())),
+		moduleName + "_variables.tf": string(hclwrite.Format(vars.Bytes())),
+		"imports.tf":                 string(hclwrite.Format(imports.Bytes())),
+	}, nil
+}
+
+// stateValue reports whether an attribute's recorded value should be set.
+// The provider records unset optional arguments as "", [] or {}; those keep
+// the module default.
+func stateValue(v any, required bool) (any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if required {
+		return v, true
+	}
+	switch v := v.(type) {
+	case string:
+		return v, v != ""
+	case []any:
+		return v, len(v) > 0
+	case map[string]any:
+		return v, len(v) > 0
+	}
+	return v, true
+}
+
+// blockStateValue converts a block as the state records it, a list of
+// objects, to the block variable's value: an object for single blocks, or
+// a map keyed "<block>-<n>" like the generated examples. Attributes outside
+// the schema, such as computed ones, are dropped.
+func blockStateValue(block schema.ParsedBlock, v any) (any, bool) {
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return nil, false
+	}
+	var objects []map[string]any
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		objects = append(objects, blockObject(block, obj))
+	}
+	if len(objects) == 0 {
+		return nil, false
+	}
+	if isSingleBlock(block) {
+		return objects[0], true
+	}
+	if block.NestingMode == "set" {
+		// set elements have no order of their own; sort for stable keys
+		sort.Slice(objects, func(i, j int) bool {
+			a, _ := json.Marshal(objects[i])
+			b, _ := json.Marshal(objects[j])
+			return bytes.Compare(a, b) < 0
+		})
+	}
+	m := map[string]any{}
+	for i, obj := range objects {
+		m[fmt.Sprintf("%s-%d", block.Name, i+1)] = obj
+	}
+	return m, true
+}
+
+func blockObject(block schema.ParsedBlock, state map[string]any) map[string]any {
+	obj := map[string]any{}
+	for _, attr := range block.Attributes {
+		if value, ok := stateValue(state[attr.Name], attr.Required); ok {
+			obj[attr.Name] = value
+		}
+	}
+	for _, nested := range block.Blocks {
+		if value, ok := blockStateValue(nested, state[nested.Name]); ok {
+			obj[nested.Name] = value
+		}
+	}
+	return obj
+}
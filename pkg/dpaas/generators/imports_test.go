@@ -0,0 +1,162 @@
+package generators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestGenerateImport(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	const id = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Web/sites/legacy-app"
+	show := `{
+  "format_version": "1.0",
+  "values": {"root_module": {
+    "resources": [{"address": "azurerm_resource_group.rg", "mode": "managed", "type": "azurerm_resource_group", "values": {"id": "/subscriptions/x/resourceGroups/rg"}}],
+    "child_modules": [{"resources": [{
+      "address": "module.legacy.azurerm_windows_web_app.app",
+      "mode": "managed",
+      "type": "azurerm_windows_web_app",
+      "values": {
+        "id": "` + id + `",
+        "name": "legacy-app",
+        "location": "eastus2",
+        "resource_group_name": "rg",
+        "service_plan_id": "/subscriptions/x/plan",
+        "enabled": false,
+        "https_only": true,
+        "tags": {"Cost Center": "42"},
+        "default_hostname": "legacy-app.azurewebsites.net",
+        "connection_string": [{"name": "db", "type": "SQLAzure", "value": "secret"}],
+        "site_config": [{"always_on": true, "worker_count": 1, "ip_restriction": []}]
+      }
+    }]}]
+  }}
+}`
+	state, err := ReadResourceState([]byte(show), "azurerm_windows_web_app", "")
+	require.NoError(t, err)
+	assert.Equal(t, "module.legacy.azurerm_windows_web_app.app", state.Address)
+
+	files, err := GenerateImport(info, state, "", "")
+	require.NoError(t, err)
+	assert.Contains(t, files["imports.tf"], "to = module.windows_web_app.azurerm_windows_web_app.this[0]")
+	assert.Contains(t, files["imports.tf"], `id = "`+id+`"`)
+
+	tfvars := files["terraform.tfvars"]
+	f, diags := hclsyntax.ParseConfig([]byte(tfvars), "terraform.tfvars", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	values := map[string]string{}
+	for name, attr := range f.Body.(*hclsyntax.Body).Attributes {
+		values[name] = string(attr.Expr.Range().SliceBytes([]byte(tfvars)))
+	}
+	assert.Equal(t, `"legacy-app"`, values["windows_web_app_name"], "the deployed name is kept")
+	assert.Equal(t, "false", values["windows_web_app_enabled"], "null-label clashes use the prefixed variable")
+	assert.Contains(t, values["tags"], `"Cost Center" = "42"`)
+	assert.Contains(t, values["connection_string"], "connection_string-1")
+	assert.Contains(t, values["site_config"], "always_on = true")
+	assert.NotContains(t, values["site_config"], "worker_count", "attributes outside the schema are dropped")
+	assert.NotContains(t, values["site_config"], "ip_restriction", "empty blocks keep the module default")
+	assert.NotContains(t, tfvars, "default_hostname")
+
+	// every value is a variable of the generated module
+	module := GenerateModule(info, nil)
+	vars, diags := hclsyntax.ParseConfig([]byte(module["variables.tf"]), "variables.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	declared := map[string]bool{}
+	for _, blk := range vars.Body.(*hclsyntax.Body).Blocks {
+		declared[blk.Labels[0]] = true
+	}
+	for name := range values {
+		assert.True(t, declared[name], "%s is not a module variable", name)
+	}
+
+	// the values reach the module: each is a root variable passed to the call
+	rootVars, diags := hclsyntax.ParseConfig([]byte(files["windows_web_app_variables.tf"]), "windows_web_app_variables.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	roots := map[string]bool{}
+	for _, blk := range rootVars.Body.(*hclsyntax.Body).Blocks {
+		roots[blk.Labels[0]] = true
+	}
+	assert.Len(t, roots, len(values))
+
+	tfvarValues := map[string]cty.Value{}
+	for name, attr := range f.Body.(*hclsyntax.Body).Attributes {
+		v, diags := attr.Expr.Value(nil)
+		require.False(t, diags.HasErrors(), diags.Error())
+		tfvarValues[name] = v
+	}
+	ctx := &hcl.EvalContext{Variables: map[string]cty.Value{"var": cty.ObjectVal(tfvarValues)}}
+
+	call, diags := hclsyntax.ParseConfig([]byte(files["windows_web_app.tf"]), "windows_web_app.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	blocks := call.Body.(*hclsyntax.Body).Blocks
+	require.Len(t, blocks, 1)
+	assert.Equal(t, []string{"windows_web_app"}, blocks[0].Labels)
+	args := blocks[0].Body.Attributes
+	source, diags := args["source"].Expr.Value(nil)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.Equal(t, "git::https://code.experian.local/scm/DPAAS/expn-tf-azure-windows-web-app.git", source.AsString())
+	assert.Len(t, args, len(values)+1)
+	for name, want := range tfvarValues {
+		require.Contains(t, args, name)
+		assert.True(t, roots[name], "%s is not declared in the root module", name)
+		got, diags := args[name].Expr.Value(ctx)
+		require.False(t, diags.HasErrors(), diags.Error())
+		assert.True(t, want.RawEquals(got), "%s reaches the module as %#v", name, got)
+	}
+
+	files, err = GenerateImport(info, state, "legacy-app", "./modules/web-app")
+	require.NoError(t, err)
+	assert.Contains(t, files["imports.tf"], "to = module.legacy-app.azurerm_windows_web_app.this[0]")
+	assert.Contains(t, files["legacy-app.tf"], `source = "./modules/web-app"`)
+
+	for _, name := range []string{"legacy app", "1app", "app.web", `app"]`} {
+		_, err = GenerateImport(info, state, name, "")
+		assert.ErrorContains(t, err, "not a valid identifier", name)
+	}
+}
+
+func TestReadResourceState_StateFile(t *testing.T) {
+	raw := `{
+  "version": 4,
+  "resources": [
+    {"mode": "data", "type": "azurerm_windows_web_app", "name": "existing", "instances": [{"attributes": {"id": "data"}}]},
+    {"mode": "managed", "type": "azurerm_windows_web_app", "name": "app", "instances": [
+      {"index_key": "blue", "attributes": {"id": "blue-id"}},
+      {"index_key": "green", "attributes": {"id": "green-id"}}
+    ]}
+  ]
+}`
+	_, err := ReadResourceState([]byte(raw), "azurerm_windows_web_app", "")
+	assert.ErrorContains(t, err, `azurerm_windows_web_app.app["blue"]`, "several candidates need an address")
+
+	state, err := ReadResourceState([]byte(raw), "azurerm_windows_web_app", `azurerm_windows_web_app.app["green"]`)
+	require.NoError(t, err)
+	assert.Equal(t, "green-id", state.ID())
+
+	_, err = ReadResourceState([]byte(raw), "azurerm_linux_web_app", "")
+	assert.Error(t, err)
+	_, err = ReadResourceState([]byte(`{"resources": []}`), "azurerm_windows_web_app", "")
+	assert.Error(t, err)
+}
+
+func TestExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := GeneratedModule{
+		"imports.tf":       "import {}\n",
+		"terraform.tfvars": "x = 1\n",
+		"app.tf":           "module \"app\" {}\n",
+	}
+	assert.Empty(t, ExistingFiles(dir, files))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terraform.tfvars"), []byte("y = 2\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.tf"), nil, 0644))
+	assert.Equal(t, []string{"app.tf", "terraform.tfvars"}, ExistingFiles(dir, files))
+}
@@ -4,121 +4,225 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 )
 
-func GenerateMainTf(info *schema.ResourceInfo) string {
-	var b strings.Builder
+func GenerateMainTf(info *schema.ResourceInfo) (string, error) {
+	return generateMainTf([]moduleResource{{info: info, label: "this"}})
+}
 
-	nameVar := info.ShortName + "_name"
+// moduleResource is one resource block of a generated module.
+type moduleResource struct {
+	info  *schema.ResourceInfo
+	label string // block label: "this" for the primary resource
+	// prefix namespaces the resource's variables; empty for the primary
+	// resource, e.g. "service_plan_" for the others.
+	prefix string
+	// refs are arguments set from another resource of the module instead of
+	// a variable, e.g. service_plan_id → azurerm_service_plan.service_plan[0].id.
+	refs   map[string]string
+	wiring map[string]Relationship // the relationship behind each ref
+	// collection is the map variable a child resource is created for_each
+	// of, e.g. "storage_containers"; empty for single resources.
+	collection string
+}
+
+func (r moduleResource) address() string {
+	return r.info.ResourceType + "." + r.label
+}
 
-	b.WriteString(fmt.Sprintf("resource \"%s\" \"this\" {\n", info.ResourceType))
-	b.WriteString("  count               = local.enabled ? 1 : 0\n\n")
-	b.WriteString(fmt.Sprintf("  name                = var.%s != null ? var.%s : module.this.id\n", nameVar, nameVar))
+// varName returns the variable an argument or block of the resource is set from.
+func (r moduleResource) varName(name string) string {
+	if r.prefix == "" {
+		return getVariableName(name, r.info.ShortName)
+	}
+	return r.prefix + name
+}
 
-	// Only add location and resource_group_name if they exist in the schema
-	hasLocation := false
-	hasResourceGroupName := false
-	for _, attr := range info.Attributes {
-		if attr.Name == "location" {
-			hasLocation = true
+func generateMainTf(resources []moduleResource) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	for i, r := range resources {
+		if i > 0 {
+			f.Body().AppendNewline()
 		}
-		if attr.Name == "resource_group_name" {
-			hasResourceGroupName = true
+		if err := writeResource(f.Body(), r); err != nil {
+			return "", fmt.Errorf("%s: %w", r.address(), err)
 		}
 	}
+	return string(hclwrite.Format(f.Bytes())), nil
+}
 
-	if hasLocation {
-		b.WriteString("  location            = var.location\n")
+func writeResource(parent *hclwrite.Body, r moduleResource) error {
+	if r.collection != "" {
+		return writeCollectionResource(parent, r)
 	}
-	if hasResourceGroupName {
-		b.WriteString("  resource_group_name = var.resource_group_name\n")
+	info := r.info
+	body := parent.AppendNewBlock("resource", []string{info.ResourceType, r.label}).Body()
+	var s exprSetter
+	set := r.setter(body, &s)
+	primary := r.prefix == ""
+
+	nameVar := info.ShortName + "_name"
+
+	set("count", "local.enabled ? 1 : 0")
+	body.AppendNewline()
+	if primary || hasAttribute(info, "name") {
+		set("name", fmt.Sprintf("var.%s != null ? var.%s : module.this.id", nameVar, nameVar))
 	}
+	writeSharedArguments(info, set)
 
-	// Collect attrs excluding standard ones
-	var otherAttrs []schema.ParsedAttribute
-	for _, attr := range info.Attributes {
-		if attr.Name == "location" || attr.Name == "resource_group_name" || attr.Name == "name" || attr.Name == "tags" {
-			continue
+	otherAttrs := nonStandardAttributes(info)
+	if len(otherAttrs) > 0 {
+		body.AppendNewline()
+		for _, a := range otherAttrs {
+			set(a.Name, r.valueExpr(a))
+		}
+	}
+
+	if primary || hasAttribute(info, "tags") {
+		set("tags", "local.tags")
+	}
+
+	// Dynamic blocks
+	for _, block := range info.Blocks {
+		body.AppendNewline()
+		if err := writeDynamicBlock(body, block, "var."+r.varName(block.Name)); err != nil {
+			return err
 		}
-		otherAttrs = append(otherAttrs, attr)
 	}
+	return s.err
+}
+
+// writeCollectionResource declares a child resource once per entry of its
+// map variable, named by the map key.
+func writeCollectionResource(parent *hclwrite.Body, r moduleResource) error {
+	info := r.info
+	body := parent.AppendNewBlock("resource", []string{info.ResourceType, r.label}).Body()
+	var s exprSetter
+	set := r.setter(body, &s)
+
+	set("for_each", fmt.Sprintf("local.enabled ? var.%s : {}", r.collection))
+	body.AppendNewline()
+	if hasAttribute(info, "name") {
+		set("name", "each.key")
+	}
+	writeSharedArguments(info, set)
 
+	otherAttrs := nonStandardAttributes(info)
 	if len(otherAttrs) > 0 {
-		b.WriteString("\n")
-		maxLen := 0
+		body.AppendNewline()
 		for _, a := range otherAttrs {
-			if len(a.Name) > maxLen {
-				maxLen = len(a.Name)
-			}
+			set(a.Name, "each.value."+a.Name)
 		}
+	}
 
-		for _, a := range otherAttrs {
-			padding := strings.Repeat(" ", maxLen-len(a.Name))
-			varName := getVariableName(a.Name, info.ShortName)
-			if a.Required {
-				b.WriteString(fmt.Sprintf("  %s%s = var.%s\n", a.Name, padding, varName))
-			} else {
-				b.WriteString(fmt.Sprintf("  %s%s = try(var.%s, null)\n", a.Name, padding, varName))
-			}
+	if hasAttribute(info, "tags") {
+		set("tags", "local.tags")
+	}
+
+	for _, block := range info.Blocks {
+		body.AppendNewline()
+		if err := writeDynamicBlock(body, block, "each.value."+block.Name); err != nil {
+			return err
 		}
 	}
+	return s.err
+}
 
-	b.WriteString("  tags                = local.tags\n")
+// setter sets an argument of the resource body from expr, or from the
+// resource it is wired to. Parse failures are kept in s.
+func (r moduleResource) setter(body *hclwrite.Body, s *exprSetter) func(name, expr string) {
+	return func(name, expr string) {
+		if ref, ok := r.refs[name]; ok {
+			expr = ref
+		}
+		s.set(body, name, expr)
+	}
+}
 
-	// Dynamic blocks
-	if len(info.Blocks) > 0 {
-		b.WriteString("\n")
-		for _, block := range info.Blocks {
-			writeTopLevelDynamicBlock(&b, block)
+// writeSharedArguments sets location and resource_group_name, when the
+// resource has them, from the variables every resource of the module shares.
+func writeSharedArguments(info *schema.ResourceInfo, set func(name, expr string)) {
+	if hasAttribute(info, "location") {
+		set("location", "var.location")
+	}
+	if hasAttribute(info, "resource_group_name") {
+		set("resource_group_name", "var.resource_group_name")
+	}
+}
+
+// nonStandardAttributes are the attributes set from their own variable:
+// all but name, location, resource_group_name and tags.
+func nonStandardAttributes(info *schema.ResourceInfo) []schema.ParsedAttribute {
+	var attrs []schema.ParsedAttribute
+	for _, attr := range info.Attributes {
+		if attr.Name == "location" || attr.Name == "resource_group_name" || attr.Name == "name" || attr.Name == "tags" {
+			continue
 		}
+		attrs = append(attrs, attr)
 	}
+	return attrs
+}
 
-	b.WriteString("}\n")
-	return b.String()
+// AttributeValueExpr returns the expression main.tf assigns to a resource
+// attribute: the variable itself when required, otherwise wrapped in try().
+func AttributeValueExpr(attr schema.ParsedAttribute, info *schema.ResourceInfo) string {
+	return moduleResource{info: info, label: "this"}.valueExpr(attr)
 }
 
-func writeTopLevelDynamicBlock(b *strings.Builder, block schema.ParsedBlock) {
-	varRef := "var." + block.Name
+func (r moduleResource) valueExpr(attr schema.ParsedAttribute) string {
+	varName := r.varName(attr.Name)
+	if attr.Required {
+		return "var." + varName
+	}
+	return fmt.Sprintf("try(var.%s, null)", varName)
+}
+
+// GenerateDynamicBlock renders the dynamic block that wires a top-level
+// nested block to its variable, indented for the resource body.
+func GenerateDynamicBlock(block schema.ParsedBlock) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	if err := writeDynamicBlock(f.Body(), block, "var."+block.Name); err != nil {
+		return "", err
+	}
+
+	lines := strings.SplitAfter(string(hclwrite.Format(f.Bytes())), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
 
-	b.WriteString(fmt.Sprintf("  dynamic \"%s\" {\n", block.Name))
+// writeDynamicBlock appends the dynamic block iterating over ref, the
+// variable or the enclosing iterator's value.
+func writeDynamicBlock(body *hclwrite.Body, block schema.ParsedBlock, ref string) error {
+	dyn := body.AppendNewBlock("dynamic", []string{block.Name}).Body()
+	var s exprSetter
 
 	if isSingleBlock(block) {
-		b.WriteString(fmt.Sprintf("    for_each = %s != null ? [%s] : []\n", varRef, varRef))
+		s.set(dyn, "for_each", fmt.Sprintf("%s != null ? [%s] : []", ref, ref))
 	} else {
 		// Use map syntax for multi-value blocks per DPaaS standard
-		b.WriteString(fmt.Sprintf("    for_each = %s != null ? %s : {}\n", varRef, varRef))
+		s.set(dyn, "for_each", fmt.Sprintf("%s != null ? %s : {}", ref, ref))
 	}
 
-	b.WriteString("    content {\n")
-	writeBlockContent(b, block, block.Name, "      ")
-	b.WriteString("    }\n")
-	b.WriteString("  }\n")
-}
-
-func writeBlockContent(b *strings.Builder, block schema.ParsedBlock, iterVar string, indent string) {
+	content := dyn.AppendNewBlock("content", nil).Body()
 	for _, attr := range block.Attributes {
-		b.WriteString(fmt.Sprintf("%s%s = %s.value.%s\n", indent, attr.Name, iterVar, attr.Name))
+		s.set(content, attr.Name, fmt.Sprintf("%s.value.%s", block.Name, attr.Name))
+	}
+	if s.err != nil {
+		return fmt.Errorf("dynamic %s: %w", block.Name, s.err)
 	}
-
 	for _, nested := range block.Blocks {
-		nestedRef := iterVar + ".value." + nested.Name
-
-		b.WriteString(fmt.Sprintf("%s\n", ""))
-		b.WriteString(fmt.Sprintf("%sdynamic \"%s\" {\n", indent, nested.Name))
-
-		if isSingleBlock(nested) {
-			b.WriteString(fmt.Sprintf("%s  for_each = %s != null ? [%s] : []\n", indent, nestedRef, nestedRef))
-		} else {
-			// Use map syntax for multi-value blocks per DPaaS standard
-			b.WriteString(fmt.Sprintf("%s  for_each = %s != null ? %s : {}\n", indent, nestedRef, nestedRef))
+		content.AppendNewline()
+		if err := writeDynamicBlock(content, nested, block.Name+".value."+nested.Name); err != nil {
+			return err
 		}
-
-		b.WriteString(fmt.Sprintf("%s  content {\n", indent))
-		writeBlockContent(b, nested, nested.Name, indent+"    ")
-		b.WriteString(fmt.Sprintf("%s  }\n", indent))
-		b.WriteString(fmt.Sprintf("%s}\n", indent))
 	}
+	return nil
 }
 
 func isSingleBlock(block schema.ParsedBlock) bool {
@@ -0,0 +1,271 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ScenarioFileName is the scenario file picked up from the working directory
+// of a module when no explicit path is given.
+const ScenarioFileName = ".dpaas-scenarios.json"
+
+// BuiltinScenarios are the scenarios the generator knows without a scenario file.
+var BuiltinScenarios = []string{"default", "complete", "disabled"}
+
+// Options controls module generation beyond the resource schema.
+type Options struct {
+	Scenarios []string         // built-in scenarios to generate; see BuiltinScenarios
+	Custom    []Scenario       // user-defined scenarios, generated in addition
+	Defaults  ScenarioDefaults // values every scenario starts from
+	// Fixtures emits tests/<scenario>/fixtures.tf creating the resource group,
+	// network, Key Vault and Log Analytics workspace the module call references,
+	// so scenarios can be applied rather than only validated.
+	Fixtures bool
+	// Pack supplies the organisation's conventions; nil selects the
+	// built-in pack.
+	Pack *TemplatePack
+}
+
+// resolved fills in the default pack and the scenario defaults the
+// scenario file left empty from the pack.
+func (o Options) resolved() Options {
+	if o.Pack == nil {
+		o.Pack = DefaultTemplatePack()
+	}
+	o.Defaults = o.Defaults.or(o.Pack.Defaults)
+	return o
+}
+
+// ScenarioDefaults are the environment-specific values shared by every test
+// scenario. Empty fields fall back to the template pack's defaults.
+type ScenarioDefaults struct {
+	Location          string `json:"location,omitempty"`
+	ResourceGroupName string `json:"resource_group_name,omitempty"`
+	SubscriptionID    string `json:"subscription_id,omitempty"`
+}
+
+// or fills the empty fields from fallback.
+func (d ScenarioDefaults) or(fallback ScenarioDefaults) ScenarioDefaults {
+	if d.Location == "" {
+		d.Location = fallback.Location
+	}
+	if d.ResourceGroupName == "" {
+		d.ResourceGroupName = fallback.ResourceGroupName
+	}
+	if d.SubscriptionID == "" {
+		d.SubscriptionID = fallback.SubscriptionID
+	}
+	return d
+}
+
+// Scenario is a user-defined test scenario: a tests/<name>/ root module built
+// from a base scenario with variable overrides and its own provider
+// configuration, plus expectations checked by terraform test.
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Base is the built-in scenario the module call starts from: "default"
+	// (required arguments only, the default) or "complete".
+	Base string `json:"base,omitempty"`
+	// Variables override or add module arguments. JSON values are written as
+	// HCL literals.
+	Variables map[string]any `json:"variables,omitempty"`
+	// Provider is merged into the scenario's provider "azurerm" block. JSON
+	// objects become nested blocks, e.g. {"features": {"key_vault": {...}}}.
+	Provider map[string]any `json:"provider,omitempty"`
+	// Expect are assertions on the planned module, e.g.
+	// "azurerm_storage_account.this[0].public_network_access_enabled == false".
+	Expect []Expectation `json:"expect,omitempty"`
+	// ExpectFailures lists checkable objects, such as "var.sku_name", whose
+	// validation must fail for this scenario.
+	ExpectFailures []string `json:"expect_failures,omitempty"`
+}
+
+// Expectation is one terraform test assert block.
+type Expectation struct {
+	Condition    string `json:"condition"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// ScenarioFile is the JSON document holding user-defined scenarios.
+type ScenarioFile struct {
+	Defaults  ScenarioDefaults `json:"defaults"`
+	Scenarios []Scenario       `json:"scenarios"`
+}
+
+var scenarioNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// LoadScenarioFile reads and checks a scenario file.
+func LoadScenarioFile(path string) (*ScenarioFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f ScenarioFile
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for _, b := range BuiltinScenarios {
+		seen[b] = true
+	}
+	for i, s := range f.Scenarios {
+		if !scenarioNamePattern.MatchString(s.Name) {
+			return nil, fmt.Errorf("scenario %d: name %q must be lower case letters, digits and underscores", i, s.Name)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("scenario %q: name is already used", s.Name)
+		}
+		seen[s.Name] = true
+		switch s.Base {
+		case "":
+			f.Scenarios[i].Base = "default"
+		case "default", "complete":
+		default:
+			return nil, fmt.Errorf("scenario %q: base must be default or complete, got %q", s.Name, s.Base)
+		}
+		for _, e := range s.Expect {
+			if strings.TrimSpace(e.Condition) == "" {
+				return nil, fmt.Errorf("scenario %q: expectation without a condition", s.Name)
+			}
+		}
+	}
+	return &f, nil
+}
+
+// ---------------------------------------------------------------------------
+// HCL rendering helpers
+// ---------------------------------------------------------------------------
+
+// hclQuote renders s as an HCL string literal.
+func hclQuote(s string) string {
+	return string(hclwrite.TokensForValue(cty.StringVal(s)).Bytes())
+}
+
+// stringTokens renders s as a quoted HCL string, escaping quotes,
+// backslashes and template sequences.
+func stringTokens(s string) hclwrite.Tokens {
+	return hclwrite.TokensForValue(cty.StringVal(s))
+}
+
+// exprTokens parses an expression the generator built, e.g.
+// "try(var.sku_name, null)". Expressions embed names from the provider
+// schema, the docs and tool input, so one that does not parse is an error
+// for the caller to report.
+func exprTokens(src string) (hclwrite.Tokens, error) {
+	f, diags := hclwrite.ParseConfig([]byte("expr = "+src+"\n"), "expr.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("generated expression %q: %s", src, diags.Error())
+	}
+	return f.Body().GetAttribute("expr").Expr().BuildTokens(nil), nil
+}
+
+// exprSetter sets attributes from generated expressions. It keeps the first
+// expression that fails to parse and skips the calls after it, so a writer
+// checks err once when it is done.
+type exprSetter struct {
+	err error
+}
+
+func (s *exprSetter) set(body *hclwrite.Body, name, src string) {
+	if s.err != nil {
+		return
+	}
+	tokens, err := exprTokens(src)
+	if err != nil {
+		s.err = fmt.Errorf("%s: %w", name, err)
+		return
+	}
+	body.SetAttributeRaw(name, tokens)
+}
+
+// commentTokens renders a "# text" line comment.
+func commentTokens(text string) hclwrite.Tokens {
+	return hclwrite.Tokens{{Type: hclsyntax.TokenComment, Bytes: []byte("# " + text + "\n")}}
+}
+
+// heredocTokens renders lines as an indented heredoc. Template sequences
+// are escaped; heredocs have no backslash escapes. hclwrite.Format leaves
+// heredoc content alone, so indent is applied here.
+func heredocTokens(marker, indent string, lines []string) hclwrite.Tokens {
+	escape := strings.NewReplacer("${", "$${", "%{", "%%{")
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<-" + marker + "\n")}}
+	for _, line := range lines {
+		if line != "" {
+			line = indent + escape.Replace(line)
+		}
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenStringLit, Bytes: []byte(line + "\n")})
+	}
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(indent + marker)})
+}
+
+// valueTokens renders a decoded JSON value as HCL literal tokens.
+func valueTokens(v any) (hclwrite.Tokens, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	ty, err := ctyjson.ImpliedType(raw)
+	if err != nil {
+		return nil, err
+	}
+	val, err := ctyjson.Unmarshal(raw, ty)
+	if err != nil {
+		return nil, err
+	}
+	return hclwrite.TokensForValue(val), nil
+}
+
+// setAttributes writes each value as an attribute of body, in name order.
+func setAttributes(body *hclwrite.Body, values map[string]any) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tokens, err := valueTokens(values[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		body.SetAttributeRaw(name, tokens)
+	}
+	return nil
+}
+
+// writeProviderBody writes provider configuration into body: JSON objects
+// become nested blocks, everything else an attribute.
+func writeProviderBody(body *hclwrite.Body, config map[string]any) error {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if nested, ok := config[name].(map[string]any); ok {
+			if err := writeProviderBody(body.AppendNewBlock(name, nil).Body(), nested); err != nil {
+				return err
+			}
+			continue
+		}
+		tokens, err := valueTokens(config[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		body.SetAttributeRaw(name, tokens)
+	}
+	return nil
+}
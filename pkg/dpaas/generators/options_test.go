@@ -0,0 +1,66 @@
+package generators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModuleWithOptions_UserDefinedScenario(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	scenarioPath := filepath.Join(t.TempDir(), "scenarios.json")
+	require.NoError(t, os.WriteFile(scenarioPath, []byte(`{
+  "defaults": {"location": "West Europe", "subscription_id": "00000000-0000-0000-0000-000000000001"},
+  "scenarios": [{
+    "name": "https_only",
+    "description": "Plain HTTP is refused",
+    "variables": {"https_only": true, "site_config": {"always_on": true}},
+    "provider": {"storage_use_azuread": true, "features": {"resource_group": {"prevent_deletion_if_contains_resources": false}}},
+    "expect": [{"condition": "azurerm_windows_web_app.this[0].https_only == true", "error_message": "HTTPS only"}]
+  }]
+}`), 0644))
+	file, err := LoadScenarioFile(scenarioPath)
+	require.NoError(t, err)
+
+	files, err := GenerateModuleWithOptions(info, Options{
+		Scenarios: []string{"default"},
+		Custom:    file.Scenarios,
+		Defaults:  file.Defaults,
+	})
+	require.NoError(t, err)
+
+	main := files["tests/https_only/main.tf"]
+	assert.Contains(t, main, "# Plain HTTP is refused")
+	assert.Contains(t, main, `"West Europe"`)
+	assert.Contains(t, main, `name        = "https-only"`)
+	assert.Regexp(t, `https_only\s+= true`, main)
+	assert.Contains(t, files["tests/default/main.tf"], `"West Europe"`)
+	assert.NotContains(t, files["tests/default/main.tf"], "East US 2")
+
+	versions := files["tests/https_only/versions.tf"]
+	assert.Contains(t, versions, `subscription_id     = "00000000-0000-0000-0000-000000000001"`)
+	assert.Contains(t, versions, "prevent_deletion_if_contains_resources = false")
+	assert.Contains(t, files["tests/default/versions.tf"], "features {\n  }")
+
+	runs := files["tests/scenarios.tftest.hcl"]
+	assert.Contains(t, runs, `run "https_only" {`)
+	assert.Contains(t, runs, "condition     = azurerm_windows_web_app.this[0].https_only == true")
+}
+
+func TestLoadScenarioFile_Rejects(t *testing.T) {
+	for name, doc := range map[string]string{
+		"builtin name": `{"scenarios": [{"name": "default"}]}`,
+		"bad name":     `{"scenarios": [{"name": "Private Networking"}]}`,
+		"bad base":     `{"scenarios": [{"name": "x", "base": "disabled"}]}`,
+		"unknown key":  `{"scenarios": [{"name": "x", "vars": {}}]}`,
+	} {
+		path := filepath.Join(t.TempDir(), "scenarios.json")
+		require.NoError(t, os.WriteFile(path, []byte(doc), 0644))
+		_, err := LoadScenarioFile(path)
+		assert.Error(t, err, name)
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/templates"
@@ -11,29 +15,117 @@ import (
 
 // GenerateModule produces all files for a DPaaS innersource module.
 // scenarios controls which test scenarios are generated (default, complete, disabled).
+//
+// It returns nil when the module cannot be rendered, e.g. for a schema
+// argument that is no valid identifier; GenerateModuleWithOptions reports why.
 func GenerateModule(info *schema.ResourceInfo, scenarios []string) GeneratedModule {
+	m, _ := GenerateModuleWithOptions(info, Options{Scenarios: scenarios})
+	return m
+}
+
+// GenerateModuleWithOptions is GenerateModule with user-defined scenarios and
+// scenario defaults. It fails when a scenario or a generated expression
+// cannot be rendered.
+func GenerateModuleWithOptions(info *schema.ResourceInfo, opts Options) (GeneratedModule, error) {
+	mainTf, err := GenerateMainTf(info)
+	if err != nil {
+		return nil, err
+	}
+	outputsTf, err := GenerateOutputsTf(info)
+	if err != nil {
+		return nil, err
+	}
+	return generateModule(info, mainTf, outputsTf, opts)
+}
+
+// generateModule produces a module around the given main.tf and outputs.tf.
+// info describes the module's inputs: everything but those two files is
+// generated from it.
+func generateModule(info *schema.ResourceInfo, mainTf, outputsTf string, opts Options) (GeneratedModule, error) {
 	m := GeneratedModule{}
+	opts = opts.resolved()
 
 	// Static files (byte-for-byte copies)
-	m["context.tf"] = templates.ContextTf
 	m[".pre-commit-config.yaml"] = templates.PreCommitConfig
 	m[".gitignore"] = templates.Gitignore
 	m["versions.tf"] = templates.VersionsRootTf
 
+	// Files from the template pack
+	var err error
+	rendered := []struct {
+		file   string
+		render func() (string, error)
+	}{
+		{"context.tf", func() (string, error) { return opts.Pack.render(packContextTemplate, info, templates.ContextTf) }},
+		{"locals.tf", func() (string, error) { return GenerateLocalsTf(info, opts.Pack) }},
+		{"variables.tf", func() (string, error) { return GenerateVariablesTf(info, opts.Pack) }},
+		{"README.md", func() (string, error) { return GenerateReadme(info, opts.Pack) }},
+		{"CHANGELOG.md", func() (string, error) { return GenerateChangelog(info, opts.Pack) }},
+	}
+	for _, r := range rendered {
+		if m[r.file], err = r.render(); err != nil {
+			return nil, fmt.Errorf("%s: %w", r.file, err)
+		}
+	}
+
 	// Dynamic files
-	m["locals.tf"] = GenerateLocalsTf(info)
-	m["main.tf"] = GenerateMainTf(info)
-	m["variables.tf"] = GenerateVariablesTf(info)
-	m["outputs.tf"] = GenerateOutputsTf(info)
-	m["README.md"] = GenerateReadme(info)
-	m["CHANGELOG.md"] = GenerateChangelog(info)
+	m["main.tf"] = mainTf
+	m["outputs.tf"] = outputsTf
+
+	// Examples rendered by the private registry
+	examples, err := GenerateExamples(info, opts)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range examples {
+		m[k] = v
+	}
 
 	// Tests
-	for k, v := range GenerateTests(info, scenarios) {
+	tests, err := GenerateTests(info, opts)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range tests {
 		m[k] = v
 	}
 
-	return m
+	docs, err := TerraformDocs(m)
+	if err != nil {
+		return nil, fmt.Errorf("README inputs and outputs: %w", err)
+	}
+	m["README.md"], _ = UpdateReadmeTfDocs(m["README.md"], docs)
+
+	// canonical formatting, as terraform fmt would write it
+	for name, content := range m {
+		if isHCLFile(name) {
+			m[name] = string(hclwrite.Format([]byte(content)))
+		}
+	}
+
+	return m, nil
+}
+
+func isHCLFile(name string) bool {
+	for _, ext := range []string{".tf", ".tftest.hcl", ".tfvars", ".tfvars.example"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExistingFiles returns the files of module that already exist in outputDir,
+// sorted, so callers that must not overwrite anything can refuse to write.
+func ExistingFiles(outputDir string, module GeneratedModule) []string {
+	var existing []string
+	for relPath := range module {
+		if _, err := os.Lstat(filepath.Join(outputDir, relPath)); err == nil {
+			existing = append(existing, relPath)
+		}
+	}
+	sort.Strings(existing)
+	return existing
 }
 
 // WriteModule writes all generated files to the specified output directory.
@@ -0,0 +1,70 @@
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModule_EscapedCanonicalHCL(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	tricky := `Path like C:\temp, "quoted", and ${var.secret} or %{if true}x%{endif}`
+	info.Attributes = append(info.Attributes, schema.ParsedAttribute{
+		Name: "tricky_setting", TFType: "string", Optional: true, Description: tricky,
+	})
+	info.Blocks[0].Attributes[0].Description = "Interpolates ${var.secret}"
+	files := GenerateModule(info, []string{"default", "complete", "disabled"})
+
+	for name, content := range files {
+		if !strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tftest.hcl") {
+			continue
+		}
+		assert.Equal(t, string(hclwrite.Format([]byte(content))), content, "%s is canonically formatted", name)
+	}
+
+	f, diags := hclsyntax.ParseConfig([]byte(files["variables.tf"]), "variables.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	descriptions := map[string]string{}
+	for _, blk := range f.Body.(*hclsyntax.Body).Blocks {
+		if a, ok := blk.Body.Attributes["description"]; ok && blk.Type == "variable" {
+			v, diags := a.Expr.Value(nil)
+			require.False(t, diags.HasErrors(), "%s: %s", blk.Labels[0], diags.Error())
+			descriptions[blk.Labels[0]] = v.AsString()
+		}
+	}
+	assert.Equal(t, tricky, descriptions["tricky_setting"], "the description survives quoting")
+	assert.Contains(t, descriptions[info.Blocks[0].Name], "Interpolates ${var.secret}", "heredocs are not interpolated")
+}
+
+func TestGenerateModuleWithOptions_InvalidExpression(t *testing.T) {
+	tests := map[string]func(info *schema.ResourceInfo){
+		"attribute name": func(info *schema.ResourceInfo) {
+			info.Attributes = append(info.Attributes, schema.ParsedAttribute{Name: "odata.type", TFType: "string", Optional: true})
+		},
+		"attribute type": func(info *schema.ResourceInfo) {
+			info.Attributes[1].TFType = "list(string"
+		},
+		"computed attribute": func(info *schema.ResourceInfo) {
+			info.ComputedOnlyAttrs = append(info.ComputedOnlyAttrs, "@odata.etag")
+		},
+		"nested block attribute": func(info *schema.ResourceInfo) {
+			info.Blocks[1].Blocks[0].Attributes[0].Name = "ip action"
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			info := schematest.WindowsWebApp()
+			mutate(info)
+			_, err := GenerateModuleWithOptions(info, Options{})
+			assert.Error(t, err, "an error, not a panic")
+			assert.Nil(t, GenerateModule(info, nil))
+		})
+	}
+}
@@ -4,27 +4,64 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 )
 
-func GenerateOutputsTf(info *schema.ResourceInfo) string {
-	var b strings.Builder
-
-	b.WriteString("# outputs.tf\n")
-	b.WriteString(fmt.Sprintf("output \"id\" {\n"))
-	b.WriteString(fmt.Sprintf("  description = \"The ID of the %s\"\n", info.DisplayName))
-	b.WriteString(fmt.Sprintf("  value       = %s.this\n", info.ResourceType))
-	b.WriteString("}\n")
-
-	// Computed-only attributes
-	for _, name := range info.ComputedOnlyAttrs {
-		displayName := strings.ReplaceAll(name, "_", " ")
-		b.WriteString("\n")
-		b.WriteString(fmt.Sprintf("output \"%s\" {\n", name))
-		b.WriteString(fmt.Sprintf("  description = \"The %s of the %s\"\n", displayName, info.DisplayName))
-		b.WriteString(fmt.Sprintf("  value       = %s.this[*].%s\n", info.ResourceType, name))
-		b.WriteString("}\n")
+func GenerateOutputsTf(info *schema.ResourceInfo) (string, error) {
+	return generateOutputsTf([]moduleResource{{info: info, label: "this"}})
+}
+
+// generateOutputsTf renders the outputs of each resource. The primary
+// resource's outputs are named after its attributes, the others' are
+// namespaced like their variables, and a child collection has one map output.
+func generateOutputsTf(resources []moduleResource) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	var s exprSetter
+
+	body.AppendUnstructuredTokens(commentTokens("outputs.tf"))
+	for i, r := range resources {
+		info := r.info
+		if i > 0 {
+			body.AppendNewline()
+		}
+		if r.collection != "" {
+			writeCollectionOutput(body, r, &s)
+			continue
+		}
+		out := body.AppendNewBlock("output", []string{r.prefix + "id"}).Body()
+		out.SetAttributeRaw("description", stringTokens(fmt.Sprintf("The ID of the %s", info.DisplayName)))
+		if r.prefix == "" {
+			s.set(out, "value", r.address())
+		} else {
+			s.set(out, "value", fmt.Sprintf("one(%s[*].id)", r.address()))
+		}
+
+		// Computed-only attributes
+		for _, name := range info.ComputedOnlyAttrs {
+			displayName := strings.ReplaceAll(name, "_", " ")
+			body.AppendNewline()
+			out := body.AppendNewBlock("output", []string{r.prefix + name}).Body()
+			out.SetAttributeRaw("description", stringTokens(fmt.Sprintf("The %s of the %s", displayName, info.DisplayName)))
+			s.set(out, "value", fmt.Sprintf("%s[*].%s", r.address(), name))
+		}
 	}
+	if s.err != nil {
+		return "", fmt.Errorf("outputs.tf: %w", s.err)
+	}
+
+	return string(hclwrite.Format(f.Bytes())), nil
+}
 
-	return b.String()
+// writeCollectionOutput exports the ID and computed attributes of every
+// child resource, keyed like the collection variable.
+func writeCollectionOutput(body *hclwrite.Body, r moduleResource, s *exprSetter) {
+	fields := []string{"id = v.id"}
+	for _, name := range r.info.ComputedOnlyAttrs {
+		fields = append(fields, fmt.Sprintf("%s = v.%s", name, name))
+	}
+	out := body.AppendNewBlock("output", []string{r.collection}).Body()
+	out.SetAttributeRaw("description", stringTokens(fmt.Sprintf("The ID and computed attributes of each %s, keyed like var.%s", r.info.DisplayName, r.collection)))
+	s.set(out, "value", fmt.Sprintf("{ for k, v in %s : k => { %s } }", r.address(), strings.Join(fields, ", ")))
 }
@@ -0,0 +1,267 @@
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/templates"
+)
+
+// DefaultTemplatePackName is the built-in pack used when none is selected.
+const DefaultTemplatePackName = "experian"
+
+// packConfigFile is the configuration file of a template pack directory.
+const packConfigFile = "pack.json"
+
+// Templates a pack may provide. locals.tf, README.md and CHANGELOG.md must
+// be provided; context.tf and the two halves of the null-label variables
+// default to the standard null-label files.
+const (
+	packLocalsTemplate     = "locals.tf.tmpl"
+	packReadmeTemplate     = "readme.md.tmpl"
+	packChangelogTemplate  = "changelog.md.tmpl"
+	packContextTemplate    = "context.tf.tmpl"
+	packLabelVarsATemplate = "null_label_vars_part_a.tf.tmpl"
+	packLabelVarsBTemplate = "null_label_vars_part_b.tf.tmpl"
+)
+
+var requiredPackTemplates = []string{packLocalsTemplate, packReadmeTemplate, packChangelogTemplate}
+
+// TemplatePack holds an organisation's module conventions: naming, the
+// module source, the null-label inputs and tags used in examples, and the
+// text/template files for the organisation-specific module files. A pack is
+// a directory with a pack.json and *.tmpl files.
+type TemplatePack struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// ModulePrefix is prepended to the hyphenated resource name to name the
+	// module, e.g. "expn-tf-azure-" gives "expn-tf-azure-storage-account".
+	ModulePrefix string `json:"module_prefix"`
+	// Source is a template for the module source consumers use, executed
+	// with the resource info, e.g. "git::https://example.com/{{.ModuleName}}.git".
+	Source string `json:"source"`
+	// Label are the null-label inputs set in tests, examples and the README.
+	Label PackLabel `json:"label"`
+	// Tags are the tags set in tests, examples and the README.
+	Tags map[string]string `json:"tags"`
+	// TagLocal names the local the locals.tf template merges over the
+	// caller's tags, e.g. "dpaas_tags" for local.dpaas_tags.
+	TagLocal string `json:"tag_local"`
+	// RequiredTags are the keys TagLocal must carry, e.g. "innersource".
+	RequiredTags []string `json:"required_tags,omitempty"`
+	// Defaults are the scenario defaults when no scenario file sets them.
+	// All three fields are required.
+	Defaults ScenarioDefaults `json:"scenario_defaults"`
+
+	templates *template.Template
+}
+
+// PackLabel are the null-label inputs a pack's examples use.
+type PackLabel struct {
+	Namespace   string `json:"namespace"`
+	Tenant      string `json:"tenant"`
+	Environment string `json:"environment"`
+}
+
+// PackData is what a pack's templates are executed with. The resource info
+// fields (DisplayName, ShortName, ModuleName, ...) are promoted.
+type PackData struct {
+	*schema.ResourceInfo
+	Pack   *TemplatePack
+	Source string // the module source rendered from the pack
+	Date   string // today, YYYY-MM-DD
+
+	// README sections generated by the server
+	LastReview     string // the review line the benchmark update rewrites
+	BenchmarkTable string
+	Usage          string // module call example
+	Examples       string // list of links to examples/
+	TerraformDocs  string // markers the inputs and outputs tables are written between
+}
+
+var defaultPack = func() *TemplatePack {
+	p, err := LoadTemplatePack(DefaultTemplatePackName)
+	if err != nil {
+		panic(fmt.Sprintf("built-in template pack: %v", err))
+	}
+	return p
+}()
+
+// DefaultTemplatePack returns the built-in Experian pack.
+func DefaultTemplatePack() *TemplatePack {
+	return defaultPack
+}
+
+// BuiltinTemplatePacks lists the names of the packs shipped with the server.
+func BuiltinTemplatePacks() []string {
+	entries, _ := fs.ReadDir(templates.Packs, "packs")
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTemplatePack loads a built-in pack by name, or a pack directory by path.
+func LoadTemplatePack(ref string) (*TemplatePack, error) {
+	if ref == "" {
+		ref = DefaultTemplatePackName
+	}
+	var fsys fs.FS
+	if sub, err := fs.Sub(templates.Packs, path.Join("packs", ref)); err == nil && !strings.ContainsAny(ref, `/\`) {
+		if _, err := fs.Stat(sub, packConfigFile); err == nil {
+			fsys = sub
+		}
+	}
+	if fsys == nil {
+		info, err := os.Stat(ref)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("template pack %q is neither a built-in pack (%s) nor a directory", ref, strings.Join(BuiltinTemplatePacks(), ", "))
+		}
+		fsys = os.DirFS(ref)
+	}
+	return loadTemplatePack(fsys, ref)
+}
+
+func loadTemplatePack(fsys fs.FS, ref string) (*TemplatePack, error) {
+	raw, err := fs.ReadFile(fsys, packConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("template pack %s: %w", ref, err)
+	}
+	p := &TemplatePack{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("template pack %s: parse %s: %w", ref, packConfigFile, err)
+	}
+	switch {
+	case p.Name == "":
+		return nil, fmt.Errorf("template pack %s: name is required", ref)
+	case p.ModulePrefix == "":
+		return nil, fmt.Errorf("template pack %s: module_prefix is required", ref)
+	case p.Source == "":
+		return nil, fmt.Errorf("template pack %s: source is required", ref)
+	case p.Label.Namespace == "" || p.Label.Tenant == "" || p.Label.Environment == "":
+		return nil, fmt.Errorf("template pack %s: label needs namespace, tenant and environment", ref)
+	case len(p.Tags) == 0:
+		return nil, fmt.Errorf("template pack %s: at least one tag is required", ref)
+	case !hclsyntax.ValidIdentifier(p.TagLocal):
+		return nil, fmt.Errorf("template pack %s: tag_local must name a local, got %q", ref, p.TagLocal)
+	case p.Defaults.Location == "" || p.Defaults.ResourceGroupName == "" || p.Defaults.SubscriptionID == "":
+		return nil, fmt.Errorf("template pack %s: scenario_defaults needs location, resource_group_name and subscription_id", ref)
+	}
+
+	p.templates = template.New(p.Name).Option("missingkey=error")
+	if _, err := p.templates.New("source").Parse(p.Source); err != nil {
+		return nil, fmt.Errorf("template pack %s: source: %w", ref, err)
+	}
+	matches, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range matches {
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("template pack %s: %w", ref, err)
+		}
+		if _, err := p.templates.New(name).Parse(string(src)); err != nil {
+			return nil, fmt.Errorf("template pack %s: %w", ref, err)
+		}
+	}
+	for _, name := range requiredPackTemplates {
+		if p.templates.Lookup(name) == nil {
+			return nil, fmt.Errorf("template pack %s: %s is required", ref, name)
+		}
+	}
+
+	// surface template errors at load time rather than mid-generation
+	sample := &schema.ResourceInfo{
+		ResourceType: "azurerm_example",
+		ShortName:    "example",
+		ModuleName:   p.ModuleName("example"),
+		DisplayName:  "Example",
+	}
+	for _, t := range p.templates.Templates() {
+		if _, err := p.render(t.Name(), sample, ""); err != nil {
+			return nil, fmt.Errorf("template pack %s: %w", ref, err)
+		}
+	}
+	return p, nil
+}
+
+// ModuleName names the module for a resource short name, e.g.
+// "storage_account" → "expn-tf-azure-storage-account".
+func (p *TemplatePack) ModuleName(shortName string) string {
+	return p.ModulePrefix + strings.ReplaceAll(shortName, "_", "-")
+}
+
+// ModulePattern is the glob matching the directory names of the pack's
+// modules, e.g. "expn-tf-azure-*".
+func (p *TemplatePack) ModulePattern() string {
+	return p.ModulePrefix + "*"
+}
+
+// TagNames returns the pack's tag keys in order.
+func (p *TemplatePack) TagNames() []string {
+	return sortedKeys(p.Tags)
+}
+
+// ResourceTags returns the tag keys every resource of the pack's modules
+// carries, in order: the pack's tags, which callers set, and the required
+// keys of its tag local.
+func (p *TemplatePack) ResourceTags() []string {
+	keys := map[string]bool{}
+	for _, k := range p.RequiredTags {
+		keys[k] = true
+	}
+	for k := range p.Tags {
+		keys[k] = true
+	}
+	return sortedKeys(keys)
+}
+
+// ModuleSource renders the module source for a resource.
+func (p *TemplatePack) ModuleSource(info *schema.ResourceInfo) (string, error) {
+	var b strings.Builder
+	if err := p.templates.ExecuteTemplate(&b, "source", info); err != nil {
+		return "", fmt.Errorf("template pack %s: source: %w", p.Name, err)
+	}
+	return b.String(), nil
+}
+
+// render executes one of the pack's templates. Optional templates the pack
+// does not provide render as fallback.
+func (p *TemplatePack) render(name string, info *schema.ResourceInfo, fallback string) (string, error) {
+	return p.renderData(name, &PackData{ResourceInfo: info}, fallback)
+}
+
+func (p *TemplatePack) renderData(name string, data *PackData, fallback string) (string, error) {
+	t := p.templates.Lookup(name)
+	if t == nil {
+		return fallback, nil
+	}
+	source, err := p.ModuleSource(data.ResourceInfo)
+	if err != nil {
+		return "", err
+	}
+	data.Pack, data.Source, data.Date = p, source, time.Now().Format("2006-01-02")
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("template pack %s: %w", p.Name, err)
+	}
+	return b.String(), nil
+}
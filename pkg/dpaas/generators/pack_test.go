@@ -0,0 +1,110 @@
+package generators
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplatePack_Builtin(t *testing.T) {
+	pack, err := LoadTemplatePack("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTemplatePackName, pack.Name)
+	assert.Contains(t, BuiltinTemplatePacks(), pack.Name)
+	assert.Equal(t, "expn-tf-azure-storage-account", pack.ModuleName("storage_account"))
+	assert.Equal(t, "expn-tf-azure-*", pack.ModulePattern())
+	assert.Equal(t, "dpaas_tags", pack.TagLocal)
+	assert.Equal(t, []string{"innersource"}, pack.RequiredTags)
+	assert.Equal(t, []string{"AppID", "CostString", "Environment", "innersource"}, pack.ResourceTags())
+
+	info := schematest.WindowsWebApp()
+	locals, err := GenerateLocalsTf(info, pack)
+	require.NoError(t, err)
+	assert.Contains(t, locals, "code.experian.local/scm/DPAAS/"+info.ModuleName+".git//")
+}
+
+func TestGenerateModuleWithOptions_CustomTemplatePack(t *testing.T) {
+	dir := t.TempDir()
+	packFiles := map[string]string{
+		"pack.json": `{
+  "name": "contoso",
+  "module_prefix": "ctso-azure-",
+  "source": "git::https://git.contoso.example/terraform/{{.ModuleName}}.git",
+  "label": {"namespace": "ctso", "tenant": "platform", "environment": "dev"},
+  "tags": {"cost-centre": "1234", "Owner": "platform-team"},
+  "tag_local": "managed_tags",
+  "required_tags": ["managed-by"],
+  "scenario_defaults": {
+    "location": "West Europe",
+    "resource_group_name": "rg-platform-dev",
+    "subscription_id": "00000000-0000-0000-0000-000000000001"
+  }
+}`,
+		"locals.tf.tmpl": `locals {
+  managed_tags = { "managed-by" = "{{.Source}}" }
+  tags         = merge(var.tags, local.managed_tags)
+  enabled      = module.this.enabled && var.create_{{.ShortName}}
+}
+`,
+		"readme.md.tmpl":    "# Contoso {{.DisplayName}}\n\n```hcl\n{{.Usage}}```\n\n{{.TerraformDocs}}\n",
+		"changelog.md.tmpl": "# Changelog\n\n## 0.1.0 - {{.Date}}\n",
+	}
+	for name, content := range packFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	pack, err := LoadTemplatePack(dir)
+	require.NoError(t, err)
+
+	info := schematest.WindowsWebApp()
+	info.ModuleName = pack.ModuleName(info.ShortName)
+	files, err := GenerateModuleWithOptions(info, Options{
+		Scenarios: []string{"default"},
+		Pack:      pack,
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, files["locals.tf"], "git.contoso.example/terraform/ctso-azure-"+strings.ReplaceAll(info.ShortName, "_", "-")+".git")
+	assert.NotContains(t, files["locals.tf"], "experian")
+	assert.True(t, strings.HasPrefix(files["README.md"], "# Contoso "+info.DisplayName))
+	assert.Contains(t, files["README.md"], "## Inputs", "the inputs table is filled in")
+	assert.Contains(t, files["README.md"], `namespace   = "ctso"`)
+	assert.Contains(t, files["README.md"], `cost-centre = "1234"`)
+
+	main := files["tests/default/main.tf"]
+	assert.Contains(t, main, `tenant      = "platform"`)
+	assert.Contains(t, main, `"Owner"       = "platform-team"`)
+	assert.NotContains(t, main, "CostString")
+	assert.Contains(t, main, `"West Europe"`, "scenario defaults come from the pack")
+	assert.Contains(t, files["tests/default/versions.tf"], "00000000-0000-0000-0000-000000000001")
+	assert.Contains(t, files["context.tf"], `module "this"`, "context.tf falls back to null-label")
+	assert.Contains(t, files["tests/tags.tftest.hcl"], `run "managed_tags_are_merged"`)
+	assert.Contains(t, files["tests/tags.tftest.hcl"], "for k, v in local.managed_tags")
+}
+
+func TestLoadTemplatePack_Rejects(t *testing.T) {
+	valid := `{"name": "x", "module_prefix": "x-", "source": "{{.ModuleName}}", "label": {"namespace": "a", "tenant": "b", "environment": "c"}, "tags": {"a": "b"},
+  "tag_local": "t", "scenario_defaults": {"location": "l", "resource_group_name": "rg", "subscription_id": "s"}}`
+	for name, files := range map[string]map[string]string{
+		"unknown field":      {"pack.json": `{"name": "x", "prefix": "x-"}`},
+		"missing source":     {"pack.json": `{"name": "x", "module_prefix": "x-", "label": {"namespace": "a", "tenant": "b", "environment": "c"}, "tags": {"a": "b"}}`},
+		"invalid tag_local":  {"pack.json": strings.Replace(valid, `"tag_local": "t"`, `"tag_local": "local.t"`, 1)},
+		"missing template":   {"pack.json": valid, "locals.tf.tmpl": "locals {}", "readme.md.tmpl": "# x"},
+		"unknown data field": {"pack.json": valid, "locals.tf.tmpl": "{{.Nope}}", "readme.md.tmpl": "# x", "changelog.md.tmpl": "# x"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+			}
+			_, err := LoadTemplatePack(dir)
+			assert.Error(t, err)
+		})
+	}
+	_, err := LoadTemplatePack("no-such-pack")
+	assert.ErrorContains(t, err, "experian")
+}
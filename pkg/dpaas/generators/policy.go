@@ -0,0 +1,418 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// PolicyDir is the directory of a module the policy set is written to.
+const PolicyDir = "policies"
+
+// invalidPolicyValue is the value the failing fixtures give enum and string
+// settings.
+const invalidPolicyValue = "not-a-valid-value"
+
+// PolicyOptions selects the policy languages and what the policies enforce
+// besides the schema's enum arguments.
+type PolicyOptions struct {
+	Sentinel bool
+	Rego     bool
+	// RequiredTags are the tag keys every resource must carry, e.g. the
+	// template pack's ResourceTags.
+	RequiredTags []string
+	// Security are the settings the policies require a secure value of,
+	// e.g. from the validation security baseline.
+	Security []PolicySetting
+}
+
+// PolicySetting is a security argument a policy requires a secure value of.
+type PolicySetting struct {
+	Path   string // argument path, e.g. "site_config.minimum_tls_version"
+	Values []any  // the secure values, compared exactly
+	// NullOK settings pass when the argument is unset: the provider default
+	// is secure.
+	NullOK bool
+}
+
+// PolicyRule is one constraint of a generated policy set. Variable and
+// Findings are filled in when the rule is cross-checked against the module.
+type PolicyRule struct {
+	Name    string `json:"name"` // rule name in every language, e.g. "account_tier_allowed"
+	Kind    string `json:"kind"` // "enum", "tags" or "security"
+	Path    string `json:"path"` // argument path, e.g. "site_config.minimum_tls_version"
+	Allowed []any  `json:"allowed"`
+	// NullOK rules pass when the argument is unset.
+	NullOK   bool     `json:"null_ok"`
+	Variable string   `json:"variable,omitempty"` // module input path feeding the argument
+	Findings []string `json:"findings,omitempty"` // where the module's variables disagree with the rule
+}
+
+// PolicySet is the starter policy set of one module.
+type PolicySet struct {
+	Name  string // the resource short name, naming the policy and Rego package
+	Rules []PolicyRule
+	// Files are the policies, their tests and fixtures, relative to the
+	// policy directory.
+	Files GeneratedModule
+}
+
+// GeneratePolicies derives a starter policy set from the resource schema:
+// allowed values of enum arguments, the required tags and the secure value
+// of every setting in opts.Security. Sentinel and Rego policies evaluate the
+// same rules on a plan and share their pass and fail fixtures.
+func GeneratePolicies(info *schema.ResourceInfo, opts PolicyOptions) (*PolicySet, error) {
+	if !strings.HasPrefix(info.ResourceType, "azurerm_") {
+		return nil, fmt.Errorf("policies are generated for azurerm resources; %s is not one", info.ResourceType)
+	}
+	if !opts.Sentinel && !opts.Rego {
+		return nil, fmt.Errorf("select at least one policy language")
+	}
+
+	ps := &PolicySet{Name: info.ShortName, Files: GeneratedModule{}}
+
+	var walk func(prefix string, attrs []schema.ParsedAttribute, blocks []schema.ParsedBlock)
+	walk = func(prefix string, attrs []schema.ParsedAttribute, blocks []schema.ParsedBlock) {
+		for _, a := range attrs {
+			if a.TFType != "string" || len(a.EnumValues) == 0 || len(a.EnumValues) >= 20 {
+				continue
+			}
+			var allowed []any
+			for _, v := range a.EnumValues {
+				allowed = append(allowed, v)
+			}
+			ps.Rules = append(ps.Rules, PolicyRule{Name: ruleName(prefix+a.Name) + "_allowed", Kind: "enum", Path: prefix + a.Name, Allowed: allowed, NullOK: true})
+		}
+		for _, b := range blocks {
+			walk(prefix+b.Name+".", b.Attributes, b.Blocks)
+		}
+	}
+	walk("", info.Attributes, info.Blocks)
+
+	if hasAttr(info, "tags") && len(opts.RequiredTags) > 0 {
+		var tags []any
+		for _, t := range opts.RequiredTags {
+			tags = append(tags, t)
+		}
+		ps.Rules = append(ps.Rules, PolicyRule{Name: "required_tags", Kind: "tags", Path: "tags", Allowed: tags})
+	}
+
+	for _, s := range opts.Security {
+		ps.Rules = append(ps.Rules, PolicyRule{Name: ruleName(s.Path) + "_secure", Kind: "security", Path: s.Path, Allowed: s.Values, NullOK: s.NullOK})
+	}
+
+	if len(ps.Rules) == 0 {
+		return nil, fmt.Errorf("%s has no enum arguments, tags or security settings to enforce", info.ResourceType)
+	}
+
+	pass, fail := ps.fixtures()
+	if opts.Sentinel {
+		if err := ps.writeSentinel(info, pass, fail); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Rego {
+		if err := ps.writeRego(info, pass, fail); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
+
+// fixtures returns the planned values of a resource passing every rule and
+// of one failing every rule, blocks holding a single element.
+func (ps *PolicySet) fixtures() (pass, fail map[string]any) {
+	pass, fail = map[string]any{}, map[string]any{}
+	enums := map[string]map[any]bool{}
+	for _, r := range ps.Rules {
+		if r.Kind == "enum" {
+			enums[r.Path] = map[any]bool{}
+			for _, v := range r.Allowed {
+				enums[r.Path][v] = true
+			}
+		}
+	}
+	for _, r := range ps.Rules {
+		switch r.Kind {
+		case "enum":
+			setPlanValue(pass, r.Path, r.Allowed[0], false)
+			setPlanValue(fail, r.Path, invalidPolicyValue, false)
+		case "tags":
+			tags := map[string]any{}
+			for _, t := range r.Allowed {
+				tags[t.(string)] = "example"
+			}
+			setPlanValue(pass, r.Path, tags, false)
+			setPlanValue(fail, r.Path, map[string]any{}, false)
+		case "security":
+			// a secure value the enum of the same path allows, if any,
+			// passes both rules
+			secure := r.Allowed[0]
+			for _, v := range r.Allowed {
+				if enums[r.Path][v] {
+					secure = v
+					break
+				}
+			}
+			setPlanValue(pass, r.Path, secure, true)
+			var insecure any = invalidPolicyValue
+			if b, ok := r.Allowed[0].(bool); ok {
+				insecure = !b
+			}
+			setPlanValue(fail, r.Path, insecure, true)
+		}
+	}
+	return pass, fail
+}
+
+// setPlanValue sets path in a resource's planned values, where blocks are
+// lists of objects as in `terraform show -json`.
+func setPlanValue(after map[string]any, path string, v any, overwrite bool) {
+	steps := strings.Split(path, ".")
+	obj := after
+	for _, block := range steps[:len(steps)-1] {
+		list, _ := obj[block].([]any)
+		if len(list) == 0 {
+			list = []any{map[string]any{}}
+			obj[block] = list
+		}
+		obj = list[0].(map[string]any)
+	}
+	leaf := steps[len(steps)-1]
+	if _, ok := obj[leaf]; ok && !overwrite {
+		return
+	}
+	obj[leaf] = v
+}
+
+// resourceChange is a resource_changes entry of a plan planning after.
+func (ps *PolicySet) resourceChange(info *schema.ResourceInfo, after map[string]any) map[string]any {
+	return map[string]any{
+		"address":        fmt.Sprintf("module.%s.%s.this[0]", ps.Name, info.ResourceType),
+		"module_address": "module." + ps.Name,
+		"mode":           "managed",
+		"type":           info.ResourceType,
+		"name":           "this",
+		"index":          0,
+		"change": map[string]any{
+			"actions": []any{"create"},
+			"after":   after,
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Sentinel
+// ---------------------------------------------------------------------------
+
+func (ps *PolicySet) writeSentinel(info *schema.ResourceInfo, pass, fail map[string]any) error {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Starter policy for %s, generated from the module's variables and the\n", info.ResourceType))
+	b.WriteString("# DPaaS security baseline. Review the allowed values before enforcing it.\n\n")
+	b.WriteString("import \"tfplan/v2\" as tfplan\n\n")
+	b.WriteString("resources = filter tfplan.resource_changes as _, rc {\n")
+	b.WriteString(fmt.Sprintf("\trc.type is %q and\n", info.ResourceType))
+	b.WriteString("\t\trc.mode is \"managed\" and\n")
+	b.WriteString("\t\t(rc.change.actions contains \"create\" or rc.change.actions contains \"update\")\n")
+	b.WriteString("}\n")
+
+	var names []string
+	for _, r := range ps.Rules {
+		names = append(names, r.Name)
+		b.WriteString("\n")
+		if r.Kind == "tags" {
+			b.WriteString(fmt.Sprintf("required_tag_keys = %s\n\n", jsonList(r.Allowed)))
+		}
+		b.WriteString(fmt.Sprintf("# %s\n", r.description()))
+		b.WriteString(fmt.Sprintf("%s = rule {\n", r.Name))
+		b.WriteString("\tall resources as _, r {\n")
+		b.WriteString(sentinelCondition(r, "r.change.after", strings.Split(r.Path, "."), 2, 1))
+		b.WriteString("\t}\n}\n")
+	}
+	b.WriteString("\nmain = rule {\n\t")
+	b.WriteString(strings.Join(names, " and\n\t"))
+	b.WriteString("\n}\n")
+	ps.Files[ps.Name+".sentinel"] = b.String()
+
+	cfg := hclwrite.NewEmptyFile()
+	policy := cfg.Body().AppendNewBlock("policy", []string{ps.Name}).Body()
+	policy.SetAttributeValue("source", cty.StringVal("./"+ps.Name+".sentinel"))
+	policy.SetAttributeValue("enforcement_level", cty.StringVal("advisory"))
+	ps.Files["sentinel.hcl"] = string(hclwrite.Format(cfg.Bytes()))
+
+	for _, fx := range []struct {
+		name   string
+		after  map[string]any
+		passes bool
+	}{{"pass", pass, true}, {"fail", fail, false}} {
+		mock, err := json.MarshalIndent(map[string]any{
+			ps.resourceChange(info, fx.after)["address"].(string): ps.resourceChange(info, fx.after),
+		}, "", "\t")
+		if err != nil {
+			return err
+		}
+		mockFile := fmt.Sprintf("mock-tfplan-%s.sentinel", fx.name)
+		ps.Files["testdata/"+mockFile] = "resource_changes = " + string(mock) + "\n"
+
+		f := hclwrite.NewEmptyFile()
+		module := f.Body().AppendNewBlock("mock", []string{"tfplan/v2"}).Body().AppendNewBlock("module", nil).Body()
+		module.SetAttributeValue("source", cty.StringVal("../../testdata/"+mockFile))
+		f.Body().AppendNewline()
+		rules := map[string]cty.Value{"main": cty.BoolVal(fx.passes)}
+		if !fx.passes {
+			// every rule is broken by the failing fixture
+			for _, name := range names {
+				rules[name] = cty.False
+			}
+		}
+		f.Body().AppendNewBlock("test", nil).Body().SetAttributeValue("rules", cty.ObjectVal(rules))
+		ps.Files[fmt.Sprintf("test/%s/%s.hcl", ps.Name, fx.name)] = string(hclwrite.Format(f.Bytes()))
+	}
+	return nil
+}
+
+// sentinelCondition renders a rule's condition on the value at path under
+// base, iterating over the blocks on the way.
+func sentinelCondition(r PolicyRule, base string, path []string, indent, depth int) string {
+	tabs := strings.Repeat("\t", indent)
+	if len(path) > 1 {
+		iter := fmt.Sprintf("b%d", depth)
+		return fmt.Sprintf("%sall (%s.%s else []) as %s {\n%s%s}\n", tabs, base, path[0], iter,
+			sentinelCondition(r, iter, path[1:], indent+1, depth+1), tabs)
+	}
+	v := fmt.Sprintf("(%s.%s else null)", base, path[0])
+	switch {
+	case r.Kind == "tags":
+		return fmt.Sprintf("%s%s is not null and all required_tag_keys as t { %s.%s contains t }\n", tabs, v, base, path[0])
+	case r.NullOK:
+		return fmt.Sprintf("%s%s is null or %s in %s\n", tabs, v, v, jsonList(r.Allowed))
+	}
+	return fmt.Sprintf("%s%s in %s\n", tabs, v, jsonList(r.Allowed))
+}
+
+// ---------------------------------------------------------------------------
+// Rego
+// ---------------------------------------------------------------------------
+
+func (ps *PolicySet) writeRego(info *schema.ResourceInfo, pass, fail map[string]any) error {
+	pkg := "dpaas." + ps.Name
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Starter policy for %s, generated from the module's variables and the\n", info.ResourceType))
+	b.WriteString("# DPaaS security baseline. Review the allowed values before enforcing it.\n")
+	b.WriteString(fmt.Sprintf("package %s\n\nimport rego.v1\n\n", pkg))
+	b.WriteString("resources contains r if {\n")
+	b.WriteString("\tsome r in input.resource_changes\n")
+	b.WriteString(fmt.Sprintf("\tr.type == %q\n", info.ResourceType))
+	b.WriteString("\tr.mode == \"managed\"\n")
+	b.WriteString("\tsome action in r.change.actions\n")
+	b.WriteString("\taction in {\"create\", \"update\"}\n")
+	b.WriteString("}\n")
+
+	denials := 0
+	for _, r := range ps.Rules {
+		b.WriteString("\n")
+		steps := strings.Split(r.Path, ".")
+		if r.Kind == "tags" {
+			b.WriteString(fmt.Sprintf("required_tag_keys := %s\n\n", regoSet(r.Allowed)))
+		}
+		b.WriteString(fmt.Sprintf("# %s\n", r.description()))
+		b.WriteString("deny contains msg if {\n")
+		b.WriteString("\tsome r in resources\n")
+		base := "r.change.after"
+		for i, block := range steps[:len(steps)-1] {
+			iter := fmt.Sprintf("b%d", i+1)
+			b.WriteString(fmt.Sprintf("\tsome %s in object.get(%s, %q, [])\n", iter, base, block))
+			base = iter
+		}
+		leaf := steps[len(steps)-1]
+		switch r.Kind {
+		case "tags":
+			b.WriteString("\tsome tag in required_tag_keys\n")
+			b.WriteString(fmt.Sprintf("\tnot %s.%s[tag]\n", base, leaf))
+			b.WriteString(fmt.Sprintf("\tmsg := sprintf(\"[%s] %%s: tag %%s is missing\", [r.address, tag])\n", r.Name))
+			denials += len(r.Allowed)
+		default:
+			b.WriteString(fmt.Sprintf("\tv := object.get(%s, %q, null)\n", base, leaf))
+			if r.NullOK {
+				b.WriteString("\tv != null\n")
+			}
+			b.WriteString(fmt.Sprintf("\tnot v in %s\n", regoSet(r.Allowed)))
+			b.WriteString(fmt.Sprintf("\tmsg := sprintf(\"[%s] %%s: %s is %%v\", [r.address, v])\n", r.Name, r.Path))
+			denials++
+		}
+		b.WriteString("}\n")
+	}
+	ps.Files[ps.Name+".rego"] = b.String()
+
+	passPlan, err := json.MarshalIndent(map[string]any{"resource_changes": []any{ps.resourceChange(info, pass)}}, "", "\t")
+	if err != nil {
+		return err
+	}
+	failPlan, err := json.MarshalIndent(map[string]any{"resource_changes": []any{ps.resourceChange(info, fail)}}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	var t strings.Builder
+	t.WriteString(fmt.Sprintf("package %s_test\n\nimport rego.v1\n\nimport data.%s\n\n", pkg, pkg))
+	t.WriteString("test_compliant_plan_is_allowed if {\n")
+	t.WriteString(fmt.Sprintf("\tcount(%s.deny) == 0 with input as pass_plan\n", ps.Name))
+	t.WriteString("}\n\n")
+	t.WriteString("test_every_rule_denies if {\n")
+	t.WriteString(fmt.Sprintf("\tcount(%s.deny) == %d with input as fail_plan\n", ps.Name, denials))
+	t.WriteString("}\n\n")
+	t.WriteString("pass_plan := " + string(passPlan) + "\n\n")
+	t.WriteString("fail_plan := " + string(failPlan) + "\n")
+	ps.Files[ps.Name+"_test.rego"] = t.String()
+	return nil
+}
+
+// description is the comment above a rule in every language.
+func (r PolicyRule) description() string {
+	switch r.Kind {
+	case "enum":
+		return fmt.Sprintf("%s is one of the documented values", r.Path)
+	case "tags":
+		return "every required tag is set"
+	}
+	if r.NullOK {
+		return fmt.Sprintf("%s is left at its secure default or set to a secure value", r.Path)
+	}
+	return fmt.Sprintf("%s is set to a secure value", r.Path)
+}
+
+func ruleName(path string) string {
+	return strings.ReplaceAll(path, ".", "_")
+}
+
+func hasAttr(info *schema.ResourceInfo, name string) bool {
+	for _, a := range info.Attributes {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonList renders values as a list literal, valid in both Sentinel and Rego.
+func jsonList(values []any) string {
+	return "[" + jsonElements(values) + "]"
+}
+
+func regoSet(values []any) string {
+	return "{" + jsonElements(values) + "}"
+}
+
+func jsonElements(values []any) string {
+	var out []string
+	for _, v := range values {
+		raw, _ := json.Marshal(v)
+		out = append(out, string(raw))
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,60 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePolicies(t *testing.T) {
+	info := schematest.StorageAccount()
+	ps, err := GeneratePolicies(info, PolicyOptions{
+		Sentinel:     true,
+		Rego:         true,
+		RequiredTags: []string{"AppID", "innersource"},
+		Security: []PolicySetting{
+			{Path: "min_tls_version", Values: []any{"1.2", "TLS1_2"}, NullOK: true},
+			{Path: "public_network_access_enabled", Values: []any{false}},
+		},
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for _, r := range ps.Rules {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"account_tier_allowed", "min_tls_version_allowed", "required_tags", "min_tls_version_secure", "public_network_access_enabled_secure"}, names)
+
+	sentinel := ps.Files["storage_account.sentinel"]
+	assert.Contains(t, sentinel, `import "tfplan/v2" as tfplan`)
+	assert.Contains(t, sentinel, `rc.type is "azurerm_storage_account"`)
+	assert.Contains(t, sentinel, `(r.change.after.account_tier else null) is null or (r.change.after.account_tier else null) in ["Standard", "Premium"]`)
+	assert.Contains(t, sentinel, `(r.change.after.public_network_access_enabled else null) in [false]`)
+	assert.Contains(t, sentinel, `all required_tag_keys as t { r.change.after.tags contains t }`)
+	assert.Contains(t, sentinel, "main = rule {\n\taccount_tier_allowed and")
+	assert.Contains(t, ps.Files["sentinel.hcl"], `source            = "./storage_account.sentinel"`)
+	assert.Contains(t, ps.Files["test/storage_account/fail.hcl"], "public_network_access_enabled_secure = false")
+	assert.Contains(t, ps.Files["test/storage_account/pass.hcl"], `source = "../../testdata/mock-tfplan-pass.sentinel"`)
+	assert.Contains(t, ps.Files["testdata/mock-tfplan-fail.sentinel"], `"public_network_access_enabled": true`)
+	assert.Contains(t, ps.Files["testdata/mock-tfplan-pass.sentinel"], `"min_tls_version": "TLS1_2"`, "a secure value the enum allows")
+
+	rego := ps.Files["storage_account.rego"]
+	assert.Contains(t, rego, "package dpaas.storage_account")
+	assert.Contains(t, rego, `required_tag_keys := {"AppID", "innersource"}`)
+	assert.Contains(t, rego, "\tv := object.get(r.change.after, \"account_tier\", null)\n\tv != null\n\tnot v in {\"Standard\", \"Premium\"}\n")
+	assert.Contains(t, rego, "\tv := object.get(r.change.after, \"public_network_access_enabled\", null)\n\tnot v in {false}\n")
+	// 4 value rules and one denial per missing tag
+	assert.Contains(t, ps.Files["storage_account_test.rego"], "count(storage_account.deny) == 6 with input as fail_plan")
+}
+
+func TestGeneratePolicies_Rejects(t *testing.T) {
+	_, err := GeneratePolicies(schematest.StorageAccount(), PolicyOptions{})
+	assert.ErrorContains(t, err, "select at least one policy language")
+
+	res, _ := schematest.StorageAccountWithContainer()
+	res.ResourceType = "azapi_resource"
+	_, err = GeneratePolicies(res, PolicyOptions{Rego: true})
+	assert.ErrorContains(t, err, "azurerm resources")
+}
@@ -3,83 +3,161 @@ package generators
 import (
 	"fmt"
 	"strings"
-	"time"
 
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 )
 
-func GenerateReadme(info *schema.ResourceInfo) string {
-	var b strings.Builder
-
-	b.WriteString(fmt.Sprintf("# EITS Cloud Enablement Azure %s Module\n\n", info.DisplayName))
-	b.WriteString(fmt.Sprintf("EITS Terraform module which creates [Azure %s] resources. This module will:\n\n", info.DisplayName))
-	b.WriteString(fmt.Sprintf("- Deploy Azure %s with configurable options\n", info.DisplayName))
-	b.WriteString("- Support both custom naming and auto-generated names using null-label\n")
-	b.WriteString("- Apply standardized tagging and security policies\n")
-	b.WriteString("- Support conditional resource creation\n\n")
-	b.WriteString("See CHANGELOG.md for the list of changes for each release.\n")
-	b.WriteString("*We highly recommend that in your code you pin the version to the exact version you are using so that your infrastructure remains stable, and update versions in a systematic way so that they do not catch you by surprise.*\n\n")
-
-	b.WriteString("## Notes\n\n")
-	b.WriteString(fmt.Sprintf("- Null-label naming convention support for standardized resource names\n"))
-	b.WriteString(fmt.Sprintf("- Conditional resource creation using `create_%s` parameter\n", info.ShortName))
-	b.WriteString("- Standardized DPaaS tagging applied automatically\n\n")
-
-	// Security section
-	b.WriteString("## EITS Security & Compliance\n\n")
-	b.WriteString("**Last Module Review**: " + time.Now().Format("2006-01-02") + "\n\n")
-	b.WriteString("See below for the date and results of our EITS security and compliance scanning.\n\n")
-	b.WriteString("<!-- BEGIN_BENCHMARK_TABLE -->\n")
-	b.WriteString("| Benchmark | Date | Version | Description |\n")
-	b.WriteString("| --------- | ---- | ------- | ----------- |\n")
-	b.WriteString(fmt.Sprintf("| [![tflint](https://img.shields.io/badge/tflint-passed-green)]() | %s | 0.58.1 | Enforces best practices, syntax, naming conventions |\n", time.Now().Format("2006-01-02")))
-	b.WriteString(fmt.Sprintf("| [![trivy](https://img.shields.io/badge/trivy-passed-green)]() | %s | 0.61.0 | Detects misconfiguration in IaC files, such as Docker, Terraform, etc |\n", time.Now().Format("2006-01-02")))
-	b.WriteString(fmt.Sprintf("| [![checkov](https://img.shields.io/badge/checkov-passed-green)]() | %s | 3.2.464 | Deeper tfplan scanning for security and compliance issues |\n", time.Now().Format("2006-01-02")))
-	b.WriteString(fmt.Sprintf("| [![wiz](https://img.shields.io/badge/wiz.io_iac-passed-green)]() | %s | 0.84.0 | Scans tests directory plans for vulnerabilities and risks |\n", time.Now().Format("2006-01-02")))
-	b.WriteString("<!-- END_BENCHMARK_TABLE -->\n\n")
-
-	// Usage section
-	b.WriteString("## Usage\n\n")
-	b.WriteString("```hcl\n")
-	b.WriteString(generateUsageExample(info))
-	b.WriteString("```\n\n")
-
-	// Contact
-	b.WriteString("## Contact\n\n")
-	b.WriteString("For advice or to report an issue, either email the EITS Cloud Enablement team <eitsukicloud@experian.com> or post in the [Terraform Modules Teams Channel](https://teams.microsoft.com/l/channel/19%3a8c4faa258cd54d2687caa746f71ae050%40thread.tacv2/Terraform%2520Modules?groupId=c08d819b-fd4a-44e1-98f1-225d1bb48b31&tenantId=be67623c-1932-42a6-9d24-6c359fe5ea71)\n\n")
-
-	// Acknowledgments
-	b.WriteString("## Acknowledgments\n\n")
-	b.WriteString("Thanks to the Data Platform and Analytics team for the module development. This module follows EITS cloud enablement standards and best practices.\n\n")
-
-	// tf-docs section
-	b.WriteString("<!-- BEGIN_TF_DOCS -->\n")
-	b.WriteString("<!-- END_TF_DOCS -->\n")
+// GenerateReadme renders README.md from the template pack. The benchmark
+// table, usage example, examples list and terraform-docs markers are
+// generated and handed to the template.
+func GenerateReadme(info *schema.ResourceInfo, pack *TemplatePack) (string, error) {
+	usage, err := generateUsageExample(info, pack)
+	if err != nil {
+		return "", err
+	}
+	var examples strings.Builder
+	for _, ex := range usageExamples {
+		examples.WriteString(fmt.Sprintf("- [%s](examples/%s) - %s\n", ex.title, ex.name, ex.description))
+	}
 
-	return b.String()
+	readme, err := pack.renderData(packReadmeTemplate, &PackData{
+		ResourceInfo:   info,
+		LastReview:     lastReviewPrefix + "Not yet reviewed",
+		BenchmarkTable: BenchmarkTable(nil),
+		Usage:          usage,
+		Examples:       examples.String(),
+		// filled in from the generated files by TerraformDocs
+		TerraformDocs: tfDocsBegin + "\n" + tfDocsEnd,
+	}, "")
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(readme, tfDocsBegin) {
+		readme += "\n" + tfDocsBegin + "\n" + tfDocsEnd + "\n"
+	}
+	return readme, nil
 }
 
-func generateUsageExample(info *schema.ResourceInfo) string {
+func generateUsageExample(info *schema.ResourceInfo, pack *TemplatePack) (string, error) {
 	var b strings.Builder
 
+	source, err := pack.ModuleSource(info)
+	if err != nil {
+		return "", err
+	}
 	moduleName := strings.ReplaceAll(info.ShortName, "_", "_")
 
 	b.WriteString(fmt.Sprintf("module \"%s\" {\n", moduleName))
-	b.WriteString(fmt.Sprintf("  source = \"git::https://code.experian.local/scm/DPAAS/%s.git\"\n\n", info.ModuleName))
+	b.WriteString(fmt.Sprintf("  source = %s\n\n", hclQuote(source)))
 	b.WriteString(fmt.Sprintf("  create_%s = true\n", info.ShortName))
 	b.WriteString("  enabled            = true\n\n")
-	b.WriteString("  namespace   = \"expn\"\n")
-	b.WriteString("  tenant      = \"msp\"\n")
-	b.WriteString("  environment = \"sbx\"\n")
-	b.WriteString("  name        = \"sample\"\n\n")
-	b.WriteString("  location            = \"East US 2\"\n")
+	writeLabelInputs(&b, pack, "sample")
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  location            = %s\n", hclQuote(pack.Defaults.Location)))
 	b.WriteString("  resource_group_name = \"example-rg\"\n\n")
+	writeTags(&b, pack, false)
+	b.WriteString("}\n")
+
+	return b.String(), nil
+}
+
+// writeLabelInputs writes the pack's null-label inputs of a module call.
+func writeLabelInputs(b *strings.Builder, pack *TemplatePack, name string) {
+	b.WriteString(fmt.Sprintf("  namespace   = %s\n", hclQuote(pack.Label.Namespace)))
+	b.WriteString(fmt.Sprintf("  tenant      = %s\n", hclQuote(pack.Label.Tenant)))
+	b.WriteString(fmt.Sprintf("  environment = %s\n", hclQuote(pack.Label.Environment)))
+	b.WriteString(fmt.Sprintf("  name        = %s\n", hclQuote(name)))
+}
+
+// writeTags writes the tags argument of a module call with the pack's tags,
+// with the keys quoted or bare.
+func writeTags(b *strings.Builder, pack *TemplatePack, quoteKeys bool) {
+	keys := make([]string, 0, len(pack.Tags))
+	width := 0
+	for _, k := range pack.TagNames() {
+		if quoteKeys || !hclsyntax.ValidIdentifier(k) {
+			k = hclQuote(k)
+		}
+		keys = append(keys, k)
+		width = max(width, len(k))
+	}
 	b.WriteString("  tags = {\n")
-	b.WriteString("    CostString  = \"0000.111.11.22\"\n")
-	b.WriteString("    AppID       = \"0\"\n")
-	b.WriteString("    Environment = \"sbx\"\n")
+	for i, k := range pack.TagNames() {
+		b.WriteString(fmt.Sprintf("    %-*s = %s\n", width, keys[i], hclQuote(pack.Tags[k])))
+	}
 	b.WriteString("  }\n")
-	b.WriteString("}\n")
+}
 
+const (
+	benchmarkBegin   = "<!-- BEGIN_BENCHMARK_TABLE -->"
+	benchmarkEnd     = "<!-- END_BENCHMARK_TABLE -->"
+	lastReviewPrefix = "**Last Module Review**: "
+)
+
+// BenchmarkResult is one row of the README benchmark table: a scan that was
+// actually run against the module and what it found.
+type BenchmarkResult struct {
+	Name        string
+	Date        string // YYYY-MM-DD
+	Version     string
+	Description string
+	Findings    int  // warnings and infos; shown as a yellow badge
+	Failed      bool // any error-severity finding; shown as a red badge
+}
+
+// BenchmarkTable renders the benchmark table between its markers. With no
+// results it renders a placeholder row rather than claiming scans passed.
+func BenchmarkTable(results []BenchmarkResult) string {
+	var b strings.Builder
+	b.WriteString(benchmarkBegin + "\n")
+	b.WriteString("| Benchmark | Date | Version | Description |\n")
+	b.WriteString("| --------- | ---- | ------- | ----------- |\n")
+	if len(results) == 0 {
+		b.WriteString("| Not yet scanned | - | - | Run dpaas_validate_module with update_readme to record results |\n")
+	}
+	for _, r := range results {
+		status := "passed-green"
+		switch {
+		case r.Failed:
+			status = "failed-red"
+		case r.Findings == 1:
+			status = "1_finding-yellow"
+		case r.Findings > 1:
+			status = fmt.Sprintf("%d_findings-yellow", r.Findings)
+		}
+		b.WriteString(fmt.Sprintf("| [![%s](https://img.shields.io/badge/%s-%s)]() | %s | %s | %s |\n",
+			r.Name, badgeText(r.Name), status, r.Date, r.Version, r.Description))
+	}
+	b.WriteString(benchmarkEnd + "\n")
 	return b.String()
 }
+
+// UpdateReadmeBenchmarks replaces the benchmark table and the last review date
+// in an existing README. It reports false when the README has no table markers.
+func UpdateReadmeBenchmarks(readme string, results []BenchmarkResult, reviewed string) (string, bool) {
+	start := strings.Index(readme, benchmarkBegin)
+	end := strings.Index(readme, benchmarkEnd)
+	if start < 0 || end < start {
+		return readme, false
+	}
+	end += len(benchmarkEnd)
+	if end < len(readme) && readme[end] == '\n' {
+		end++
+	}
+	readme = readme[:start] + BenchmarkTable(results) + readme[end:]
+
+	if i := strings.Index(readme, lastReviewPrefix); i >= 0 {
+		eol := strings.IndexByte(readme[i:], '\n')
+		if eol < 0 {
+			eol = len(readme) - i
+		}
+		readme = readme[:i] + lastReviewPrefix + reviewed + readme[i+eol:]
+	}
+	return readme, true
+}
+
+// badgeText escapes a label for a shields.io static badge path.
+func badgeText(s string) string {
+	return strings.NewReplacer("-", "--", "_", "__", " ", "_").Replace(s)
+}
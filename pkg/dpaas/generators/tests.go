@@ -4,37 +4,140 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/templates"
 )
 
-func GenerateTests(info *schema.ResourceInfo, scenarios []string) map[string]string {
+// GenerateTests returns the example root modules under tests/<scenario>/ for
+// the requested built-in and user-defined scenarios, plus the
+// tests/*.tftest.hcl files.
+func GenerateTests(info *schema.ResourceInfo, opts Options) (map[string]string, error) {
 	files := map[string]string{}
+	opts = opts.resolved()
+	d := opts.Defaults
+
+	versions, err := GenerateTestVersionsTf(d, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	scenarioSet := map[string]bool{}
-	for _, s := range scenarios {
+	for _, s := range opts.Scenarios {
 		scenarioSet[s] = true
 	}
 
+	// newEngine returns the example engine for one scenario; each scenario
+	// records the fixtures its own module call references.
+	newEngine := func() *exampleEngine {
+		if opts.Fixtures {
+			return newFixtureEngine(info, opts)
+		}
+		return newExampleEngine(info, opts)
+	}
+	addScenario := func(name, main, versions string, e *exampleEngine) {
+		files["tests/"+name+"/main.tf"] = main
+		files["tests/"+name+"/versions.tf"] = versions
+		if fx := generateFixturesTf(e, strings.ReplaceAll(name, "_", "-")); fx != "" {
+			files["tests/"+name+"/fixtures.tf"] = fx
+		}
+	}
+
 	if scenarioSet["default"] {
-		files["tests/default/main.tf"] = generateDefaultTest(info)
-		files["tests/default/versions.tf"] = templates.VersionsTestTf
+		e := newEngine()
+		addScenario("default", generateDefaultTest(info, e, "sample"), versions, e)
 	}
 
 	if scenarioSet["complete"] {
-		files["tests/complete/main.tf"] = generateCompleteTest(info)
-		files["tests/complete/versions.tf"] = templates.VersionsTestTf
+		e := newEngine()
+		addScenario("complete", generateCompleteTest(info, e, "complete"), versions, e)
 	}
 
 	if scenarioSet["disabled"] {
-		files["tests/disabled/main.tf"] = generateDisabledTest(info)
-		files["tests/disabled/versions.tf"] = templates.VersionsTestTf
+		// nothing is created when the module is disabled, so neither are fixtures
+		e := newExampleEngine(info, opts)
+		addScenario("disabled", generateDisabledTest(info, e), versions, e)
+	}
+
+	for _, sc := range opts.Custom {
+		e := newEngine()
+		main, err := generateCustomTest(info, e, sc)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s: %w", sc.Name, err)
+		}
+		scVersions, err := GenerateTestVersionsTf(d, sc.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s: provider: %w", sc.Name, err)
+		}
+		addScenario(sc.Name, main, scVersions, e)
 	}
 
-	return files
+	tfTests, err := GenerateTfTests(info, opts)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range tfTests {
+		files[k] = v
+	}
+
+	return files, nil
 }
 
-func generateDefaultTest(info *schema.ResourceInfo) string {
+// GenerateTestVersionsTf renders a scenario's versions.tf: the pinned
+// providers and a provider "azurerm" block for the default subscription with
+// the scenario's provider configuration merged over it.
+func GenerateTestVersionsTf(d ScenarioDefaults, provider map[string]any) (string, error) {
+	config := map[string]any{
+		"subscription_id": d.SubscriptionID,
+		"features":        map[string]any{},
+	}
+	for k, v := range provider {
+		config[k] = v
+	}
+
+	f := hclwrite.NewEmptyFile()
+	if err := writeProviderBody(f.Body().AppendNewBlock("provider", []string{"azurerm"}).Body(), config); err != nil {
+		return "", err
+	}
+	return templates.VersionsTestTf + string(hclwrite.Format(f.Bytes())), nil
+}
+
+// generateCustomTest renders a user-defined scenario: the module call of its
+// base scenario with the scenario's variables set over it.
+func generateCustomTest(info *schema.ResourceInfo, e *exampleEngine, sc Scenario) (string, error) {
+	label := strings.ReplaceAll(sc.Name, "_", "-")
+	base := generateDefaultTest(info, e, label)
+	if sc.Base == "complete" {
+		base = generateCompleteTest(info, e, label)
+	}
+
+	f, diags := hclwrite.ParseConfig([]byte(base), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	var call *hclwrite.Block
+	for _, blk := range f.Body().Blocks() {
+		if blk.Type() == "module" {
+			call = blk
+			break
+		}
+	}
+	if call == nil {
+		return "", fmt.Errorf("no module call in the %s scenario", sc.Base)
+	}
+	if err := setAttributes(call.Body(), sc.Variables); err != nil {
+		return "", err
+	}
+
+	out := string(hclwrite.Format(f.Bytes()))
+	if sc.Description != "" {
+		out = "# " + sc.Description + "\n" + out
+	}
+	return out, nil
+}
+
+func generateDefaultTest(info *schema.ResourceInfo, e *exampleEngine, label string) string {
 	var b strings.Builder
 
 	moduleName := strings.ReplaceAll(info.ShortName, "_", "_")
@@ -42,10 +145,8 @@ func generateDefaultTest(info *schema.ResourceInfo) string {
 	b.WriteString(fmt.Sprintf("module \"%s\" {\n\n", moduleName))
 	b.WriteString("  source = \"../..\"\n\n")
 	b.WriteString("  enabled = true\n\n")
-	b.WriteString("  namespace   = \"expn\"\n")
-	b.WriteString("  tenant      = \"msp\"\n")
-	b.WriteString("  environment = \"sbx\"\n")
-	b.WriteString("  name        = \"sample\"\n\n")
+	writeLabelInputs(&b, e.pack, label)
+	b.WriteString("\n")
 
 	// Add the {resource}_name as a commented example (it's optional in the module)
 	resourceNameVar := info.ShortName + "_name"
@@ -65,10 +166,10 @@ func generateDefaultTest(info *schema.ResourceInfo) string {
 	}
 
 	if hasLocation {
-		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", "\"East US 2\""))
+		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", hclQuote(e.defaults.Location)))
 	}
 	if hasResourceGroupName {
-		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", "\"eits-Sandbox-mspsandbox-BU-07959a-rg\""))
+		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", e.resourceGroupName()))
 	}
 
 	// Collect required attributes (excluding standard ones)
@@ -84,8 +185,7 @@ func generateDefaultTest(info *schema.ResourceInfo) string {
 		b.WriteString("\n  # Required attributes\n")
 		for _, attr := range requiredAttrs {
 			varName := getVariableName(attr.Name, info.ShortName)
-			exampleValue := generateExampleValue(attr)
-			b.WriteString(fmt.Sprintf("  %-27s = %s\n", varName, exampleValue))
+			b.WriteString(fmt.Sprintf("  %-27s = %s\n", varName, e.value(attr.Name, attr)))
 		}
 	}
 
@@ -100,17 +200,14 @@ func generateDefaultTest(info *schema.ResourceInfo) string {
 	if len(requiredBlocks) > 0 {
 		b.WriteString("\n  # Required blocks\n")
 		for _, block := range requiredBlocks {
-			exampleBlock := generateExampleBlock(block)
+			exampleBlock := generateExampleBlock(e, block)
 			b.WriteString(exampleBlock)
 		}
 	}
 
 	// Tags
-	b.WriteString("\n  tags = {\n")
-	b.WriteString("    \"CostString\"  = \"0000.111.11.22\"\n")
-	b.WriteString("    \"AppID\"       = \"0\"\n")
-	b.WriteString("    \"Environment\" = \"sbx\"\n")
-	b.WriteString("  }\n")
+	b.WriteString("\n")
+	writeTags(&b, e.pack, true)
 	b.WriteString("}\n")
 
 	return b.String()
@@ -118,7 +215,7 @@ func generateDefaultTest(info *schema.ResourceInfo) string {
 
 // generateCompleteTest creates a test that sets ALL attributes and blocks.
 // Proves every variable the module exposes is wirable without syntax/type errors.
-func generateCompleteTest(info *schema.ResourceInfo) string {
+func generateCompleteTest(info *schema.ResourceInfo, e *exampleEngine, label string) string {
 	var b strings.Builder
 
 	moduleName := strings.ReplaceAll(info.ShortName, "_", "_")
@@ -126,10 +223,8 @@ func generateCompleteTest(info *schema.ResourceInfo) string {
 	b.WriteString(fmt.Sprintf("module \"%s\" {\n\n", moduleName))
 	b.WriteString("  source = \"../..\"\n\n")
 	b.WriteString("  enabled = true\n\n")
-	b.WriteString("  namespace   = \"expn\"\n")
-	b.WriteString("  tenant      = \"msp\"\n")
-	b.WriteString("  environment = \"sbx\"\n")
-	b.WriteString("  name        = \"complete\"\n\n")
+	writeLabelInputs(&b, e.pack, label)
+	b.WriteString("\n")
 
 	// Resource name
 	resourceNameVar := info.ShortName + "_name"
@@ -148,10 +243,10 @@ func generateCompleteTest(info *schema.ResourceInfo) string {
 		}
 	}
 	if hasLocation {
-		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", "\"East US 2\""))
+		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", hclQuote(e.defaults.Location)))
 	}
 	if hasResourceGroupName {
-		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", "\"eits-Sandbox-mspsandbox-BU-07959a-rg\""))
+		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", e.resourceGroupName()))
 	}
 
 	// All non-standard attributes (required + optional)
@@ -166,8 +261,7 @@ func generateCompleteTest(info *schema.ResourceInfo) string {
 		b.WriteString("\n  # All attributes\n")
 		for _, attr := range attrs {
 			varName := getVariableName(attr.Name, info.ShortName)
-			exampleValue := generateExampleValue(attr)
-			b.WriteString(fmt.Sprintf("  %-27s = %s\n", varName, exampleValue))
+			b.WriteString(fmt.Sprintf("  %-27s = %s\n", varName, e.value(attr.Name, attr)))
 		}
 	}
 
@@ -175,17 +269,14 @@ func generateCompleteTest(info *schema.ResourceInfo) string {
 	if len(info.Blocks) > 0 {
 		b.WriteString("\n  # All blocks\n")
 		for _, block := range info.Blocks {
-			exampleBlock := generateCompleteExampleBlock(block)
+			exampleBlock := generateCompleteExampleBlock(e, block)
 			b.WriteString(exampleBlock)
 		}
 	}
 
 	// Tags
-	b.WriteString("\n  tags = {\n")
-	b.WriteString("    \"CostString\"  = \"0000.111.11.22\"\n")
-	b.WriteString("    \"AppID\"       = \"0\"\n")
-	b.WriteString("    \"Environment\" = \"sbx\"\n")
-	b.WriteString("  }\n")
+	b.WriteString("\n")
+	writeTags(&b, e.pack, true)
 	b.WriteString("}\n")
 
 	return b.String()
@@ -193,7 +284,7 @@ func generateCompleteTest(info *schema.ResourceInfo) string {
 
 // generateDisabledTest creates a test with enabled=false.
 // Proves the module can be cleanly skipped (count=0) without errors.
-func generateDisabledTest(info *schema.ResourceInfo) string {
+func generateDisabledTest(info *schema.ResourceInfo, e *exampleEngine) string {
 	var b strings.Builder
 
 	moduleName := strings.ReplaceAll(info.ShortName, "_", "_")
@@ -201,10 +292,8 @@ func generateDisabledTest(info *schema.ResourceInfo) string {
 	b.WriteString(fmt.Sprintf("module \"%s\" {\n\n", moduleName))
 	b.WriteString("  source = \"../..\"\n\n")
 	b.WriteString("  enabled = false\n\n")
-	b.WriteString("  namespace   = \"expn\"\n")
-	b.WriteString("  tenant      = \"msp\"\n")
-	b.WriteString("  environment = \"sbx\"\n")
-	b.WriteString("  name        = \"disabled\"\n\n")
+	writeLabelInputs(&b, e.pack, "disabled")
+	b.WriteString("\n")
 
 	// Still need to provide required variables (they have no defaults)
 	hasLocation := false
@@ -219,10 +308,10 @@ func generateDisabledTest(info *schema.ResourceInfo) string {
 	}
 
 	if hasLocation {
-		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", "\"East US 2\""))
+		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", hclQuote(e.defaults.Location)))
 	}
 	if hasResourceGroupName {
-		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", "\"eits-Sandbox-mspsandbox-BU-07959a-rg\""))
+		b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", e.resourceGroupName()))
 	}
 
 	// Required attributes (no defaults, must be provided even when disabled)
@@ -237,8 +326,7 @@ func generateDisabledTest(info *schema.ResourceInfo) string {
 		b.WriteString("\n  # Required attributes (must be provided even when disabled)\n")
 		for _, attr := range requiredAttrs {
 			varName := getVariableName(attr.Name, info.ShortName)
-			exampleValue := generateExampleValue(attr)
-			b.WriteString(fmt.Sprintf("  %-27s = %s\n", varName, exampleValue))
+			b.WriteString(fmt.Sprintf("  %-27s = %s\n", varName, e.value(attr.Name, attr)))
 		}
 	}
 
@@ -253,17 +341,14 @@ func generateDisabledTest(info *schema.ResourceInfo) string {
 	if len(requiredBlocks) > 0 {
 		b.WriteString("\n  # Required blocks\n")
 		for _, block := range requiredBlocks {
-			exampleBlock := generateExampleBlock(block)
+			exampleBlock := generateExampleBlock(e, block)
 			b.WriteString(exampleBlock)
 		}
 	}
 
 	// Tags (required by validation even when disabled)
-	b.WriteString("\n  tags = {\n")
-	b.WriteString("    \"CostString\"  = \"0000.111.11.22\"\n")
-	b.WriteString("    \"AppID\"       = \"0\"\n")
-	b.WriteString("    \"Environment\" = \"sbx\"\n")
-	b.WriteString("  }\n")
+	b.WriteString("\n")
+	writeTags(&b, e.pack, true)
 	b.WriteString("}\n")
 
 	return b.String()
@@ -271,7 +356,7 @@ func generateDisabledTest(info *schema.ResourceInfo) string {
 
 // generateCompleteExampleBlock generates a block with ALL attributes (required + optional),
 // including nested blocks recursively.
-func generateCompleteExampleBlock(block schema.ParsedBlock) string {
+func generateCompleteExampleBlock(e *exampleEngine, block schema.ParsedBlock) string {
 	var b strings.Builder
 
 	isSingle := isSingleBlock(block)
@@ -279,12 +364,12 @@ func generateCompleteExampleBlock(block schema.ParsedBlock) string {
 
 	if isSingle {
 		b.WriteString(fmt.Sprintf("  %s = {\n", block.Name))
-		writeCompleteBlockContent(&b, block, "    ")
+		writeCompleteBlockContent(&b, e, block, block.Name, "    ")
 		b.WriteString("  }\n")
 	} else {
 		b.WriteString(fmt.Sprintf("  %s = {\n", block.Name))
 		b.WriteString(fmt.Sprintf("    %s = {\n", mapKey))
-		writeCompleteBlockContent(&b, block, "      ")
+		writeCompleteBlockContent(&b, e, block, block.Name, "      ")
 		b.WriteString("    }\n")
 		b.WriteString("  }\n")
 	}
@@ -292,10 +377,11 @@ func generateCompleteExampleBlock(block schema.ParsedBlock) string {
 	return b.String()
 }
 
-// writeCompleteBlockContent writes all attributes and nested blocks at the given indent level.
-func writeCompleteBlockContent(b *strings.Builder, block schema.ParsedBlock, indent string) {
+// writeCompleteBlockContent writes all attributes and nested blocks at the given
+// indent level. path is the block's schema path, e.g. "site_config.application_stack".
+func writeCompleteBlockContent(b *strings.Builder, e *exampleEngine, block schema.ParsedBlock, path, indent string) {
 	for _, attr := range block.Attributes {
-		exampleValue := generateExampleValue(attr)
+		exampleValue := e.value(path+"."+attr.Name, attr)
 		padding := strings.Repeat(" ", max(0, 25-len(attr.Name)))
 		b.WriteString(fmt.Sprintf("%s%s%s = %s\n", indent, attr.Name, padding, exampleValue))
 	}
@@ -303,14 +389,14 @@ func writeCompleteBlockContent(b *strings.Builder, block schema.ParsedBlock, ind
 		if isSingleBlock(nested) {
 			// Single nested block: direct object syntax
 			b.WriteString(fmt.Sprintf("%s%s = {\n", indent, nested.Name))
-			writeCompleteBlockContent(b, nested, indent+"  ")
+			writeCompleteBlockContent(b, e, nested, path+"."+nested.Name, indent+"  ")
 			b.WriteString(fmt.Sprintf("%s}\n", indent))
 		} else {
 			// Multi-value nested block: map syntax with named key
 			mapKey := fmt.Sprintf("%s-1", nested.Name)
 			b.WriteString(fmt.Sprintf("%s%s = {\n", indent, nested.Name))
 			b.WriteString(fmt.Sprintf("%s  %s = {\n", indent, mapKey))
-			writeCompleteBlockContent(b, nested, indent+"    ")
+			writeCompleteBlockContent(b, e, nested, path+"."+nested.Name, indent+"    ")
 			b.WriteString(fmt.Sprintf("%s  }\n", indent))
 			b.WriteString(fmt.Sprintf("%s}\n", indent))
 		}
@@ -332,45 +418,6 @@ func isStandardTestVar(name string) bool {
 	return name == "name" || name == "location" || name == "resource_group_name" || name == "tags" || name == "id"
 }
 
-// generateExampleValue creates a sensible example value for an attribute.
-// Uses pattern-based name matching derived from analysis of 1000+ azurerm resource schemas.
-// Priority: type → _id → enum → name patterns → fallback
-func generateExampleValue(attr schema.ParsedAttribute) string {
-	// ── bool ────────────────────────────────────────────────────────────────
-	if attr.TFType == "bool" {
-		return "true"
-	}
-
-	// ── number ─────────────────────────────────────────────────────────────
-	if attr.TFType == "number" {
-		return generateNumberValue(attr.Name)
-	}
-
-	// ── string ─────────────────────────────────────────────────────────────
-	if attr.TFType == "string" {
-		// _id fields → Azure resource ID format
-		if strings.HasSuffix(attr.Name, "_id") {
-			return fmt.Sprintf("\"%s\"", schema.GenerateAzureResourceID(attr.Name))
-		}
-
-		// Enum values from provider docs
-		if len(attr.EnumValues) > 0 {
-			return fmt.Sprintf("\"%s\"", attr.EnumValues[0])
-		}
-
-		return generateStringValue(attr.Name)
-	}
-
-	// ── list / set / map ───────────────────────────────────────────────────
-	if strings.HasPrefix(attr.TFType, "list(") || strings.HasPrefix(attr.TFType, "set(") {
-		return "[]"
-	}
-	if strings.HasPrefix(attr.TFType, "map(") {
-		return "{}"
-	}
-	return "null"
-}
-
 // generateNumberValue returns a realistic number based on the attribute name.
 func generateNumberValue(name string) string {
 	n := strings.ToLower(name)
@@ -640,7 +687,7 @@ func generateStringValue(name string) string {
 
 // generateExampleBlock creates an example configuration for a required block
 // Single blocks use object syntax, multi-value blocks use map of objects with named keys
-func generateExampleBlock(block schema.ParsedBlock) string {
+func generateExampleBlock(e *exampleEngine, block schema.ParsedBlock) string {
 	var b strings.Builder
 
 	// Check if this is a single block or multi-value block
@@ -658,7 +705,7 @@ func generateExampleBlock(block schema.ParsedBlock) string {
 		for _, attr := range block.Attributes {
 			if attr.Required {
 				hasRequired = true
-				exampleValue := generateExampleValue(attr)
+				exampleValue := e.value(block.Name+"."+attr.Name, attr)
 				padding := strings.Repeat(" ", max(0, 25-len(attr.Name)))
 				b.WriteString(fmt.Sprintf("    %s%s = %s\n", attr.Name, padding, exampleValue))
 			}
@@ -677,7 +724,7 @@ func generateExampleBlock(block schema.ParsedBlock) string {
 		for _, attr := range block.Attributes {
 			if attr.Required {
 				hasRequired = true
-				exampleValue := generateExampleValue(attr)
+				exampleValue := e.value(block.Name+"."+attr.Name, attr)
 				padding := strings.Repeat(" ", max(0, 25-len(attr.Name)))
 				b.WriteString(fmt.Sprintf("      %s%s = %s\n", attr.Name, padding, exampleValue))
 			}
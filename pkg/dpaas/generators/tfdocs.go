@@ -0,0 +1,271 @@
+package generators
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	tfDocsBegin = "<!-- BEGIN_TF_DOCS -->"
+	tfDocsEnd   = "<!-- END_TF_DOCS -->"
+)
+
+// tfDocsModule is what the README tables document, read from the module's
+// root .tf files the way terraform-docs reads them.
+type tfDocsModule struct {
+	terraformVersion string
+	requirements     map[string]string // provider name → version constraint
+	resources        []tfDocsResource
+	modules          []tfDocsCall
+	inputs           []tfDocsInput
+	outputs          []tfDocsOutput
+}
+
+type tfDocsResource struct {
+	mode, typ, name string // mode is "resource" or "data"
+}
+
+type tfDocsCall struct {
+	name, source, version string
+}
+
+type tfDocsInput struct {
+	name, description, typ, dflt string
+	required                     bool
+}
+
+type tfDocsOutput struct {
+	name, description string
+}
+
+// TerraformDocs renders the Requirements, Providers, Modules, Resources,
+// Inputs and Outputs tables for a module's root .tf files in the
+// terraform-docs markdown table format, between the TF_DOCS markers.
+func TerraformDocs(files map[string]string) (string, error) {
+	m := tfDocsModule{requirements: map[string]string{}}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if path.Dir(name) == "." && strings.HasSuffix(name, ".tf") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := m.read(name, []byte(files[name])); err != nil {
+			return "", err
+		}
+	}
+	return m.render(), nil
+}
+
+func (m *tfDocsModule) read(filename string, src []byte) error {
+	f, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+	text := func(expr hcl.Expression) string {
+		rng := expr.Range()
+		return string(src[rng.Start.Byte:rng.End.Byte])
+	}
+	str := func(attrs hclsyntax.Attributes, name string) string {
+		a, ok := attrs[name]
+		if !ok {
+			return ""
+		}
+		v, diags := a.Expr.Value(nil)
+		if diags.HasErrors() || v.IsNull() || v.Type() != cty.String {
+			return text(a.Expr)
+		}
+		return strings.TrimSpace(v.AsString())
+	}
+
+	for _, blk := range f.Body.(*hclsyntax.Body).Blocks {
+		attrs := blk.Body.Attributes
+		switch blk.Type {
+		case "terraform":
+			if v := str(attrs, "required_version"); v != "" {
+				m.terraformVersion = v
+			}
+			for _, rp := range blk.Body.Blocks {
+				if rp.Type != "required_providers" {
+					continue
+				}
+				for name, a := range rp.Body.Attributes {
+					obj, ok := a.Expr.(*hclsyntax.ObjectConsExpr)
+					if !ok {
+						continue
+					}
+					for _, item := range obj.Items {
+						if k, _ := item.KeyExpr.Value(nil); k.Type() == cty.String && k.AsString() == "version" {
+							if v, _ := item.ValueExpr.Value(nil); v.Type() == cty.String && v.IsKnown() && !v.IsNull() {
+								m.requirements[name] = v.AsString()
+							}
+						}
+					}
+				}
+			}
+		case "resource", "data":
+			m.resources = append(m.resources, tfDocsResource{mode: blk.Type, typ: blk.Labels[0], name: blk.Labels[1]})
+		case "module":
+			m.modules = append(m.modules, tfDocsCall{name: blk.Labels[0], source: str(attrs, "source"), version: str(attrs, "version")})
+		case "variable":
+			in := tfDocsInput{name: blk.Labels[0], description: str(attrs, "description"), typ: "any", required: true}
+			if a, ok := attrs["type"]; ok {
+				in.typ = text(a.Expr)
+			}
+			if a, ok := attrs["default"]; ok {
+				in.dflt = text(a.Expr)
+				in.required = false
+			}
+			m.inputs = append(m.inputs, in)
+		case "output":
+			m.outputs = append(m.outputs, tfDocsOutput{name: blk.Labels[0], description: str(attrs, "description")})
+		}
+	}
+	return nil
+}
+
+func (m *tfDocsModule) render() string {
+	var b strings.Builder
+	b.WriteString(tfDocsBegin + "\n")
+
+	b.WriteString("## Requirements\n\n")
+	b.WriteString("| Name | Version |\n")
+	b.WriteString("|------|---------|\n")
+	if m.terraformVersion != "" {
+		b.WriteString(fmt.Sprintf("| %s | %s |\n", tfDocsAnchor("requirement", "terraform"), m.terraformVersion))
+	}
+	for _, name := range sortedKeys(m.requirements) {
+		b.WriteString(fmt.Sprintf("| %s | %s |\n", tfDocsAnchor("requirement", name), m.requirements[name]))
+	}
+
+	// providers are the ones the module's resources use
+	used := map[string]bool{}
+	for _, r := range m.resources {
+		used[strings.SplitN(r.typ, "_", 2)[0]] = true
+	}
+	b.WriteString("\n## Providers\n\n")
+	b.WriteString("| Name | Version |\n")
+	b.WriteString("|------|---------|\n")
+	for _, name := range sortedKeys(used) {
+		version := m.requirements[name]
+		if version == "" {
+			version = "n/a"
+		}
+		b.WriteString(fmt.Sprintf("| %s | %s |\n", tfDocsAnchor("provider", name), version))
+	}
+
+	b.WriteString("\n## Modules\n\n")
+	b.WriteString("| Name | Source | Version |\n")
+	b.WriteString("|------|--------|---------|\n")
+	sort.Slice(m.modules, func(i, j int) bool { return m.modules[i].name < m.modules[j].name })
+	for _, c := range m.modules {
+		version := c.version
+		if version == "" {
+			version = "n/a"
+		}
+		b.WriteString(fmt.Sprintf("| %s | %s | %s |\n", tfDocsAnchor("module", c.name), c.source, version))
+	}
+
+	b.WriteString("\n## Resources\n\n")
+	b.WriteString("| Name | Type |\n")
+	b.WriteString("|------|------|\n")
+	sort.Slice(m.resources, func(i, j int) bool {
+		a, c := m.resources[i], m.resources[j]
+		if a.typ != c.typ {
+			return a.typ < c.typ
+		}
+		return a.name < c.name
+	})
+	for _, r := range m.resources {
+		provider, short, _ := strings.Cut(r.typ, "_")
+		kind, docs, addr := "resource", "resources", r.typ+"."+r.name
+		if r.mode == "data" {
+			kind, docs, addr = "data source", "data-sources", "data."+addr
+		}
+		b.WriteString(fmt.Sprintf("| [%s](https://registry.terraform.io/providers/hashicorp/%s/latest/docs/%s/%s) | %s |\n",
+			addr, provider, docs, short, kind))
+	}
+
+	b.WriteString("\n## Inputs\n\n")
+	b.WriteString("| Name | Description | Type | Default | Required |\n")
+	b.WriteString("|------|-------------|------|---------|:--------:|\n")
+	sort.Slice(m.inputs, func(i, j int) bool { return m.inputs[i].name < m.inputs[j].name })
+	for _, in := range m.inputs {
+		dflt, required := "n/a", "yes"
+		if !in.required {
+			dflt, required = tfDocsCode(in.dflt), "no"
+		}
+		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			tfDocsAnchor("input", in.name), tfDocsText(in.description), tfDocsCode(in.typ), dflt, required))
+	}
+
+	b.WriteString("\n## Outputs\n\n")
+	b.WriteString("| Name | Description |\n")
+	b.WriteString("|------|-------------|\n")
+	sort.Slice(m.outputs, func(i, j int) bool { return m.outputs[i].name < m.outputs[j].name })
+	for _, out := range m.outputs {
+		b.WriteString(fmt.Sprintf("| %s | %s |\n", tfDocsAnchor("output", out.name), tfDocsText(out.description)))
+	}
+
+	b.WriteString(tfDocsEnd + "\n")
+	return b.String()
+}
+
+// tfDocsAnchor renders a linkable name cell, as terraform-docs does.
+func tfDocsAnchor(kind, name string) string {
+	id := kind + "_" + name
+	escape := func(s string) string { return strings.ReplaceAll(s, "_", `\_`) }
+	return fmt.Sprintf(`<a name="%s"></a> [%s](#%s)`, id, escape(name), escape(id))
+}
+
+// tfDocsText makes a description fit in a table cell.
+func tfDocsText(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br/>")
+}
+
+// tfDocsCode renders an expression in a table cell: inline code, or a
+// preformatted block when it spans lines.
+func tfDocsCode(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "|", `\|`)
+	if !strings.Contains(s, "\n") {
+		return "`" + s + "`"
+	}
+	return "<pre>" + strings.ReplaceAll(s, "\n", "<br/>") + "</pre>"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UpdateReadmeTfDocs replaces the section between the TF_DOCS markers and
+// leaves the rest of the README as written. It reports false when the README
+// has no markers.
+func UpdateReadmeTfDocs(readme, docs string) (string, bool) {
+	start := strings.Index(readme, tfDocsBegin)
+	end := strings.Index(readme, tfDocsEnd)
+	if start < 0 || end < start {
+		return readme, false
+	}
+	end += len(tfDocsEnd)
+	if end < len(readme) && readme[end] == '\n' {
+		end++
+	}
+	return readme[:start] + docs + readme[end:], true
+}
@@ -0,0 +1,46 @@
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModule_TerraformDocs(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.ComputedOnlyAttrs = []string{"default_hostname"}
+	files := GenerateModule(info, []string{"default"})
+	readme := files["README.md"]
+
+	assert.Contains(t, readme, `| <a name="requirement_azurerm"></a> [azurerm](#requirement\_azurerm) | >= 3.117, < 5.0 |`)
+	assert.Contains(t, readme, `| <a name="provider_azurerm"></a> [azurerm](#provider\_azurerm) | >= 3.117, < 5.0 |`)
+	assert.Contains(t, readme, `| <a name="module_this"></a> [this](#module\_this) | ../null-label | n/a |`)
+	assert.Contains(t, readme, "| [azurerm_windows_web_app.this](https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/windows_web_app) | resource |")
+	assert.Regexp(t, `\| <a name="input_service_plan_id"></a> \[service\\_plan\\_id\]\(#input\\_service\\_plan\\_id\) \| .+ \| `+"`string`"+` \| n/a \| yes \|`, readme)
+	assert.Regexp(t, `\| <a name="input_https_only"></a> .+ \| `+"`bool`"+` \| `+"`null`"+` \| no \|`, readme)
+	assert.Contains(t, readme, `| <a name="output_default_hostname"></a> [default\_hostname](#output\_default\_hostname) | The default hostname of the Windows Web App |`)
+	assert.Less(t, strings.Index(readme, "## Acknowledgments"), strings.Index(readme, "<!-- BEGIN_TF_DOCS -->\n## Requirements"))
+}
+
+func TestUpdateReadmeTfDocs_KeepsHandWrittenSections(t *testing.T) {
+	readme := "# My module\n\nHand-written notes.\n\n<!-- BEGIN_TF_DOCS -->\nstale\n<!-- END_TF_DOCS -->\n\n## Footer\n"
+	docs, err := TerraformDocs(map[string]string{
+		"variables.tf":    "variable \"sku\" {\n  description = \"The SKU\"\n  type        = string\n}\n",
+		"tests/x/main.tf": "variable \"ignored\" {}\n",
+	})
+	require.NoError(t, err)
+
+	updated, ok := UpdateReadmeTfDocs(readme, docs)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(updated, "# My module\n\nHand-written notes.\n\n<!-- BEGIN_TF_DOCS -->\n## Requirements"))
+	assert.True(t, strings.HasSuffix(updated, "<!-- END_TF_DOCS -->\n\n## Footer\n"))
+	assert.Contains(t, updated, "| The SKU | `string` | n/a | yes |")
+	assert.NotContains(t, updated, "stale")
+	assert.NotContains(t, updated, "ignored", "only root module files are documented")
+
+	_, ok = UpdateReadmeTfDocs("# No markers\n", docs)
+	assert.False(t, ok)
+}
@@ -0,0 +1,281 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// invalidEnumValue is passed to enum-validated variables to prove the
+// validation rejects it.
+const invalidEnumValue = "not-a-valid-value"
+
+// GenerateTfTests returns the terraform native test files of a module. They
+// plan the module from its root against a mock of the wrapped resource's
+// provider, azurerm or azapi, so `terraform test` needs no Azure
+// credentials. User-defined scenarios with expectations get one run each in
+// tests/scenarios.tftest.hcl.
+func GenerateTfTests(info *schema.ResourceInfo, opts Options) (map[string]string, error) {
+	opts = opts.resolved()
+	files := map[string]string{
+		"tests/count.tftest.hcl":  generateCountTfTest(info, opts),
+		"tests/tags.tftest.hcl":   generateTagsTfTest(info, opts),
+		"tests/naming.tftest.hcl": generateNamingTfTest(info, opts),
+	}
+	if v := generateValidationTfTest(info, opts); v != "" {
+		files["tests/validation.tftest.hcl"] = v
+	}
+	v, err := generateScenarioTfTest(info, opts)
+	if err != nil {
+		return nil, err
+	}
+	if v != "" {
+		files["tests/scenarios.tftest.hcl"] = v
+	}
+	return files, nil
+}
+
+// tfTestHeader writes the mock provider and the file-level variables every
+// run starts from: the null-label context and the required inputs.
+func tfTestHeader(b *strings.Builder, info *schema.ResourceInfo, opts Options) {
+	e := newExampleEngine(info, opts)
+	d := opts.Defaults
+	provider, _, _ := strings.Cut(info.ResourceType, "_")
+	b.WriteString(fmt.Sprintf("mock_provider %q {}\n\n", provider))
+	b.WriteString("variables {\n")
+	writeLabelInputs(b, e.pack, "test")
+	b.WriteString("\n")
+
+	for _, attr := range info.Attributes {
+		switch {
+		case attr.Name == "location":
+			b.WriteString(fmt.Sprintf("  %-27s = %s\n", "location", hclQuote(d.Location)))
+		case attr.Name == "resource_group_name":
+			b.WriteString(fmt.Sprintf("  %-27s = %s\n", "resource_group_name", hclQuote(d.ResourceGroupName)))
+		case attr.Required && !isStandardTestVar(attr.Name):
+			b.WriteString(fmt.Sprintf("  %-27s = %s\n", getVariableName(attr.Name, info.ShortName), e.value(attr.Name, attr)))
+		}
+	}
+	for _, block := range info.Blocks {
+		if block.Required {
+			b.WriteString(generateExampleBlock(e, block))
+		}
+	}
+
+	b.WriteString("\n")
+	writeTags(b, e.pack, true)
+	b.WriteString("}\n")
+}
+
+// generateCountTfTest asserts that the resource is planned once when enabled
+// and not at all when either null-label enabled or create_<resource> is false.
+func generateCountTfTest(info *schema.ResourceInfo, opts Options) string {
+	var b strings.Builder
+	tfTestHeader(&b, info, opts)
+	address := info.ResourceType + ".this"
+
+	b.WriteString("\nrun \"enabled_creates_resource\" {\n")
+	b.WriteString("  command = plan\n\n")
+	writeAssert(&b, fmt.Sprintf("length(%s) == 1", address), fmt.Sprintf("%s should be created when the module is enabled", address))
+	b.WriteString("}\n")
+
+	b.WriteString("\nrun \"disabled_creates_nothing\" {\n")
+	b.WriteString("  command = plan\n\n")
+	b.WriteString("  variables {\n")
+	b.WriteString("    enabled = false\n")
+	b.WriteString("  }\n\n")
+	writeAssert(&b, fmt.Sprintf("length(%s) == 0", address), fmt.Sprintf("%s should not be created when enabled = false", address))
+	b.WriteString("}\n")
+
+	b.WriteString(fmt.Sprintf("\nrun \"create_%s_false_creates_nothing\" {\n", info.ShortName))
+	b.WriteString("  command = plan\n\n")
+	b.WriteString("  variables {\n")
+	b.WriteString(fmt.Sprintf("    create_%s = false\n", info.ShortName))
+	b.WriteString("  }\n\n")
+	writeAssert(&b, fmt.Sprintf("length(%s) == 0", address), fmt.Sprintf("%s should not be created when create_%s = false", address, info.ShortName))
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generateTagsTfTest asserts that the pack's tag local, local.dpaas_tags in
+// the built-in pack, is merged over the caller's tags.
+func generateTagsTfTest(info *schema.ResourceInfo, opts Options) string {
+	var b strings.Builder
+	tfTestHeader(&b, info, opts)
+	address := info.ResourceType + ".this[0]"
+	tag := opts.Pack.TagNames()[0] // one of the caller tags tfTestHeader sets
+	tagLocal := "local." + opts.Pack.TagLocal
+
+	b.WriteString(fmt.Sprintf("\nrun \"%s_are_merged\" {\n", opts.Pack.TagLocal))
+	b.WriteString("  command = plan\n\n")
+	writeAssert(&b,
+		fmt.Sprintf("alltrue([for k, v in %s : %s.tags[k] == v])", tagLocal, address),
+		fmt.Sprintf("Every tag in %s should be applied to the resource", tagLocal))
+	b.WriteString("\n")
+	writeAssert(&b,
+		fmt.Sprintf("%s.tags[%s] == %s", address, hclQuote(tag), hclQuote(opts.Pack.Tags[tag])),
+		fmt.Sprintf("Caller tags should be kept alongside %s", tagLocal))
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generateNamingTfTest asserts that the resource name falls back to the
+// null-label id and that an explicit <resource>_name wins.
+func generateNamingTfTest(info *schema.ResourceInfo, opts Options) string {
+	var b strings.Builder
+	tfTestHeader(&b, info, opts)
+	address := info.ResourceType + ".this[0]"
+	nameVar := info.ShortName + "_name"
+	explicit := "example-" + strings.ReplaceAll(info.ShortName, "_", "-")
+
+	b.WriteString("\nrun \"name_falls_back_to_label_id\" {\n")
+	b.WriteString("  command = plan\n\n")
+	writeAssert(&b,
+		fmt.Sprintf("%s.name == module.this.id", address),
+		fmt.Sprintf("The name should default to module.this.id when %s is null", nameVar))
+	b.WriteString("}\n")
+
+	b.WriteString("\nrun \"explicit_name_is_used\" {\n")
+	b.WriteString("  command = plan\n\n")
+	b.WriteString("  variables {\n")
+	b.WriteString(fmt.Sprintf("    %s = \"%s\"\n", nameVar, explicit))
+	b.WriteString("  }\n\n")
+	writeAssert(&b,
+		fmt.Sprintf("%s.name == \"%s\"", address, explicit),
+		fmt.Sprintf("The name should be taken from %s when it is set", nameVar))
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generateValidationTfTest emits one run per enum validation in
+// variables.tf, each expecting the validation to reject an invalid value.
+// It returns "" when the module has no enum validations.
+func generateValidationTfTest(info *schema.ResourceInfo, opts Options) string {
+	var runs strings.Builder
+	e := newExampleEngine(info, opts)
+
+	for _, attr := range info.Attributes {
+		if !hasEnumValidation(attr) || isStandardTestVar(attr.Name) {
+			continue
+		}
+		varName := getVariableName(attr.Name, info.ShortName)
+		runs.WriteString(fmt.Sprintf("\nrun \"invalid_%s\" {\n", varName))
+		runs.WriteString("  command = plan\n\n")
+		runs.WriteString("  variables {\n")
+		runs.WriteString(fmt.Sprintf("    %s = \"%s\"\n", varName, invalidEnumValue))
+		runs.WriteString("  }\n\n")
+		runs.WriteString(fmt.Sprintf("  expect_failures = [var.%s]\n", varName))
+		runs.WriteString("}\n")
+	}
+
+	for _, block := range info.Blocks {
+		for _, attr := range block.Attributes {
+			if !hasEnumValidation(attr) {
+				continue
+			}
+			runs.WriteString(fmt.Sprintf("\nrun \"invalid_%s_%s\" {\n", block.Name, attr.Name))
+			runs.WriteString("  command = plan\n\n")
+			runs.WriteString("  variables {\n")
+			runs.WriteString(invalidBlockValue(e, block, attr))
+			runs.WriteString("  }\n\n")
+			runs.WriteString(fmt.Sprintf("  expect_failures = [var.%s]\n", block.Name))
+			runs.WriteString("}\n")
+		}
+	}
+
+	if runs.Len() == 0 {
+		return ""
+	}
+	var b strings.Builder
+	tfTestHeader(&b, info, opts)
+	b.WriteString(runs.String())
+	return b.String()
+}
+
+// generateScenarioTfTest emits one plan run per user-defined scenario that
+// has expectations, with the scenario's variables set over the file-level
+// ones. It returns "" when no scenario has expectations.
+func generateScenarioTfTest(info *schema.ResourceInfo, opts Options) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	for _, sc := range opts.Custom {
+		if len(sc.Expect) == 0 && len(sc.ExpectFailures) == 0 {
+			continue
+		}
+		f.Body().AppendNewline()
+		run := f.Body().AppendNewBlock("run", []string{sc.Name}).Body()
+		run.SetAttributeRaw("command", hclwrite.TokensForIdentifier("plan"))
+		if len(sc.Variables) > 0 {
+			run.AppendNewline()
+			if err := setAttributes(run.AppendNewBlock("variables", nil).Body(), sc.Variables); err != nil {
+				return "", fmt.Errorf("scenario %s: %w", sc.Name, err)
+			}
+		}
+		for _, e := range sc.Expect {
+			if _, diags := hclsyntax.ParseExpression([]byte(e.Condition), sc.Name, hcl.InitialPos); diags.HasErrors() {
+				return "", fmt.Errorf("scenario %s: condition %q: %s", sc.Name, e.Condition, diags.Error())
+			}
+			msg := e.ErrorMessage
+			if msg == "" {
+				msg = fmt.Sprintf("Expectation of scenario %s failed: %s", sc.Name, e.Condition)
+			}
+			run.AppendNewline()
+			assert := run.AppendNewBlock("assert", nil).Body()
+			assert.SetAttributeRaw("condition", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(e.Condition)}})
+			assert.SetAttributeValue("error_message", cty.StringVal(msg))
+		}
+		if len(sc.ExpectFailures) > 0 {
+			var refs []string
+			for _, ref := range sc.ExpectFailures {
+				if _, diags := hclsyntax.ParseTraversalAbs([]byte(ref), sc.Name, hcl.InitialPos); diags.HasErrors() {
+					return "", fmt.Errorf("scenario %s: expect_failures entry %q is not a reference", sc.Name, ref)
+				}
+				refs = append(refs, ref)
+			}
+			run.AppendNewline()
+			run.SetAttributeRaw("expect_failures", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte("[" + strings.Join(refs, ", ") + "]")}})
+		}
+	}
+	if len(f.Body().Blocks()) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	tfTestHeader(&b, info, opts)
+	b.Write(hclwrite.Format(f.Bytes()))
+	return b.String(), nil
+}
+
+// hasEnumValidation mirrors the condition under which variables.tf emits an
+// enum validation block.
+func hasEnumValidation(attr schema.ParsedAttribute) bool {
+	return attr.TFType == "string" && len(attr.EnumValues) > 0 && len(attr.EnumValues) < 20
+}
+
+// invalidBlockValue renders a complete example value for a block variable
+// with attr set to invalidEnumValue, indented for a run's variables block.
+func invalidBlockValue(e *exampleEngine, block schema.ParsedBlock, attr schema.ParsedAttribute) string {
+	invalid := e.withValue(block.Name+"."+attr.Name, hclQuote(invalidEnumValue))
+
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(generateCompleteExampleBlock(invalid, block), "\n") {
+		if line != "" {
+			b.WriteString("  " + line)
+		}
+	}
+	return b.String()
+}
+
+func writeAssert(b *strings.Builder, condition, message string) {
+	b.WriteString("  assert {\n")
+	b.WriteString(fmt.Sprintf("    condition     = %s\n", condition))
+	b.WriteString(fmt.Sprintf("    error_message = \"%s\"\n", message))
+	b.WriteString("  }\n")
+}
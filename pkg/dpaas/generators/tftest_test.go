@@ -0,0 +1,70 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestGenerateModule_TfTests(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.Attributes = append(info.Attributes, schema.ParsedAttribute{
+		Name: "ftps_state", TFType: "string", Optional: true, EnumValues: []string{"AllAllowed", "Disabled"},
+	})
+	files := GenerateModule(info, []string{"default"})
+
+	for _, name := range []string{"count", "tags", "naming", "validation"} {
+		path := "tests/" + name + ".tftest.hcl"
+		src, ok := files[path]
+		require.True(t, ok, path)
+		_, diags := hclsyntax.ParseConfig([]byte(src), path, hcl.InitialPos)
+		require.False(t, diags.HasErrors(), diags.Error())
+		assert.Contains(t, src, `mock_provider "azurerm" {}`)
+	}
+	assert.Contains(t, files["tests/count.tftest.hcl"], "create_windows_web_app = false")
+	assert.Contains(t, files["tests/tags.tftest.hcl"], "local.dpaas_tags")
+	assert.Contains(t, files["tests/naming.tftest.hcl"], "azurerm_windows_web_app.this[0].name == module.this.id")
+	assert.Contains(t, files["tests/validation.tftest.hcl"], "expect_failures = [var.ftps_state]")
+}
+
+func TestGenerateTfTests_InvalidBlockValues(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.Blocks[0].Attributes[1].EnumValues = []string{"MySQL", "SQLAzure", "SQLServer"}
+	// the web app's SKU profile pins site_config.minimum_tls_version
+	info.Blocks[1].Attributes = append(info.Blocks[1].Attributes,
+		schema.ParsedAttribute{Name: "minimum_tls_version", TFType: "string", Optional: true, EnumValues: []string{"1.0", "1.1", "1.2"}})
+	src := GenerateModule(info, nil)["tests/validation.tftest.hcl"]
+	f, diags := hclsyntax.ParseConfig([]byte(src), "validation.tftest.hcl", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	runs := map[string]*hclsyntax.Body{}
+	for _, blk := range f.Body.(*hclsyntax.Body).Blocks {
+		if blk.Type == "run" {
+			runs[blk.Labels[0]] = blk.Body
+		}
+	}
+	variable := func(run, name string) cty.Value {
+		t.Helper()
+		require.Contains(t, runs, run)
+		require.Len(t, runs[run].Blocks, 1)
+		attr, ok := runs[run].Blocks[0].Body.Attributes[name]
+		require.True(t, ok, "%s sets %s", run, name)
+		v, diags := attr.Expr.Value(nil)
+		require.False(t, diags.HasErrors(), diags.Error())
+		return v
+	}
+
+	tls := variable("invalid_site_config_minimum_tls_version", "site_config")
+	assert.Equal(t, invalidEnumValue, tls.GetAttr("minimum_tls_version").AsString())
+	assert.True(t, tls.GetAttr("always_on").True(), "the other attributes keep their example values")
+
+	conn := variable("invalid_connection_string_type", "connection_string").GetAttr("connection_string-1")
+	assert.Equal(t, invalidEnumValue, conn.GetAttr("type").AsString())
+	assert.NotEqual(t, invalidEnumValue, conn.GetAttr("name").AsString())
+}
@@ -0,0 +1,189 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/templates"
+)
+
+// usageExample is one directory under examples/, which the private registry
+// renders on the module page.
+type usageExample struct {
+	name        string
+	title       string
+	description string
+	// call renders the module call; the environment-specific arguments are
+	// replaced with variables afterwards.
+	call func(info *schema.ResourceInfo, e *exampleEngine, label string) string
+}
+
+var usageExamples = []usageExample{
+	{
+		name:        "basic",
+		title:       "Basic",
+		description: "the required arguments only, with everything else left at the module defaults",
+		call:        generateDefaultTest,
+	},
+	{
+		name:        "complete",
+		title:       "Complete",
+		description: "every argument and block the module exposes, set to example values",
+		call:        generateCompleteTest,
+	},
+}
+
+// exampleVariables are the module arguments an example takes from
+// terraform.tfvars instead of hardcoding, with their descriptions.
+var exampleVariables = []struct{ name, description string }{
+	{"location", "The Azure region to deploy into"},
+	{"resource_group_name", "The existing resource group to deploy into"},
+}
+
+// GenerateExamples returns examples/basic and examples/complete, each a root
+// module with a README walkthrough, variables with a terraform.tfvars.example
+// and outputs forwarding the module's outputs.
+func GenerateExamples(info *schema.ResourceInfo, opts Options) (map[string]string, error) {
+	opts = opts.resolved()
+	files := map[string]string{}
+	for _, ex := range usageExamples {
+		dir := "examples/" + ex.name + "/"
+		main, err := exampleMainTf(info, ex.call(info, newExampleEngine(info, opts), ex.name))
+		if err != nil {
+			return nil, fmt.Errorf("example %s: %w", ex.name, err)
+		}
+		files[dir+"main.tf"] = main
+		files[dir+"variables.tf"] = exampleVariablesTf(info)
+		files[dir+"outputs.tf"] = exampleOutputsTf(info)
+		files[dir+"versions.tf"] = templates.VersionsTestTf + "\nprovider \"azurerm\" {\n  subscription_id = var.subscription_id\n  features {}\n}\n"
+		if info.ResourceType == schema.AzAPIResourceType {
+			files[dir+"versions.tf"] += "\nprovider \"azapi\" {\n  subscription_id = var.subscription_id\n}\n"
+		}
+		files[dir+"terraform.tfvars.example"] = exampleTfvars(info, opts.Defaults)
+		files[dir+"README.md"] = exampleReadme(info, ex)
+	}
+	return files, nil
+}
+
+// exampleMainTf makes a scenario's module call take the environment-specific
+// arguments from variables. The "../.." source works for examples as is.
+func exampleMainTf(info *schema.ResourceInfo, call string) (string, error) {
+	f, diags := hclwrite.ParseConfig([]byte(call), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	for _, blk := range f.Body().Blocks() {
+		if blk.Type() != "module" {
+			continue
+		}
+		for _, v := range exampleVariables {
+			if blk.Body().GetAttribute(v.name) != nil {
+				blk.Body().SetAttributeTraversal(v.name, hcl.Traversal{
+					hcl.TraverseRoot{Name: "var"},
+					hcl.TraverseAttr{Name: v.name},
+				})
+			}
+		}
+	}
+	return string(hclwrite.Format(f.Bytes())), nil
+}
+
+func hasAttribute(info *schema.ResourceInfo, name string) bool {
+	for _, attr := range info.Attributes {
+		if attr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func exampleVariablesTf(info *schema.ResourceInfo) string {
+	var b strings.Builder
+	b.WriteString("variable \"subscription_id\" {\n")
+	b.WriteString("  description = \"The Azure subscription to deploy into\"\n")
+	b.WriteString("  type        = string\n")
+	b.WriteString("}\n")
+	for _, v := range exampleVariables {
+		if !hasAttribute(info, v.name) {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\nvariable \"%s\" {\n", v.name))
+		b.WriteString(fmt.Sprintf("  description = \"%s\"\n", v.description))
+		b.WriteString("  type        = string\n")
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func exampleOutputsTf(info *schema.ResourceInfo) string {
+	var b strings.Builder
+	b.WriteString("output \"id\" {\n")
+	b.WriteString(fmt.Sprintf("  description = \"The %s created by the module\"\n", info.DisplayName))
+	b.WriteString(fmt.Sprintf("  value       = module.%s.id\n", info.ShortName))
+	b.WriteString("}\n")
+	for _, name := range info.ComputedOnlyAttrs {
+		b.WriteString(fmt.Sprintf("\noutput \"%s\" {\n", name))
+		b.WriteString(fmt.Sprintf("  description = \"The %s of the %s\"\n", strings.ReplaceAll(name, "_", " "), info.DisplayName))
+		b.WriteString(fmt.Sprintf("  value       = module.%s.%s\n", info.ShortName, name))
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func exampleTfvars(info *schema.ResourceInfo, d ScenarioDefaults) string {
+	var b strings.Builder
+	b.WriteString("# Copy to terraform.tfvars and set the values for your environment.\n\n")
+	b.WriteString(fmt.Sprintf("%-19s = %s\n", "subscription_id", hclQuote(d.SubscriptionID)))
+	if hasAttribute(info, "location") {
+		b.WriteString(fmt.Sprintf("%-19s = %s\n", "location", hclQuote(d.Location)))
+	}
+	if hasAttribute(info, "resource_group_name") {
+		b.WriteString(fmt.Sprintf("%-19s = %s\n", "resource_group_name", hclQuote(d.ResourceGroupName)))
+	}
+	return b.String()
+}
+
+func exampleReadme(info *schema.ResourceInfo, ex usageExample) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s %s example\n\n", ex.title, info.DisplayName))
+	b.WriteString(fmt.Sprintf("This example deploys an Azure %s with %s.\n\n", info.DisplayName, ex.description))
+
+	b.WriteString("## Usage\n\n")
+	b.WriteString("1. Copy `terraform.tfvars.example` to `terraform.tfvars` and set the subscription")
+	if hasAttribute(info, "resource_group_name") {
+		b.WriteString(", location and an existing resource group")
+	}
+	b.WriteString(".\n")
+	b.WriteString("2. Sign in with `az login`, or export the `ARM_*` environment variables for a service principal.\n")
+	b.WriteString("3. Review and deploy:\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("terraform init\n")
+	b.WriteString("terraform plan -out tfplan\n")
+	b.WriteString("terraform apply tfplan\n")
+	b.WriteString("```\n\n")
+	b.WriteString("4. Remove everything the example created with `terraform destroy`.\n\n")
+	b.WriteString("The name comes from the null-label inputs (`namespace`, `tenant`, `environment`, `name`) unless ")
+	b.WriteString(fmt.Sprintf("`%s_name` is set, and the DPaaS tags are merged over the `tags` given here.\n\n", info.ShortName))
+
+	b.WriteString("## Inputs\n\n")
+	b.WriteString("| Name | Description |\n")
+	b.WriteString("|------|-------------|\n")
+	b.WriteString("| subscription_id | The Azure subscription to deploy into |\n")
+	for _, v := range exampleVariables {
+		if hasAttribute(info, v.name) {
+			b.WriteString(fmt.Sprintf("| %s | %s |\n", v.name, v.description))
+		}
+	}
+
+	b.WriteString("\n## Outputs\n\n")
+	b.WriteString("| Name | Description |\n")
+	b.WriteString("|------|-------------|\n")
+	b.WriteString(fmt.Sprintf("| id | The %s created by the module |\n", info.DisplayName))
+	for _, name := range info.ComputedOnlyAttrs {
+		b.WriteString(fmt.Sprintf("| %s | The %s of the %s |\n", name, strings.ReplaceAll(name, "_", " "), info.DisplayName))
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateModule_Examples(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.ComputedOnlyAttrs = []string{"default_hostname"}
+	files := GenerateModule(info, []string{"default"})
+
+	for _, name := range []string{"basic", "complete"} {
+		dir := "examples/" + name + "/"
+		for _, f := range []string{"main.tf", "variables.tf", "outputs.tf", "versions.tf"} {
+			_, diags := hclsyntax.ParseConfig([]byte(files[dir+f]), dir+f, hcl.InitialPos)
+			require.False(t, diags.HasErrors(), dir+f+": "+diags.Error())
+		}
+		assert.Regexp(t, `location\s+= var.location`, files[dir+"main.tf"])
+		assert.Regexp(t, `resource_group_name\s+= var.resource_group_name`, files[dir+"main.tf"])
+		assert.Contains(t, files[dir+"outputs.tf"], "value       = module.windows_web_app.default_hostname")
+		assert.Contains(t, files[dir+"terraform.tfvars.example"], `location            = "East US 2"`)
+		assert.Contains(t, files[dir+"README.md"], "terraform apply tfplan")
+		assert.Contains(t, files["README.md"], "(examples/"+name+")")
+	}
+	assert.NotContains(t, files["examples/basic/main.tf"], "https_only", "optional arguments are left out")
+	assert.Contains(t, files["examples/complete/main.tf"], "https_only")
+}
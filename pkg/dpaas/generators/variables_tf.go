@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/templates"
 )
@@ -31,58 +32,54 @@ func getVariableName(attrName string, shortName string) string {
 	return attrName
 }
 
-func GenerateVariablesTf(info *schema.ResourceInfo) string {
+// GenerateVariablesTf renders variables.tf: the pack's null-label variables,
+// the create flag and one variable per resource argument and block.
+func GenerateVariablesTf(info *schema.ResourceInfo, pack *TemplatePack) (string, error) {
 	var b strings.Builder
 
+	labelVarsA, err := pack.render(packLabelVarsATemplate, info, templates.NullLabelVarsPartA)
+	if err != nil {
+		return "", err
+	}
+	labelVarsB, err := pack.render(packLabelVarsBTemplate, info, templates.NullLabelVarsPartB)
+	if err != nil {
+		return "", err
+	}
+
 	// Part A: null-label vars up to namespace
-	b.WriteString(templates.NullLabelVarsPartA)
+	b.WriteString(labelVarsA)
 	b.WriteString("\n")
 
 	// create_{resource} variable (injected between parts A and B)
-	b.WriteString(fmt.Sprintf("variable \"create_%s\" {\n", info.ShortName))
-	b.WriteString("  type        = bool\n")
-	b.WriteString(fmt.Sprintf("  description = \"Whether to create the %s.\"\n", info.DisplayName))
-	b.WriteString("  default     = true\n")
-	b.WriteString("}\n")
+	b.WriteString(GenerateCreateFlagVariable(info))
 
 	// Part B: rest of null-label vars (tenant through tags + end marker)
-	b.WriteString(templates.NullLabelVarsPartB)
+	b.WriteString(labelVarsB)
 	b.WriteString("\n")
 
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
 	// Resource name variable
-	nameVar := info.ShortName + "_name"
-	b.WriteString(fmt.Sprintf("variable \"%s\" {\n", nameVar))
-	b.WriteString(fmt.Sprintf("  description = \"Specifies the name of the %s\"\n", info.DisplayName))
-	b.WriteString("  type        = string\n")
-	b.WriteString("  default     = null\n")
-	b.WriteString("}\n\n")
-
-	// Check if resource_group_name and location exist in schema
-	hasResourceGroupName := false
-	hasLocation := false
-	for _, attr := range info.Attributes {
-		if attr.Name == "resource_group_name" {
-			hasResourceGroupName = true
-		}
-		if attr.Name == "location" {
-			hasLocation = true
-		}
-	}
+	nameVar := body.AppendNewBlock("variable", []string{info.ShortName + "_name"}).Body()
+	nameVar.SetAttributeRaw("description", stringTokens(fmt.Sprintf("Specifies the name of the %s", info.DisplayName)))
+	nameVar.SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
+	nameVar.SetAttributeRaw("default", hclwrite.TokensForIdentifier("null"))
 
 	// Only include resource_group_name if it exists in schema
-	if hasResourceGroupName {
-		b.WriteString("variable \"resource_group_name\" {\n")
-		b.WriteString(fmt.Sprintf("  description = \"The name of the resource group in which to create the %s\"\n", info.DisplayName))
-		b.WriteString("  type        = string\n")
-		b.WriteString("}\n\n")
+	if hasAttribute(info, "resource_group_name") {
+		body.AppendNewline()
+		v := body.AppendNewBlock("variable", []string{"resource_group_name"}).Body()
+		v.SetAttributeRaw("description", stringTokens(fmt.Sprintf("The name of the resource group in which to create the %s", info.DisplayName)))
+		v.SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
 	}
 
 	// Only include location if it exists in schema
-	if hasLocation {
-		b.WriteString("variable \"location\" {\n")
-		b.WriteString("  description = \"Specifies the supported Azure location where the resource exists\"\n")
-		b.WriteString("  type        = string\n")
-		b.WriteString("}\n\n")
+	if hasAttribute(info, "location") {
+		body.AppendNewline()
+		v := body.AppendNewBlock("variable", []string{"location"}).Body()
+		v.SetAttributeRaw("description", stringTokens("Specifies the supported Azure location where the resource exists"))
+		v.SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
 	}
 
 	// Other required attributes (excluding standard ones)
@@ -90,7 +87,10 @@ func GenerateVariablesTf(info *schema.ResourceInfo) string {
 		if isStandardVar(attr.Name) || !attr.Required {
 			continue
 		}
-		writeVariable(&b, attr, info)
+		body.AppendNewline()
+		if err := writeVariable(body, attr, info); err != nil {
+			return "", err
+		}
 	}
 
 	// Optional attributes
@@ -98,76 +98,126 @@ func GenerateVariablesTf(info *schema.ResourceInfo) string {
 		if isStandardVar(attr.Name) || attr.Required {
 			continue
 		}
-		writeVariable(&b, attr, info)
+		body.AppendNewline()
+		if err := writeVariable(body, attr, info); err != nil {
+			return "", err
+		}
 	}
 
 	// Block variables
 	for _, block := range info.Blocks {
-		writeBlockVariable(&b, block)
+		body.AppendNewline()
+		if err := writeBlockVariable(body, block); err != nil {
+			return "", err
+		}
 	}
 
-	return b.String()
+	b.Write(hclwrite.Format(f.Bytes()))
+	return b.String(), nil
 }
 
-func writeVariable(b *strings.Builder, attr schema.ParsedAttribute, info *schema.ResourceInfo) {
+// GenerateCreateFlagVariable renders the create_<resource> flag variable.
+func GenerateCreateFlagVariable(info *schema.ResourceInfo) string {
+	f := hclwrite.NewEmptyFile()
+	v := f.Body().AppendNewBlock("variable", []string{"create_" + info.ShortName}).Body()
+	v.SetAttributeRaw("type", hclwrite.TokensForIdentifier("bool"))
+	v.SetAttributeRaw("description", stringTokens(fmt.Sprintf("Whether to create the %s.", info.DisplayName)))
+	v.SetAttributeRaw("default", hclwrite.TokensForIdentifier("true"))
+	return string(hclwrite.Format(f.Bytes()))
+}
+
+// VariableName returns the input variable name generated for a resource
+// attribute, accounting for null-label name clashes.
+func VariableName(attrName string, shortName string) string {
+	return getVariableName(attrName, shortName)
+}
+
+// IsSingleBlock reports whether a block's variable is one object rather
+// than a map of objects.
+func IsSingleBlock(block schema.ParsedBlock) bool {
+	return isSingleBlock(block)
+}
+
+// GenerateAttributeVariable renders the variable block for one resource attribute.
+func GenerateAttributeVariable(attr schema.ParsedAttribute, info *schema.ResourceInfo) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	if err := writeVariable(f.Body(), attr, info); err != nil {
+		return "", err
+	}
+	return string(hclwrite.Format(f.Bytes())), nil
+}
+
+// GenerateBlockVariable renders the variable block for one top-level nested block.
+func GenerateBlockVariable(block schema.ParsedBlock) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	if err := writeBlockVariable(f.Body(), block); err != nil {
+		return "", err
+	}
+	return string(hclwrite.Format(f.Bytes())), nil
+}
+
+func writeVariable(body *hclwrite.Body, attr schema.ParsedAttribute, info *schema.ResourceInfo) error {
 	varName := getVariableName(attr.Name, info.ShortName)
-	b.WriteString(fmt.Sprintf("variable \"%s\" {\n", varName))
+	v := body.AppendNewBlock("variable", []string{varName}).Body()
+	var s exprSetter
 
 	desc := attr.Description
 	if desc == "" {
 		desc = fmt.Sprintf("The %s attribute", strings.ReplaceAll(attr.Name, "_", " "))
 	}
-	desc = escapeDescription(desc)
-	b.WriteString(fmt.Sprintf("  description = \"%s\"\n", desc))
-	b.WriteString(fmt.Sprintf("  type        = %s\n", attr.TFType))
+	v.SetAttributeRaw("description", stringTokens(shortDescription(desc)))
+	s.set(v, "type", attr.TFType)
 
 	if !attr.Required {
-		b.WriteString("  default     = null\n")
+		v.SetAttributeRaw("default", hclwrite.TokensForIdentifier("null"))
 	}
 
 	// Validation block for enum-valued string attributes
 	if attr.TFType == "string" && len(attr.EnumValues) > 0 && len(attr.EnumValues) < 20 {
-		b.WriteString("  validation {\n")
-		b.WriteString(fmt.Sprintf("    condition = var.%s == null || contains(%s, var.%s)\n", varName, formatEnumList(attr.EnumValues), varName))
-		b.WriteString(fmt.Sprintf("    error_message = \"%s must be one of: %s.\"\n", varName, strings.Join(attr.EnumValues, ", ")))
-		b.WriteString("  }\n")
+		val := v.AppendNewBlock("validation", nil).Body()
+		s.set(val, "condition", fmt.Sprintf("var.%s == null || contains(%s, var.%s)", varName, formatEnumList(attr.EnumValues), varName))
+		val.SetAttributeRaw("error_message", stringTokens(fmt.Sprintf("%s must be one of: %s.", varName, strings.Join(attr.EnumValues, ", "))))
 	}
 
 	if attr.Sensitive {
-		b.WriteString("  sensitive   = true\n")
+		v.SetAttributeRaw("sensitive", hclwrite.TokensForIdentifier("true"))
 	}
-
-	b.WriteString("}\n\n")
+	if s.err != nil {
+		return fmt.Errorf("variable %s: %w", varName, s.err)
+	}
+	return nil
 }
 
-func writeBlockVariable(b *strings.Builder, block schema.ParsedBlock) {
-	b.WriteString(fmt.Sprintf("variable \"%s\" {\n", block.Name))
+func writeBlockVariable(body *hclwrite.Body, block schema.ParsedBlock) error {
+	v := body.AppendNewBlock("variable", []string{block.Name}).Body()
+	var s exprSetter
 
-	typeExpr := blockToTypeExpr(block, "  ")
-	b.WriteString(fmt.Sprintf("  type        = %s\n", typeExpr))
+	s.set(v, "type", blockToTypeExpr(block, ""))
 
 	if !block.Required {
 		if isSingleBlock(block) {
-			b.WriteString("  default     = null\n")
+			v.SetAttributeRaw("default", hclwrite.TokensForIdentifier("null"))
 		} else {
-			b.WriteString("  default     = {}\n")
+			v.SetAttributeRaw("default", hclwrite.TokensForObject(nil))
 		}
 	}
 
 	// Heredoc description listing all attributes and nested blocks
-	b.WriteString("  description = <<-DESCRIPTION\n")
-	writeBlockDescriptionLines(b, block, "  ")
-	b.WriteString("  DESCRIPTION\n")
+	var lines []string
+	writeBlockDescriptionLines(&lines, block, "")
+	v.SetAttributeRaw("description", heredocTokens("DESCRIPTION", "  ", lines))
 
 	// Validation blocks for enum-valued string attributes
-	writeBlockValidations(b, block)
-
-	b.WriteString("}\n\n")
+	writeBlockValidations(v, block, &s)
+	if s.err != nil {
+		return fmt.Errorf("variable %s: %w", block.Name, s.err)
+	}
+	return nil
 }
 
 // writeBlockDescriptionLines writes a structured description for a block variable,
 // recursively documenting nested blocks with --- separators and indentation.
-func writeBlockDescriptionLines(b *strings.Builder, block schema.ParsedBlock, indent string) {
+func writeBlockDescriptionLines(lines *[]string, block schema.ParsedBlock, indent string) {
 	// Sorted attributes for stable output
 	sortedAttrs := make([]schema.ParsedAttribute, len(block.Attributes))
 	copy(sortedAttrs, block.Attributes)
@@ -176,7 +226,7 @@ func writeBlockDescriptionLines(b *strings.Builder, block schema.ParsedBlock, in
 	})
 
 	for _, attr := range sortedAttrs {
-		b.WriteString(fmt.Sprintf("%s- `%s` - %s\n", indent, attr.Name, attrDescription(attr)))
+		*lines = append(*lines, fmt.Sprintf("%s- `%s` - %s", indent, attr.Name, attrDescription(attr)))
 	}
 
 	// Nested blocks with --- separator
@@ -187,10 +237,8 @@ func writeBlockDescriptionLines(b *strings.Builder, block schema.ParsedBlock, in
 	})
 
 	for _, nested := range sortedBlocks {
-		b.WriteString("\n")
-		b.WriteString(indent + "---\n")
-		b.WriteString(fmt.Sprintf("%s`%s` block supports the following:\n", indent, nested.Name))
-		writeBlockDescriptionLines(b, nested, indent+"  ")
+		*lines = append(*lines, "", indent+"---", fmt.Sprintf("%s`%s` block supports the following:", indent, nested.Name))
+		writeBlockDescriptionLines(lines, nested, indent+"  ")
 	}
 }
 
@@ -209,7 +257,7 @@ func attrDescription(attr schema.ParsedAttribute) string {
 // writeBlockValidations emits validation blocks for string attributes that have
 // known enum values. Single blocks use direct property access; map blocks use
 // an alltrue([for ...]) comprehension.
-func writeBlockValidations(b *strings.Builder, block schema.ParsedBlock) {
+func writeBlockValidations(body *hclwrite.Body, block schema.ParsedBlock, s *exprSetter) {
 	isSingle := isSingleBlock(block)
 
 	for _, attr := range block.Attributes {
@@ -220,14 +268,14 @@ func writeBlockValidations(b *strings.Builder, block schema.ParsedBlock) {
 		enumList := formatEnumList(attr.EnumValues)
 		qualifiedName := block.Name + "." + attr.Name
 
-		b.WriteString("  validation {\n")
+		var condition string
 		if isSingle {
 			if block.Required && attr.Required {
-				b.WriteString(fmt.Sprintf("    condition     = contains(%s, var.%s.%s)\n", enumList, block.Name, attr.Name))
+				condition = fmt.Sprintf("contains(%s, var.%s.%s)", enumList, block.Name, attr.Name)
 			} else if block.Required && !attr.Required {
-				b.WriteString(fmt.Sprintf("    condition     = var.%s.%s == null || contains(%s, var.%s.%s)\n", block.Name, attr.Name, enumList, block.Name, attr.Name))
+				condition = fmt.Sprintf("var.%s.%s == null || contains(%s, var.%s.%s)", block.Name, attr.Name, enumList, block.Name, attr.Name)
 			} else {
-				b.WriteString(fmt.Sprintf("    condition     = var.%s == null || contains(%s, var.%s.%s)\n", block.Name, enumList, block.Name, attr.Name))
+				condition = fmt.Sprintf("var.%s == null || contains(%s, var.%s.%s)", block.Name, enumList, block.Name, attr.Name)
 			}
 		} else {
 			// map(object) — alltrue over the map; handles empty map (alltrue([]) == true)
@@ -235,10 +283,11 @@ func writeBlockValidations(b *strings.Builder, block schema.ParsedBlock) {
 			if !attr.Required {
 				check = fmt.Sprintf("v.%s == null || %s", attr.Name, check)
 			}
-			b.WriteString(fmt.Sprintf("    condition     = alltrue([for k, v in var.%s : %s])\n", block.Name, check))
+			condition = fmt.Sprintf("alltrue([for k, v in var.%s : %s])", block.Name, check)
 		}
-		b.WriteString(fmt.Sprintf("    error_message = \"%s must be one of: %s.\"\n", qualifiedName, strings.Join(attr.EnumValues, ", ")))
-		b.WriteString("  }\n")
+		val := body.AppendNewBlock("validation", nil).Body()
+		s.set(val, "condition", condition)
+		val.SetAttributeRaw("error_message", stringTokens(fmt.Sprintf("%s must be one of: %s.", qualifiedName, strings.Join(attr.EnumValues, ", "))))
 	}
 }
 
@@ -304,8 +353,9 @@ func isStandardVar(name string) bool {
 	return name == "name" || name == "location" || name == "resource_group_name" || name == "tags" || name == "id"
 }
 
-func escapeDescription(s string) string {
-	s = strings.ReplaceAll(s, "\"", "\\\"")
+// shortDescription fits a docs description on one line of at most 200
+// characters. Quoting is left to the HCL writer.
+func shortDescription(s string) string {
 	s = strings.ReplaceAll(s, "\n", " ")
 	s = strings.ReplaceAll(s, "\r", "")
 	if len(s) > 200 {
@@ -317,7 +367,7 @@ func escapeDescription(s string) string {
 func formatEnumList(vals []string) string {
 	quoted := make([]string, len(vals))
 	for i, v := range vals {
-		quoted[i] = fmt.Sprintf("\"%s\"", v)
+		quoted[i] = hclQuote(v)
 	}
 	return "[" + strings.Join(quoted, ", ") + "]"
 }
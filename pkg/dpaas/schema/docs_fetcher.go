@@ -1,7 +1,6 @@
 package schema
 
 import (
-	"fmt"
 	"io"
 	"net/http"
 	"regexp"
@@ -230,33 +229,8 @@ func isGenericHeading(s string) bool {
 
 // GenerateAzureResourceID generates a properly formatted Azure resource ID
 // based on the attribute name suffix. Returns a placeholder ID that matches
-// the Azure ID segment structure.
+// the Azure ID segment structure; see AzureResourceID.
 func GenerateAzureResourceID(attrName string) string {
-	sub := "00000000-0000-0000-0000-000000000000"
-	rg := "example-resource-group"
-
-	// Known Azure resource ID patterns derived from attribute name
-	// These are the standard Azure resource ID formats per resource type
-	patterns := map[string]string{
-		"subnet_id":                      fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/example-vnet/subnets/example-subnet", sub, rg),
-		"virtual_network_id":             fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/example-vnet", sub, rg),
-		"public_ip_address_id":           fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/publicIPAddresses/example-pip", sub, rg),
-		"network_security_group_id":      fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/example-nsg", sub, rg),
-		"key_vault_id":                   fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/example-kv", sub, rg),
-		"storage_account_id":             fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/examplestorage", sub, rg),
-		"log_analytics_workspace_id":     fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.OperationalInsights/workspaces/example-workspace", sub, rg),
-		"service_plan_id":                fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Web/serverFarms/example-service-plan", sub, rg),
-		"app_service_plan_id":            fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Web/serverFarms/example-app-service-plan", sub, rg),
-		"container_app_environment_id":   fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/managedEnvironments/example-environment", sub, rg),
-		"private_link_configuration_id":  fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGateways/example-appgw/privateLinkConfigurations/example-plc", sub, rg),
-		"firewall_policy_id":             fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGatewayWebApplicationFirewallPolicies/example-waf", sub, rg),
-	}
-
-	if id, ok := patterns[attrName]; ok {
-		return id
-	}
-
-	// Generic fallback: derive resource type from attribute name
-	resourceType := strings.TrimSuffix(attrName, "_id")
-	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Resources/resources/example-%s", sub, rg, resourceType)
+	id, _ := AzureResourceID("", attrName, "", "")
+	return id
 }
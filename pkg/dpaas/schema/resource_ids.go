@@ -0,0 +1,235 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExampleSubscriptionID and ExampleResourceGroup are the placeholders used
+// when no scenario defaults are given.
+const (
+	ExampleSubscriptionID = "00000000-0000-0000-0000-000000000000"
+	ExampleResourceGroup  = "example-resource-group"
+)
+
+// armIDFormats maps the name an attribute gives a referenced resource (the
+// attribute name without "_id") to the ARM ID path of that resource type
+// below the resource group. Child resources include their parent.
+var armIDFormats = map[string]string{
+	// Network
+	"virtual_network":                 "Microsoft.Network/virtualNetworks/example-vnet",
+	"subnet":                          "Microsoft.Network/virtualNetworks/example-vnet/subnets/example-subnet",
+	"network_security_group":          "Microsoft.Network/networkSecurityGroups/example-nsg",
+	"application_security_group":      "Microsoft.Network/applicationSecurityGroups/example-asg",
+	"route_table":                     "Microsoft.Network/routeTables/example-rt",
+	"public_ip_address":               "Microsoft.Network/publicIPAddresses/example-pip",
+	"public_ip_prefix":                "Microsoft.Network/publicIPPrefixes/example-pip-prefix",
+	"nat_gateway":                     "Microsoft.Network/natGateways/example-natgw",
+	"network_interface":               "Microsoft.Network/networkInterfaces/example-nic",
+	"private_dns_zone":                "Microsoft.Network/privateDnsZones/privatelink.example.com",
+	"dns_zone":                        "Microsoft.Network/dnsZones/example.com",
+	"private_endpoint":                "Microsoft.Network/privateEndpoints/example-pe",
+	"private_link_service":            "Microsoft.Network/privateLinkServices/example-pls",
+	"load_balancer":                   "Microsoft.Network/loadBalancers/example-lb",
+	"backend_address_pool":            "Microsoft.Network/loadBalancers/example-lb/backendAddressPools/example-pool",
+	"frontend_ip_configuration":       "Microsoft.Network/loadBalancers/example-lb/frontendIPConfigurations/example-fip",
+	"probe":                           "Microsoft.Network/loadBalancers/example-lb/probes/example-probe",
+	"application_gateway":             "Microsoft.Network/applicationGateways/example-appgw",
+	"private_link_configuration":      "Microsoft.Network/applicationGateways/example-appgw/privateLinkConfigurations/example-plc",
+	"web_application_firewall_policy": "Microsoft.Network/applicationGatewayWebApplicationFirewallPolicies/example-waf",
+	"firewall_policy":                 "Microsoft.Network/firewallPolicies/example-fwpolicy",
+	"firewall":                        "Microsoft.Network/azureFirewalls/example-fw",
+	"virtual_hub":                     "Microsoft.Network/virtualHubs/example-hub",
+	"virtual_wan":                     "Microsoft.Network/virtualWans/example-wan",
+	"express_route_circuit":           "Microsoft.Network/expressRouteCircuits/example-erc",
+	"virtual_network_gateway":         "Microsoft.Network/virtualNetworkGateways/example-vng",
+	"local_network_gateway":           "Microsoft.Network/localNetworkGateways/example-lng",
+	"ddos_protection_plan":            "Microsoft.Network/ddosProtectionPlans/example-ddos",
+	"network_watcher":                 "Microsoft.Network/networkWatchers/example-nw",
+	"ip_group":                        "Microsoft.Network/ipGroups/example-ipg",
+	"bastion_host":                    "Microsoft.Network/bastionHosts/example-bastion",
+	"network_manager":                 "Microsoft.Network/networkManagers/example-nm",
+	"frontdoor_firewall_policy":       "Microsoft.Network/frontDoorWebApplicationFirewallPolicies/examplewaf",
+	"cdn_frontdoor_profile":           "Microsoft.Cdn/profiles/example-afd",
+	"cdn_frontdoor_endpoint":          "Microsoft.Cdn/profiles/example-afd/afdEndpoints/example-endpoint",
+	"cdn_frontdoor_origin_group":      "Microsoft.Cdn/profiles/example-afd/originGroups/example-og",
+	"cdn_frontdoor_firewall_policy":   "Microsoft.Network/frontDoorWebApplicationFirewallPolicies/examplewaf",
+	"traffic_manager_profile":         "Microsoft.Network/trafficManagerProfiles/example-tm",
+	"network_security_perimeter":      "Microsoft.Network/networkSecurityPerimeters/example-nsp",
+	"service_endpoint_policy":         "Microsoft.Network/serviceEndpointPolicies/example-sep",
+	"virtual_network_peering":         "Microsoft.Network/virtualNetworks/example-vnet/virtualNetworkPeerings/example-peering",
+	"ip_configuration":                "Microsoft.Network/networkInterfaces/example-nic/ipConfigurations/internal",
+	"gateway_load_balancer_frontend_ip_configuration": "Microsoft.Network/loadBalancers/example-gwlb/frontendIPConfigurations/example-fip",
+
+	// Identity, security and secrets
+	"user_assigned_identity":    "Microsoft.ManagedIdentity/userAssignedIdentities/example-uai",
+	"identity":                  "Microsoft.ManagedIdentity/userAssignedIdentities/example-uai",
+	"key_vault":                 "Microsoft.KeyVault/vaults/example-kv",
+	"key_vault_managed_hsm":     "Microsoft.KeyVault/managedHSMs/example-hsm",
+	"disk_encryption_set":       "Microsoft.Compute/diskEncryptionSets/example-des",
+	"role_definition":           "Microsoft.Authorization/roleDefinitions/00000000-0000-0000-0000-000000000001",
+	"policy_definition":         "Microsoft.Authorization/policyDefinitions/example-policy",
+	"policy_set_definition":     "Microsoft.Authorization/policySetDefinitions/example-initiative",
+	"security_center_workspace": "Microsoft.OperationalInsights/workspaces/example-law",
+
+	// Monitoring
+	"log_analytics_workspace":      "Microsoft.OperationalInsights/workspaces/example-law",
+	"workspace":                    "Microsoft.OperationalInsights/workspaces/example-law",
+	"log_analytics_destination":    "Microsoft.OperationalInsights/workspaces/example-law",
+	"application_insights":         "Microsoft.Insights/components/example-appi",
+	"monitor_action_group":         "Microsoft.Insights/actionGroups/example-ag",
+	"action_group":                 "Microsoft.Insights/actionGroups/example-ag",
+	"monitor_data_collection_rule": "Microsoft.Insights/dataCollectionRules/example-dcr",
+	"data_collection_rule":         "Microsoft.Insights/dataCollectionRules/example-dcr",
+	"data_collection_endpoint":     "Microsoft.Insights/dataCollectionEndpoints/example-dce",
+	"monitor_workspace":            "Microsoft.Monitor/accounts/example-amw",
+	"eventhub_authorization_rule":  "Microsoft.EventHub/namespaces/example-ehns/authorizationRules/RootManageSharedAccessKey",
+
+	// Storage and data
+	"storage_account":            "Microsoft.Storage/storageAccounts/examplestorage",
+	"storage_container":          "Microsoft.Storage/storageAccounts/examplestorage/blobServices/default/containers/example",
+	"storage_share":              "Microsoft.Storage/storageAccounts/examplestorage/fileServices/default/shares/example",
+	"mssql_server":               "Microsoft.Sql/servers/example-sql",
+	"server":                     "Microsoft.Sql/servers/example-sql",
+	"mssql_elasticpool":          "Microsoft.Sql/servers/example-sql/elasticPools/example-pool",
+	"elastic_pool":               "Microsoft.Sql/servers/example-sql/elasticPools/example-pool",
+	"mssql_database":             "Microsoft.Sql/servers/example-sql/databases/example-db",
+	"database":                   "Microsoft.Sql/servers/example-sql/databases/example-db",
+	"postgresql_flexible_server": "Microsoft.DBforPostgreSQL/flexibleServers/example-psql",
+	"mysql_flexible_server":      "Microsoft.DBforMySQL/flexibleServers/example-mysql",
+	"cosmosdb_account":           "Microsoft.DocumentDB/databaseAccounts/example-cosmos",
+	"redis_cache":                "Microsoft.Cache/redis/example-redis",
+	"data_factory":               "Microsoft.DataFactory/factories/example-adf",
+	"synapse_workspace":          "Microsoft.Synapse/workspaces/example-syn",
+	"databricks_workspace":       "Microsoft.Databricks/workspaces/example-dbw",
+	"eventhub_namespace":         "Microsoft.EventHub/namespaces/example-ehns",
+	"eventhub":                   "Microsoft.EventHub/namespaces/example-ehns/eventhubs/example-eh",
+	"servicebus_namespace":       "Microsoft.ServiceBus/namespaces/example-sbns",
+	"servicebus_queue":           "Microsoft.ServiceBus/namespaces/example-sbns/queues/example-queue",
+	"servicebus_topic":           "Microsoft.ServiceBus/namespaces/example-sbns/topics/example-topic",
+	"search_service":             "Microsoft.Search/searchServices/example-search",
+
+	// Compute and containers
+	"service_plan":                    "Microsoft.Web/serverFarms/example-asp",
+	"app_service_plan":                "Microsoft.Web/serverFarms/example-asp",
+	"app_service_environment":         "Microsoft.Web/hostingEnvironments/example-ase",
+	"linux_web_app":                   "Microsoft.Web/sites/example-app",
+	"windows_web_app":                 "Microsoft.Web/sites/example-app",
+	"app_service":                     "Microsoft.Web/sites/example-app",
+	"function_app":                    "Microsoft.Web/sites/example-func",
+	"container_app_environment":       "Microsoft.App/managedEnvironments/example-cae",
+	"container_registry":              "Microsoft.ContainerRegistry/registries/exampleacr",
+	"kubernetes_cluster":              "Microsoft.ContainerService/managedClusters/example-aks",
+	"virtual_machine":                 "Microsoft.Compute/virtualMachines/example-vm",
+	"linux_virtual_machine":           "Microsoft.Compute/virtualMachines/example-vm",
+	"windows_virtual_machine":         "Microsoft.Compute/virtualMachines/example-vm",
+	"virtual_machine_scale_set":       "Microsoft.Compute/virtualMachineScaleSets/example-vmss",
+	"availability_set":                "Microsoft.Compute/availabilitySets/example-avset",
+	"proximity_placement_group":       "Microsoft.Compute/proximityPlacementGroups/example-ppg",
+	"dedicated_host":                  "Microsoft.Compute/hostGroups/example-hg/hosts/example-host",
+	"dedicated_host_group":            "Microsoft.Compute/hostGroups/example-hg",
+	"capacity_reservation_group":      "Microsoft.Compute/capacityReservationGroups/example-crg",
+	"managed_disk":                    "Microsoft.Compute/disks/example-disk",
+	"source_image":                    "Microsoft.Compute/images/example-image",
+	"image":                           "Microsoft.Compute/images/example-image",
+	"shared_image":                    "Microsoft.Compute/galleries/examplegallery/images/example-image",
+	"gallery_application_version":     "Microsoft.Compute/galleries/examplegallery/applications/example-app/versions/1.0.0",
+	"snapshot":                        "Microsoft.Compute/snapshots/example-snapshot",
+	"api_management":                  "Microsoft.ApiManagement/service/example-apim",
+	"automation_account":              "Microsoft.Automation/automationAccounts/example-aa",
+	"recovery_services_vault":         "Microsoft.RecoveryServices/vaults/example-rsv",
+	"backup_policy":                   "Microsoft.RecoveryServices/vaults/example-rsv/backupPolicies/example-policy",
+	"machine_learning_workspace":      "Microsoft.MachineLearningServices/workspaces/example-mlw",
+	"cognitive_account":               "Microsoft.CognitiveServices/accounts/example-cog",
+	"communication_service":           "Microsoft.Communication/communicationServices/example-acs",
+	"load_test":                       "Microsoft.LoadTestService/loadTests/example-lt",
+	"dev_center":                      "Microsoft.DevCenter/devcenters/example-dc",
+	"healthcare_workspace":            "Microsoft.HealthcareApis/workspaces/example-hw",
+	"signalr_service":                 "Microsoft.SignalRService/signalR/example-signalr",
+	"web_pubsub":                      "Microsoft.SignalRService/webPubSub/example-wps",
+	"spring_cloud_service":            "Microsoft.AppPlatform/spring/example-spring",
+	"static_web_app":                  "Microsoft.Web/staticSites/example-swa",
+	"logic_app_workflow":              "Microsoft.Logic/workflows/example-la",
+	"integration_service_environment": "Microsoft.Logic/integrationServiceEnvironments/example-ise",
+}
+
+// contextualIDFormats resolve attribute names whose referenced type depends
+// on the referencing resource, keyed by "resource_type.attribute".
+var contextualIDFormats = map[string]string{
+	"azurerm_application_gateway.firewall_policy_id":        "Microsoft.Network/applicationGatewayWebApplicationFirewallPolicies/example-waf",
+	"azurerm_mssql_database.server_id":                      "Microsoft.Sql/servers/example-sql",
+	"azurerm_postgresql_flexible_server_database.server_id": "Microsoft.DBforPostgreSQL/flexibleServers/example-psql",
+	"azurerm_mysql_flexible_database.server_id":             "Microsoft.DBforMySQL/flexibleServers/example-mysql",
+}
+
+// guidAttributes are "_id" attributes holding a GUID rather than an ARM ID.
+var guidAttributes = map[string]bool{
+	"tenant_id": true, "client_id": true, "object_id": true, "principal_id": true,
+	"application_id": true, "subscription_id": true, "app_id": true,
+	"managed_identity_client_id": true, "service_principal_id": true,
+	"aad_tenant_id": true, "azure_ad_tenant_id": true, "user_object_id": true,
+}
+
+// dataPlaneIDs are "_id" attributes holding a Key Vault data plane URL.
+var dataPlaneIDs = map[string]string{
+	"key_vault_key_id":             "https://example-kv.vault.azure.net/keys/example-key/00000000000000000000000000000000",
+	"key_vault_secret_id":          "https://example-kv.vault.azure.net/secrets/example-secret/00000000000000000000000000000000",
+	"key_vault_certificate_id":     "https://example-kv.vault.azure.net/certificates/example-cert/00000000000000000000000000000000",
+	"customer_managed_key_id":      "https://example-kv.vault.azure.net/keys/example-key/00000000000000000000000000000000",
+	"versionless_key_id":           "https://example-kv.vault.azure.net/keys/example-key",
+	"key_vault_key_versionless_id": "https://example-kv.vault.azure.net/keys/example-key",
+}
+
+// referencedTypes returns the armIDFormats keys sorted longest first, so
+// suffix matching prefers "private_dns_zone" over "dns_zone".
+var referencedTypes = func() []string {
+	keys := make([]string, 0, len(armIDFormats))
+	for k := range armIDFormats {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}()
+
+// AzureResourceID returns an example ID in the format the attribute expects:
+// a GUID for tenant, client and principal IDs, a Key Vault URL for key and
+// secret IDs, and otherwise the ARM ID of the referenced resource type in the
+// given subscription and resource group. The referenced type is matched by
+// the longest known suffix of the attribute name, so "source_subnet_id"
+// resolves to a subnet. ok is false when the type is not known and the ID is
+// a generic placeholder.
+func AzureResourceID(resourceType, attrName, subscriptionID, resourceGroup string) (id string, ok bool) {
+	name := strings.TrimSuffix(strings.TrimSuffix(attrName, "_ids"), "_id")
+	if guidAttributes[attrName] || guidAttributes[name+"_id"] {
+		return "00000000-0000-0000-0000-000000000000", true
+	}
+	if url, found := dataPlaneIDs[name+"_id"]; found {
+		return url, true
+	}
+	if subscriptionID == "" {
+		subscriptionID = ExampleSubscriptionID
+	}
+	if resourceGroup == "" {
+		resourceGroup = ExampleResourceGroup
+	}
+	rgScope := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroup)
+
+	if name == "resource_group" {
+		return rgScope, true
+	}
+	if path, found := contextualIDFormats[resourceType+"."+name+"_id"]; found {
+		return rgScope + "/providers/" + path, true
+	}
+	for _, t := range referencedTypes {
+		if name == t || strings.HasSuffix(name, "_"+t) {
+			return rgScope + "/providers/" + armIDFormats[t], true
+		}
+	}
+	return fmt.Sprintf("%s/providers/Microsoft.Resources/resources/example-%s", rgScope, name), false
+}
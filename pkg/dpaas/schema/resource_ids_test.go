@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureResourceID(t *testing.T) {
+	for _, tc := range []struct {
+		resourceType, attr, want string
+		known                    bool
+	}{
+		{"azurerm_linux_web_app", "service_plan_id", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Web/serverFarms/example-asp", true},
+		{"azurerm_private_endpoint", "subnet_id", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/example-vnet/subnets/example-subnet", true},
+		{"azurerm_mssql_database", "server_id", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Sql/servers/example-sql", true},
+		{"azurerm_key_vault", "tenant_id", "00000000-0000-0000-0000-000000000000", true},
+		{"azurerm_storage_account", "resource_group_id", "/subscriptions/s/resourceGroups/rg", true},
+		{"azurerm_storage_account", "frobnicator_id", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Resources/resources/example-frobnicator", false},
+	} {
+		id, ok := AzureResourceID(tc.resourceType, tc.attr, "s", "rg")
+		assert.Equal(t, tc.want, id, tc.attr)
+		assert.Equal(t, tc.known, ok, tc.attr)
+	}
+}
@@ -0,0 +1,496 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// AzAPIResourceType is the resource every AzAPI-generated module wraps.
+const AzAPIResourceType = "azapi_resource"
+
+// maxRESTDepth bounds how deep nested REST objects become nested blocks;
+// deeper objects, and recursive definitions, are typed any.
+const maxRESTDepth = 4
+
+// AzAPIResource is an Azure resource type outside the azurerm provider,
+// described by an Azure REST API spec and managed through azapi_resource.
+type AzAPIResource struct {
+	Type       string // e.g. "Microsoft.App/containerApps"
+	APIVersion string // e.g. "2024-03-01"
+	// ResourceGroupScoped resources are created in var.resource_group_name;
+	// the others, child and extension resources, under var.parent_id.
+	ResourceGroupScoped bool
+	// Outputs maps each computed-only output to its path in the response
+	// body, e.g. "fqdn" → "properties.fqdn".
+	Outputs map[string]string
+	Info    *ResourceInfo // the module's inputs; attributes carry their RESTName
+}
+
+// TypeVersion is the azapi_resource type argument, e.g.
+// "Microsoft.App/containerApps@2024-03-01".
+func (r *AzAPIResource) TypeVersion() string {
+	return r.Type + "@" + r.APIVersion
+}
+
+// restSpec is the part of an Azure REST API spec (Swagger 2.0) read here.
+type restSpec struct {
+	Info struct {
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths       map[string]map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]*restSchema                `json:"definitions"`
+	Parameters  map[string]*restParameter             `json:"parameters"`
+}
+
+type restOperation struct {
+	Parameters []*restParameter `json:"parameters"`
+}
+
+type restParameter struct {
+	Ref    string      `json:"$ref"`
+	Name   string      `json:"name"`
+	In     string      `json:"in"`
+	Schema *restSchema `json:"schema"`
+}
+
+type restSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format"`
+	Description          string                 `json:"description"`
+	Properties           map[string]*restSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	ReadOnly             bool                   `json:"readOnly"`
+	Enum                 []any                  `json:"enum"`
+	Items                *restSchema            `json:"items"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	AllOf                []*restSchema          `json:"allOf"`
+	Secret               bool                   `json:"x-ms-secret"`
+	Mutability           []string               `json:"x-ms-mutability"`
+}
+
+// ParseRESTAPISpec reads the PUT request body of resourceType, e.g.
+// "Microsoft.App/containerApps", from an Azure REST API spec and returns the
+// module inputs of an azapi_resource wrapping it. A "@<api-version>" suffix
+// overrides the spec's version.
+//
+// Properties become snake_case variables, nested objects nested blocks and
+// read-only properties outputs. Definitions in other spec files cannot be
+// followed; a TrackedResource among them gives the resource location and tags.
+// ModuleName is left empty for the caller to set, e.g. with
+// TemplatePack.ModuleName.
+func ParseRESTAPISpec(data []byte, resourceType string) (*AzAPIResource, error) {
+	var spec restSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse REST API spec JSON: %w", err)
+	}
+
+	typeName, apiVersion, _ := strings.Cut(strings.TrimSpace(resourceType), "@")
+	if apiVersion == "" {
+		apiVersion = spec.Info.Version
+	}
+	if apiVersion == "" {
+		return nil, fmt.Errorf("the REST API spec has no info.version; pass the type as %s@<api-version>", typeName)
+	}
+
+	path, rgScoped, err := spec.putPath(typeName)
+	if err != nil {
+		return nil, err
+	}
+	var op restOperation
+	if err := json.Unmarshal(spec.Paths[path]["put"], &op); err != nil {
+		return nil, fmt.Errorf("PUT %s: %w", path, err)
+	}
+	var body *restSchema
+	for _, p := range op.Parameters {
+		if p = spec.parameter(p); p != nil && p.In == "body" {
+			body = p.Schema
+		}
+	}
+	if body == nil {
+		return nil, fmt.Errorf("PUT %s has no body parameter", path)
+	}
+
+	segments := strings.Split(typeName, "/")
+	shortName := singular(snakeCase(segments[len(segments)-1]))
+	info := &ResourceInfo{
+		ResourceType: AzAPIResourceType,
+		ShortName:    shortName,
+		DisplayName:  toDisplayName(shortName),
+	}
+	res := &AzAPIResource{
+		Type:                typeName,
+		APIVersion:          apiVersion,
+		ResourceGroupScoped: rgScoped,
+		Outputs:             map[string]string{},
+		Info:                info,
+	}
+
+	props, required, tracked := spec.merged(body, map[string]bool{})
+	_, hasLocation := props["location"]
+	_, hasTags := props["tags"]
+
+	info.Attributes = append(info.Attributes, ParsedAttribute{
+		Name:        "name",
+		TFType:      "string",
+		Description: fmt.Sprintf("The name of the %s.", info.DisplayName),
+		Required:    true,
+	})
+	if tracked || hasLocation {
+		info.Attributes = append(info.Attributes, ParsedAttribute{
+			Name:        "location",
+			TFType:      "string",
+			Description: "The Azure region the resource is created in.",
+			Required:    true,
+		})
+	}
+	if rgScoped {
+		info.Attributes = append(info.Attributes, ParsedAttribute{
+			Name:        "resource_group_name",
+			TFType:      "string",
+			Description: fmt.Sprintf("The name of the resource group in which to create the %s.", info.DisplayName),
+			Required:    true,
+		})
+	} else {
+		info.Attributes = append(info.Attributes, ParsedAttribute{
+			Name:        "parent_id",
+			TFType:      "string",
+			Description: fmt.Sprintf("The ID of the resource the %s is created in.", info.DisplayName),
+			Required:    true,
+		})
+	}
+	if tracked || hasTags {
+		info.Attributes = append(info.Attributes, ParsedAttribute{
+			Name:        "tags",
+			TFType:      "map(string)",
+			Description: "A mapping of tags to assign to the resource.",
+			Optional:    true,
+		})
+	}
+
+	taken := map[string]bool{}
+	for _, a := range info.Attributes {
+		taken[a.Name] = true
+	}
+	add := func(key, restPath string, s *restSchema, req bool) {
+		s = spec.resolve(s)
+		name := snakeCase(key)
+		if taken[name] {
+			name = shortName + "_" + name
+		}
+		taken[name] = true
+		if readOnly(s) {
+			res.Outputs[name] = restPath
+			info.ComputedOnlyAttrs = append(info.ComputedOnlyAttrs, name)
+			return
+		}
+		attr, block := spec.convert(name, s, req, 0, map[string]bool{})
+		if block != nil {
+			block.RESTName = restPath
+			info.Blocks = append(info.Blocks, *block)
+			return
+		}
+		attr.RESTName = restPath
+		info.Attributes = append(info.Attributes, attr)
+	}
+
+	for _, key := range sortedKeys(props) {
+		switch key {
+		case "id", "name", "type", "systemData", "etag", "location", "tags":
+			continue
+		case "properties":
+			inner, innerRequired, _ := spec.merged(props[key], map[string]bool{})
+			for _, k := range sortedKeys(inner) {
+				add(k, "properties."+k, inner[k], innerRequired[k])
+			}
+		default:
+			add(key, key, props[key], required[key])
+		}
+	}
+	sort.Strings(info.ComputedOnlyAttrs)
+	return res, nil
+}
+
+// putPath finds the path creating typeName and reports whether it is the
+// resource group scoped one. Paths are matched on their type segments after
+// the last /providers/, ignoring the name parameters between them.
+func (s *restSpec) putPath(typeName string) (string, bool, error) {
+	var found []string
+	for path, ops := range s.Paths {
+		if _, ok := ops["put"]; !ok {
+			continue
+		}
+		if t, _ := pathType(path); strings.EqualFold(t, typeName) {
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 {
+		return "", false, fmt.Errorf("the REST API spec has no PUT operation for %s", typeName)
+	}
+	sort.Strings(found)
+	for _, path := range found {
+		if _, rgScoped := pathType(path); rgScoped {
+			return path, true, nil
+		}
+	}
+	return found[0], false, nil
+}
+
+// pathType returns the resource type a path addresses and whether it is a
+// top-level resource in a resource group.
+func pathType(path string) (string, bool) {
+	i := strings.LastIndex(strings.ToLower(path), "/providers/")
+	if i < 0 {
+		return "", false
+	}
+	rest := strings.Split(strings.Trim(path[i+len("/providers/"):], "/"), "/")
+	if len(rest) < 3 || len(rest)%2 == 0 {
+		return "", false
+	}
+	typeSegments := []string{rest[0]}
+	for j := 1; j < len(rest); j += 2 {
+		typeSegments = append(typeSegments, rest[j])
+	}
+
+	scope := strings.Split(strings.Trim(path[:i], "/"), "/")
+	rgScoped := len(typeSegments) == 2 && len(scope) == 4 &&
+		strings.EqualFold(scope[0], "subscriptions") && strings.EqualFold(scope[2], "resourceGroups")
+	return strings.Join(typeSegments, "/"), rgScoped
+}
+
+// parameter resolves a parameter reference into the spec's own parameters.
+// References to other files, the common subscription and version
+// parameters, are never the body and resolve to nil.
+func (s *restSpec) parameter(p *restParameter) *restParameter {
+	if p == nil || p.Ref == "" {
+		return p
+	}
+	name, ok := strings.CutPrefix(p.Ref, "#/parameters/")
+	if !ok {
+		return nil
+	}
+	return s.Parameters[name]
+}
+
+// resolve follows local definition references. A reference into another
+// file resolves to the definition of the same name in this spec, if any;
+// otherwise it is returned unresolved.
+func (s *restSpec) resolve(sch *restSchema) *restSchema {
+	for i := 0; sch != nil && sch.Ref != "" && i < 10; i++ {
+		def, ok := s.Definitions[refName(sch.Ref)]
+		if !ok {
+			return sch
+		}
+		if sch.Description != "" && def.Description == "" {
+			d := *def
+			d.Description = sch.Description
+			def = &d
+		}
+		sch = def
+	}
+	return sch
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// merged returns the properties of an object schema including those of its
+// allOf parents, the required ones, and whether one of the parents is an
+// unresolved TrackedResource.
+func (s *restSpec) merged(sch *restSchema, seen map[string]bool) (map[string]*restSchema, map[string]bool, bool) {
+	props := map[string]*restSchema{}
+	required := map[string]bool{}
+	tracked := false
+
+	if sch != nil && sch.Ref != "" {
+		name := refName(sch.Ref)
+		if seen[name] {
+			return props, required, false
+		}
+		seen[name] = true
+		resolved := s.resolve(sch)
+		if resolved.Ref != "" {
+			return props, required, strings.HasSuffix(name, "TrackedResource")
+		}
+		sch = resolved
+	}
+	if sch == nil {
+		return props, required, false
+	}
+	for _, parent := range sch.AllOf {
+		p, r, t := s.merged(parent, seen)
+		for k, v := range p {
+			props[k] = v
+		}
+		for k := range r {
+			required[k] = true
+		}
+		tracked = tracked || t
+	}
+	for k, v := range sch.Properties {
+		props[k] = v
+	}
+	for _, k := range sch.Required {
+		required[k] = true
+	}
+	return props, required, tracked
+}
+
+// convert maps one property to an attribute, or to a nested block when it is
+// an object, or an array of objects, with properties of its own.
+func (s *restSpec) convert(name string, sch *restSchema, required bool, depth int, seen map[string]bool) (ParsedAttribute, *ParsedBlock) {
+	attr := ParsedAttribute{
+		Name:        name,
+		Description: sch.Description,
+		Required:    required,
+		Optional:    !required,
+		Sensitive:   sch.Secret || sch.Format == "password",
+		EnumValues:  enumStrings(sch.Enum),
+	}
+
+	if depth < maxRESTDepth {
+		nesting, element := "single", sch
+		if sch.Type == "array" {
+			nesting, element = "list", sch.Items
+		}
+		if element != nil {
+			ref := element.Ref
+			if ref == "" || !seen[refName(ref)] {
+				nested := copySeen(seen)
+				props, req, _ := s.merged(element, nested)
+				if len(props) > 0 && (nesting == "list" || isObject(s.resolve(element))) {
+					block := &ParsedBlock{Name: name, NestingMode: nesting, Required: required}
+					for _, k := range sortedKeys(props) {
+						p := s.resolve(props[k])
+						if readOnly(p) {
+							continue
+						}
+						a, b := s.convert(snakeCase(k), p, req[k], depth+1, nested)
+						if b != nil {
+							b.RESTName = k
+							block.Blocks = append(block.Blocks, *b)
+						} else {
+							a.RESTName = k
+							block.Attributes = append(block.Attributes, a)
+						}
+					}
+					if len(block.Attributes)+len(block.Blocks) > 0 {
+						return attr, block
+					}
+				}
+			}
+		}
+	}
+
+	attr.TFType = s.tfType(sch, 0)
+	return attr, nil
+}
+
+// tfType is the variable type of a property that is not a nested block.
+func (s *restSpec) tfType(sch *restSchema, depth int) string {
+	sch = s.resolve(sch)
+	if sch == nil || sch.Ref != "" || depth > maxRESTDepth {
+		return "any"
+	}
+	switch sch.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "list(" + s.tfType(sch.Items, depth+1) + ")"
+	}
+	if len(sch.Properties) == 0 && len(sch.AllOf) == 0 && len(sch.AdditionalProperties) > 0 {
+		var elem restSchema
+		if err := json.Unmarshal(sch.AdditionalProperties, &elem); err == nil {
+			return "map(" + s.tfType(&elem, depth+1) + ")"
+		}
+	}
+	if len(sch.Enum) > 0 {
+		return "string"
+	}
+	return "any"
+}
+
+func isObject(sch *restSchema) bool {
+	return sch != nil && (sch.Type == "object" || sch.Type == "") && len(sch.AdditionalProperties) == 0
+}
+
+func readOnly(sch *restSchema) bool {
+	if sch == nil {
+		return false
+	}
+	if sch.ReadOnly {
+		return true
+	}
+	return len(sch.Mutability) == 1 && sch.Mutability[0] == "read"
+}
+
+func enumStrings(values []any) []string {
+	var out []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func copySeen(seen map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(seen))
+	for k, v := range seen {
+		out[k] = v
+	}
+	return out
+}
+
+// snakeCase converts a REST property name to a variable name:
+// environmentId → environment_id, publicIPAddress → public_ip_address.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// singular turns a plural resource type segment into the resource's short
+// name: container_apps → container_app, policies → policy.
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
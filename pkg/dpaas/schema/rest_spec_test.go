@@ -0,0 +1,60 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRESTAPISpec(t *testing.T) {
+	res, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "Microsoft.App/containerApps")
+	require.NoError(t, err)
+	assert.Equal(t, "Microsoft.App/containerApps@2024-03-01", res.TypeVersion())
+	assert.True(t, res.ResourceGroupScoped)
+
+	info := res.Info
+	assert.Equal(t, schema.AzAPIResourceType, info.ResourceType)
+	assert.Equal(t, "container_app", info.ShortName)
+	assert.Empty(t, info.ModuleName, "the caller names the module")
+	assert.Equal(t, []string{"latest_revision_fqdn"}, info.ComputedOnlyAttrs)
+	assert.Equal(t, "properties.latestRevisionFqdn", res.Outputs["latest_revision_fqdn"])
+
+	attrs := map[string]schema.ParsedAttribute{}
+	for _, a := range info.Attributes {
+		attrs[a.Name] = a
+	}
+	for _, name := range []string{"name", "location", "resource_group_name", "tags", "environment_id", "workload_profile_name", "kind"} {
+		assert.Contains(t, attrs, name)
+	}
+	assert.True(t, attrs["environment_id"].Required)
+	assert.Equal(t, "properties.environmentId", attrs["environment_id"].RESTName)
+	assert.Equal(t, []string{"workflowapp", "functionapp"}, attrs["kind"].EnumValues)
+
+	require.Len(t, info.Blocks, 1)
+	config := info.Blocks[0]
+	assert.Equal(t, "configuration", config.Name)
+	assert.Equal(t, "single", config.NestingMode)
+	assert.Equal(t, "properties.configuration", config.RESTName)
+	blocks := map[string]schema.ParsedBlock{}
+	for _, b := range config.Blocks {
+		blocks[b.Name] = b
+	}
+	require.Contains(t, blocks, "ingress")
+	require.Contains(t, blocks, "secrets")
+	assert.Equal(t, "list", blocks["secrets"].NestingMode)
+	for _, a := range blocks["ingress"].Attributes {
+		assert.NotEqual(t, "fqdn", a.Name, "read-only properties are not inputs")
+	}
+}
+
+func TestParseRESTAPISpec_Errors(t *testing.T) {
+	_, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "Microsoft.App/managedEnvironments")
+	assert.ErrorContains(t, err, "no PUT operation")
+
+	res, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "microsoft.app/containerapps@2025-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-01", res.APIVersion)
+}
@@ -0,0 +1,219 @@
+// Package schematest provides resource schemas shared by the tests of the
+// packages that generate and validate modules.
+package schematest
+
+import "github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+
+// WindowsWebApp models a resource with a null-label name clash ("enabled"),
+// a map-style block and a single block holding a nested list block.
+func WindowsWebApp() *schema.ResourceInfo {
+	return &schema.ResourceInfo{
+		ResourceType: "azurerm_windows_web_app",
+		ShortName:    "windows_web_app",
+		ModuleName:   "expn-tf-azure-windows-web-app",
+		DisplayName:  "Windows Web App",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "enabled", TFType: "bool", Optional: true},
+			{Name: "https_only", TFType: "bool", Optional: true},
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "service_plan_id", TFType: "string", Required: true},
+			{Name: "tags", TFType: "map(string)", Optional: true},
+		},
+		Blocks: []schema.ParsedBlock{
+			{
+				Name:        "connection_string",
+				NestingMode: "set",
+				Attributes: []schema.ParsedAttribute{
+					{Name: "name", TFType: "string", Required: true},
+					{Name: "type", TFType: "string", Required: true},
+					{Name: "value", TFType: "string", Required: true, Sensitive: true},
+				},
+			},
+			{
+				Name:        "site_config",
+				NestingMode: "list",
+				MaxItems:    1,
+				Required:    true,
+				Attributes: []schema.ParsedAttribute{
+					{Name: "always_on", TFType: "bool", Optional: true},
+				},
+				Blocks: []schema.ParsedBlock{
+					{
+						Name:        "ip_restriction",
+						NestingMode: "list",
+						Attributes: []schema.ParsedAttribute{
+							{Name: "action", TFType: "string", Optional: true},
+							{Name: "ip_address", TFType: "string", Optional: true},
+						},
+					},
+				},
+			},
+		},
+		ComputedOnlyAttrs: []string{"default_hostname"},
+	}
+}
+
+// LinuxWebAppWithServicePlan models a web app that needs the ID of a plan
+// declared alongside it.
+func LinuxWebAppWithServicePlan() (webApp, plan *schema.ResourceInfo) {
+	webApp = &schema.ResourceInfo{
+		ResourceType: "azurerm_linux_web_app",
+		ShortName:    "linux_web_app",
+		ModuleName:   "expn-tf-azure-linux-web-app",
+		DisplayName:  "Linux Web App",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "service_plan_id", TFType: "string", Required: true},
+			{Name: "https_only", TFType: "bool", Optional: true},
+			{Name: "tags", TFType: "map(string)", Optional: true},
+		},
+		Blocks: []schema.ParsedBlock{{
+			Name: "site_config", NestingMode: "list", MaxItems: 1, Required: true,
+			Attributes: []schema.ParsedAttribute{{Name: "always_on", TFType: "bool", Optional: true}},
+		}},
+		ComputedOnlyAttrs: []string{"default_hostname"},
+	}
+	plan = &schema.ResourceInfo{
+		ResourceType: "azurerm_service_plan",
+		ShortName:    "service_plan",
+		ModuleName:   "expn-tf-azure-service-plan",
+		DisplayName:  "Service Plan",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "os_type", TFType: "string", Required: true, EnumValues: []string{"Linux", "Windows", "WindowsContainer"}},
+			{Name: "sku_name", TFType: "string", Required: true},
+			{Name: "tags", TFType: "map(string)", Optional: true},
+		},
+	}
+	return webApp, plan
+}
+
+// StorageAccount models a resource with enum arguments, TLS and public
+// access settings of the security baseline, and tags.
+func StorageAccount() *schema.ResourceInfo {
+	return &schema.ResourceInfo{
+		ResourceType: "azurerm_storage_account",
+		ShortName:    "storage_account",
+		ModuleName:   "expn-tf-azure-storage-account",
+		DisplayName:  "Storage Account",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "account_tier", TFType: "string", Required: true, EnumValues: []string{"Standard", "Premium"}},
+			{Name: "min_tls_version", TFType: "string", Optional: true, EnumValues: []string{"TLS1_0", "TLS1_1", "TLS1_2"}},
+			{Name: "public_network_access_enabled", TFType: "bool", Optional: true},
+			{Name: "tags", TFType: "map(string)", Optional: true},
+		},
+		Blocks: []schema.ParsedBlock{{
+			Name:        "blob_properties",
+			NestingMode: "list",
+			MaxItems:    1,
+			Attributes: []schema.ParsedAttribute{
+				{Name: "default_service_version", TFType: "string", Optional: true},
+			},
+		}},
+	}
+}
+
+// StorageAccountWithContainer models a parent resource and a child resource
+// that references it by ID.
+func StorageAccountWithContainer() (account, container *schema.ResourceInfo) {
+	account = &schema.ResourceInfo{
+		ResourceType: "azurerm_storage_account",
+		ShortName:    "storage_account",
+		ModuleName:   "expn-tf-azure-storage-account",
+		DisplayName:  "Storage Account",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+			{Name: "account_tier", TFType: "string", Required: true},
+			{Name: "tags", TFType: "map(string)", Optional: true},
+		},
+	}
+	container = &schema.ResourceInfo{
+		ResourceType: "azurerm_storage_container",
+		ShortName:    "storage_container",
+		DisplayName:  "Storage Container",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "storage_account_id", TFType: "string", Optional: true},
+			{Name: "container_access_type", TFType: "string", Optional: true, EnumValues: []string{"blob", "container", "private"}},
+			{Name: "metadata", TFType: "map(string)", Optional: true},
+		},
+		ComputedOnlyAttrs: []string{"has_immutability_policy", "resource_manager_id"},
+	}
+	return account, container
+}
+
+// ContainerAppsSpec is a trimmed Azure REST API spec for
+// Microsoft.App/containerApps: a flattened properties object, read-only and
+// secret properties, an enum and an array of objects.
+const ContainerAppsSpec = `{
+  "swagger": "2.0",
+  "info": {"version": "2024-03-01"},
+  "paths": {
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.App/containerApps/{containerAppName}": {
+      "put": {
+        "parameters": [
+          {"$ref": "../../../../../common-types/resource-management/v5/types.json#/parameters/SubscriptionIdParameter"},
+          {"$ref": "#/parameters/ContainerAppEnvelope"}
+        ]
+      }
+    }
+  },
+  "parameters": {
+    "ContainerAppEnvelope": {"name": "containerAppEnvelope", "in": "body", "required": true, "schema": {"$ref": "#/definitions/ContainerApp"}}
+  },
+  "definitions": {
+    "ContainerApp": {
+      "allOf": [{"$ref": "../../../../../common-types/resource-management/v5/types.json#/definitions/TrackedResource"}],
+      "properties": {
+        "properties": {"x-ms-client-flatten": true, "$ref": "#/definitions/ContainerAppProperties"},
+        "kind": {"type": "string", "enum": ["workflowapp", "functionapp"], "description": "Metadata used to render different experiences."}
+      }
+    },
+    "ContainerAppProperties": {
+      "type": "object",
+      "required": ["environmentId"],
+      "properties": {
+        "environmentId": {"type": "string", "description": "Resource ID of the Container App's environment."},
+        "workloadProfileName": {"type": "string"},
+        "latestRevisionFqdn": {"type": "string", "readOnly": true, "description": "Fully Qualified Domain Name of the latest revision."},
+        "configuration": {"$ref": "#/definitions/Configuration"}
+      }
+    },
+    "Configuration": {
+      "type": "object",
+      "description": "Non versioned Container App configuration properties.",
+      "properties": {
+        "activeRevisionsMode": {"type": "string", "enum": ["Multiple", "Single"]},
+        "ingress": {
+          "type": "object",
+          "properties": {
+            "external": {"type": "boolean"},
+            "targetPort": {"type": "integer", "format": "int32"},
+            "fqdn": {"type": "string", "readOnly": true},
+            "traffic": {"type": "array", "items": {"$ref": "#/definitions/TrafficWeight"}}
+          }
+        },
+        "secrets": {"type": "array", "items": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "value": {"type": "string", "x-ms-secret": true}}}}
+      }
+    },
+    "TrafficWeight": {
+      "type": "object",
+      "properties": {
+        "revisionName": {"type": "string"},
+        "weight": {"type": "integer", "format": "int32"},
+        "latestRevision": {"type": "boolean"}
+      }
+    }
+  }
+}`
@@ -63,13 +63,17 @@ type ResourceInfo struct {
 // ParsedAttribute is one attribute that will become a variable and a resource argument.
 type ParsedAttribute struct {
 	Name        string
-	TFType      string   // Terraform variable type expression
+	TFType      string // Terraform variable type expression
 	Description string
 	Required    bool
 	Optional    bool
 	Computed    bool
 	Sensitive   bool
 	EnumValues  []string // possible values extracted from description
+	// RESTName is the key of an Azure REST API property in the azapi_resource
+	// body, dotted from the body root for top-level attributes
+	// (properties.environmentId); empty for provider schema attributes.
+	RESTName string
 }
 
 // ParsedBlock is one nested block that will become a variable (object/list) + dynamic block.
@@ -80,4 +84,5 @@ type ParsedBlock struct {
 	MaxItems    int
 	Attributes  []ParsedAttribute
 	Blocks      []ParsedBlock // recursively nested
+	RESTName    string        // as ParsedAttribute.RESTName
 }
@@ -1,6 +1,6 @@
 package templates
 
-import _ "embed"
+import "embed"
 
 //go:embed static/context.tf
 var ContextTf string
@@ -22,3 +22,9 @@ var NullLabelVarsPartB string
 
 //go:embed static/gitignore
 var Gitignore string
+
+// Packs holds the built-in template packs, one directory each; see
+// generators.LoadTemplatePack.
+//
+//go:embed packs
+var Packs embed.FS
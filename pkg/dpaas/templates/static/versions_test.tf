@@ -20,8 +20,3 @@ terraform {
     }
   }
 }
-provider "azurerm" {
-  subscription_id = "dbcd4abc-9638-497f-bab3-c6575bd67b72"
-  features {}
-
-}
@@ -0,0 +1,258 @@
+package validation
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+)
+
+// SchemaResolver returns the schemas of the given resource types. Batch
+// validation calls it concurrently, so implementations must be safe for
+// concurrent use.
+type SchemaResolver func(resourceTypes []string) ([]*schema.ResourceInfo, error)
+
+// BatchOptions configures ValidateBatch.
+type BatchOptions struct {
+	Options
+	// Pattern is the glob module directory names must match; defaults to
+	// the template pack's module pattern.
+	Pattern string
+	// Workers bounds how many modules are validated at once; defaults to
+	// the number of CPUs.
+	Workers int
+	// Resolve looks up the schemas of the resource types detected in each
+	// module. Nil validates without schemas, so coverage is not reported.
+	Resolve SchemaResolver
+	// RESTAPISpec is an Azure REST API spec the azapi_resource of each
+	// module is read from (see ReadAzAPIResource). Nil reports no coverage
+	// for azapi_resource.
+	RESTAPISpec []byte
+}
+
+// ModulePattern returns Pattern, or the module pattern of Pack when Pattern
+// is empty.
+func (o BatchOptions) ModulePattern() string {
+	switch {
+	case o.Pattern != "":
+		return o.Pattern
+	case o.Pack != nil:
+		return o.Pack.ModulePattern()
+	}
+	return generators.DefaultTemplatePack().ModulePattern()
+}
+
+// ModuleResult is the outcome of validating one module in a batch.
+type ModuleResult struct {
+	Path          string            `json:"path"` // relative to the batch root
+	ResourceTypes []string          `json:"resource_types"`
+	Report        *ValidationReport `json:"report,omitempty"`
+	Error         string            `json:"error,omitempty"` // set when the module could not be validated
+}
+
+// RuleFailures counts the modules a rule failed in.
+type RuleFailures struct {
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	Modules  int      `json:"modules"`
+}
+
+// Dashboard aggregates the results of a batch validation.
+type Dashboard struct {
+	Root            string         `json:"root"`
+	Modules         []ModuleResult `json:"modules"`
+	Total           int            `json:"total"`
+	Passed          int            `json:"passed"`
+	Failed          int            `json:"failed"`
+	Errored         int            `json:"errored"`
+	PassRate        float64        `json:"pass_rate"`        // percent of modules that passed
+	AverageCoverage float64        `json:"average_coverage"` // mean primary-resource coverage of modules with a coverage report
+	FailingRules    []RuleFailures `json:"failing_rules"`    // most common first
+}
+
+// FindModules returns every directory under root whose name matches pattern,
+// sorted. Matching directories are not descended into, and hidden and
+// .terraform directories are skipped. An empty pattern matches the built-in
+// template pack's modules.
+func FindModules(root, pattern string) ([]string, error) {
+	if pattern == "" {
+		pattern = generators.DefaultTemplatePack().ModulePattern()
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (name[0] == '.' || name == "node_modules") {
+			return filepath.SkipDir
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// ValidateBatch finds the modules under root and validates them in parallel
+// with a bounded worker pool. A module that cannot be validated is recorded
+// with an error rather than aborting the batch. Cancelling ctx stops workers
+// picking up further modules.
+func ValidateBatch(ctx context.Context, root string, opts BatchOptions) (*Dashboard, error) {
+	dirs, err := FindModules(root, opts.ModulePattern())
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(dirs) {
+		workers = len(dirs)
+	}
+
+	results := make([]ModuleResult, len(dirs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = validateBatchModule(root, dirs[i], opts)
+			}
+		}()
+	}
+
+feed:
+	for i := range dirs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return summariseBatch(root, results), nil
+}
+
+func validateBatchModule(root, dir string, opts BatchOptions) ModuleResult {
+	res := ModuleResult{Path: dir}
+	if rel, err := filepath.Rel(root, dir); err == nil {
+		res.Path = filepath.ToSlash(rel)
+	}
+
+	types, err := DetectResourceTypes(dir)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.ResourceTypes = types
+
+	var infos []*schema.ResourceInfo
+	if opts.Resolve != nil && len(types) > 0 {
+		if infos, err = opts.Resolve(types); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+	}
+	if opts.RESTAPISpec != nil && slices.Contains(types, schema.AzAPIResourceType) {
+		azapi, err := ReadAzAPIResource(dir, opts.RESTAPISpec)
+		if err != nil {
+			res.Error = err.Error()
+			return res
+		}
+		// the primary resource comes first
+		if types[0] == schema.AzAPIResourceType {
+			infos = append([]*schema.ResourceInfo{azapi.Info}, infos...)
+		} else {
+			infos = append(infos, azapi.Info)
+		}
+	}
+
+	report, err := ValidateResources(dir, infos, opts.Options)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Report = report
+	return res
+}
+
+func summariseBatch(root string, results []ModuleResult) *Dashboard {
+	d := &Dashboard{Root: root, Modules: results, Total: len(results), FailingRules: []RuleFailures{}}
+
+	failures := map[string]*RuleFailures{}
+	coverageSum, coverageCount := 0.0, 0
+	for _, m := range results {
+		switch {
+		case m.Report == nil:
+			d.Errored++
+			continue
+		case m.Report.Passed:
+			d.Passed++
+		default:
+			d.Failed++
+		}
+		if cr := m.Report.CoverageReport; cr != nil {
+			coverageSum += cr.CoveragePercent
+			coverageCount++
+		}
+
+		seen := map[string]bool{}
+		for _, c := range m.Report.Checks {
+			if c.Passed || seen[c.RuleID] {
+				continue
+			}
+			seen[c.RuleID] = true
+			rf, ok := failures[c.RuleID]
+			if !ok {
+				rf = &RuleFailures{RuleID: c.RuleID, Severity: c.Severity}
+				failures[c.RuleID] = rf
+			}
+			rf.Modules++
+		}
+	}
+
+	for _, rf := range failures {
+		d.FailingRules = append(d.FailingRules, *rf)
+	}
+	sort.Slice(d.FailingRules, func(i, j int) bool {
+		a, b := d.FailingRules[i], d.FailingRules[j]
+		if a.Modules != b.Modules {
+			return a.Modules > b.Modules
+		}
+		return a.RuleID < b.RuleID
+	})
+
+	d.PassRate = percent(d.Passed, d.Total)
+	if coverageCount > 0 {
+		d.AverageCoverage = coverageSum / float64(coverageCount)
+	}
+	return d
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBatch_Dashboard(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	root := t.TempDir()
+	module := generators.GenerateModule(info, []string{"default"})
+
+	good := filepath.Join(root, "modules", "expn-tf-azure-windows-web-app")
+	_, err := generators.WriteModule(good, module)
+	require.NoError(t, err)
+
+	broken := filepath.Join(root, "modules", "expn-tf-azure-broken")
+	_, err = generators.WriteModule(broken, module)
+	require.NoError(t, err)
+	breakCountPattern(t, broken)
+	require.NoError(t, os.Remove(filepath.Join(broken, "CHANGELOG.md")))
+
+	// not matched by the pattern, and hidden directories are skipped
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "shared"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git", "expn-tf-azure-ignored"), 0755))
+
+	var resolved atomic.Int32
+	d, err := ValidateBatch(context.Background(), root, BatchOptions{
+		Workers: 2,
+		Resolve: func(types []string) ([]*schema.ResourceInfo, error) {
+			resolved.Add(1)
+			if types[0] != info.ResourceType {
+				return nil, fmt.Errorf("unexpected type %s", types[0])
+			}
+			return []*schema.ResourceInfo{info}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, resolved.Load())
+	require.Equal(t, 2, d.Total)
+	assert.Equal(t, "modules/expn-tf-azure-broken", d.Modules[0].Path)
+	assert.Equal(t, []string{info.ResourceType}, d.Modules[0].ResourceTypes)
+	assert.Equal(t, 1, d.Passed)
+	assert.Equal(t, 1, d.Failed)
+	assert.Equal(t, float64(50), d.PassRate)
+	assert.Equal(t, float64(100), d.AverageCoverage)
+
+	// security warnings fire in both modules and sort first
+	require.Len(t, d.FailingRules, 4)
+	assert.Equal(t, "SEC002", d.FailingRules[0].RuleID)
+	assert.Equal(t, 2, d.FailingRules[0].Modules)
+	assert.Equal(t, "SEC004", d.FailingRules[1].RuleID)
+	assert.Equal(t, "DPAAS001", d.FailingRules[2].RuleID)
+	assert.Equal(t, 1, d.FailingRules[2].Modules)
+	assert.Equal(t, "DPAAS005", d.FailingRules[3].RuleID)
+}
+
+func TestValidateBatch_ResolverErrorRecordedPerModule(t *testing.T) {
+	root := t.TempDir()
+	_, err := generators.WriteModule(filepath.Join(root, "expn-tf-azure-windows-web-app"), generators.GenerateModule(schematest.WindowsWebApp(), []string{"default"}))
+	require.NoError(t, err)
+
+	d, err := ValidateBatch(context.Background(), root, BatchOptions{
+		Resolve: func([]string) ([]*schema.ResourceInfo, error) { return nil, fmt.Errorf("schema unavailable") },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, d.Errored)
+	assert.Equal(t, "schema unavailable", d.Modules[0].Error)
+	assert.Equal(t, float64(0), d.PassRate)
+}
+
+func TestValidateBatch_PatternFromPack(t *testing.T) {
+	pack := contosoPack(t)
+	info := schematest.WindowsWebApp()
+	root := t.TempDir()
+	module := generators.GenerateModule(info, []string{"default"})
+	for _, name := range []string{"ctso-azure-windows-web-app", "expn-tf-azure-windows-web-app"} {
+		_, err := generators.WriteModule(filepath.Join(root, name), module)
+		require.NoError(t, err)
+	}
+
+	opts := BatchOptions{Options: Options{Pack: pack}}
+	assert.Equal(t, "ctso-azure-*", opts.ModulePattern())
+	d, err := ValidateBatch(context.Background(), root, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, d.Total)
+	assert.Equal(t, "ctso-azure-windows-web-app", d.Modules[0].Path)
+
+	assert.Equal(t, "expn-tf-azure-*", BatchOptions{}.ModulePattern())
+	opts.Pattern = "*-web-app"
+	d, err = ValidateBatch(context.Background(), root, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, d.Total, "an explicit pattern wins over the pack's")
+}
+
+func TestValidateBatch_AzAPIModuleFromSpec(t *testing.T) {
+	res, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "Microsoft.App/containerApps")
+	require.NoError(t, err)
+	files, err := generators.GenerateAzAPIModule(res, generators.Options{Scenarios: []string{"default"}})
+	require.NoError(t, err)
+	root := t.TempDir()
+	_, err = generators.WriteModule(filepath.Join(root, "expn-tf-azure-container-app"), files)
+	require.NoError(t, err)
+
+	d, err := ValidateBatch(context.Background(), root, BatchOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, d.Modules[0].Report.CoverageReport, "no coverage without the spec")
+
+	d, err = ValidateBatch(context.Background(), root, BatchOptions{RESTAPISpec: []byte(schematest.ContainerAppsSpec)})
+	require.NoError(t, err)
+	require.Empty(t, d.Modules[0].Error)
+	require.NotNil(t, d.Modules[0].Report.CoverageReport)
+	assert.Equal(t, 100.0, d.Modules[0].Report.CoverageReport.CoveragePercent)
+	assert.Equal(t, 100.0, d.AverageCoverage)
+}
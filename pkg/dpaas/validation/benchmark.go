@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/version"
+)
+
+// benchmarkGroup maps report rules to one README benchmark row.
+type benchmarkGroup struct {
+	name        string
+	description string
+	match       func(ruleID string) bool
+}
+
+var benchmarkGroups = []benchmarkGroup{
+	{
+		name:        "DPaaS standards",
+		description: "DPaaS module structure, null-label, tagging and argument coverage rules",
+		match: func(id string) bool {
+			return !strings.HasPrefix(id, "SEC") && !strings.HasPrefix(id, "TF")
+		},
+	},
+	{
+		name:        "DPaaS security",
+		description: "Public network access, TLS, encryption at rest, diagnostics and NSG rules across test scenarios",
+		match:       func(id string) bool { return strings.HasPrefix(id, "SEC") },
+	},
+	{
+		name:        "terraform validate",
+		description: "terraform validate of every tests/ scenario",
+		match:       func(id string) bool { return id == "TF001" || id == "TF002" },
+	},
+	{
+		name:        "terraform test",
+		description: "terraform test of the module's .tftest.hcl files",
+		match:       func(id string) bool { return id == "TF003" },
+	},
+}
+
+// Benchmarks summarises the report as README benchmark rows, one per rule
+// group that produced checks. Only what was actually run is listed.
+func (r *ValidationReport) Benchmarks(date string) []generators.BenchmarkResult {
+	var out []generators.BenchmarkResult
+	for _, g := range benchmarkGroups {
+		row := generators.BenchmarkResult{
+			Name:        g.name,
+			Date:        date,
+			Version:     version.GetHumanVersion(),
+			Description: g.description,
+		}
+		ran := false
+		for _, c := range r.Checks {
+			if !g.match(c.RuleID) {
+				continue
+			}
+			ran = true
+			if c.Passed {
+				continue
+			}
+			if c.Severity == SeverityError {
+				row.Failed = true
+			} else {
+				row.Findings++
+			}
+		}
+		if ran {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// UpdateReadmeBenchmarks rewrites the benchmark table and review date of the
+// module's README.md from the report.
+func UpdateReadmeBenchmarks(modulePath string, r *ValidationReport, date string) error {
+	path := filepath.Join(modulePath, "README.md")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	updated, ok := generators.UpdateReadmeBenchmarks(string(raw), r.Benchmarks(date), date)
+	if !ok {
+		return fmt.Errorf("%s has no BEGIN_BENCHMARK_TABLE/END_BENCHMARK_TABLE markers", path)
+	}
+	if updated == string(raw) {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
@@ -0,0 +1,326 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// requiredFiles is the DPaaS innersource module layout.
+var requiredFiles = []string{
+	"context.tf", "locals.tf", "main.tf", "outputs.tf",
+	"variables.tf", "versions.tf", "README.md", "CHANGELOG.md",
+	".pre-commit-config.yaml", ".gitignore",
+}
+
+// builtinRules returns the standard DPaaS rule pack in evaluation order.
+func builtinRules() []Rule {
+	return []Rule{
+		{
+			ID:          "DPAAS001",
+			Severity:    SeverityError,
+			Description: "Module contains every file of the DPaaS layout",
+			Remediation: "Add the missing file; static files can be copied from the DPaaS templates",
+			Check: func(rc *RuleContext) []CheckResult {
+				var out []CheckResult
+				for _, f := range requiredFiles {
+					out = append(out, CheckResult{
+						Name:   fmt.Sprintf("File exists: %s", f),
+						Passed: fileExists(filepath.Join(rc.ModulePath, f)),
+					})
+				}
+				return out
+			},
+		},
+		{
+			ID:          "DPAAS002",
+			Severity:    SeverityError,
+			Description: "tests/ contains at least one example scenario",
+			Remediation: "Add a root module under tests/<scenario>/main.tf that calls the module",
+			Check: func(rc *RuleContext) []CheckResult {
+				testMains, _ := filepath.Glob(filepath.Join(rc.ModulePath, "tests", "*", "main.tf"))
+				return []CheckResult{{Name: "tests/ has at least one scenario", Passed: len(testMains) > 0}}
+			},
+		},
+		{
+			ID:          "DPAAS003",
+			Severity:    SeverityError,
+			Description: "All .tf files parse as valid HCL",
+			Remediation: "Fix the reported syntax errors",
+			Check: func(rc *RuleContext) []CheckResult {
+				return []CheckResult{{
+					Name:    "HCL parses without errors",
+					Passed:  !rc.mod.Diags.HasErrors(),
+					Message: diagSummary(rc.mod.Diags),
+				}}
+			},
+		},
+		{
+			ID:          "DPAAS004",
+			Severity:    SeverityError,
+			Description: "The wrapped resource is declared with the name 'this'",
+			Remediation: "Rename the resource block to \"this\"",
+			Check: mainTfCheck(func(rc *RuleContext, this *hclsyntax.Block) CheckResult {
+				return CheckResult{
+					Name:    "main.tf resource named 'this'",
+					Passed:  this != nil,
+					Message: fmt.Sprintf("Resource block must be declared as resource %q \"this\"", rc.Info.ResourceType),
+				}
+			}),
+		},
+		{
+			ID:          "DPAAS005",
+			Severity:    SeverityError,
+			Description: "The resource is created conditionally through local.enabled",
+			Remediation: "Resource must use: count = local.enabled ? 1 : 0",
+			Check: mainTfCheck(func(rc *RuleContext, this *hclsyntax.Block) CheckResult {
+				var count *hclsyntax.Attribute
+				if this != nil {
+					count = this.Body.Attributes["count"]
+				}
+				return CheckResult{Name: "main.tf uses count pattern", Passed: count != nil && isCountPattern(count.Expr)}
+			}),
+		},
+		{
+			ID:          "DPAAS006",
+			Severity:    SeverityError,
+			Description: "The resource tags come from local.tags",
+			Remediation: "Tags attribute must reference local.tags",
+			Check: mainTfCheck(func(rc *RuleContext, this *hclsyntax.Block) CheckResult {
+				var tags *hclsyntax.Attribute
+				if this != nil {
+					tags = this.Body.Attributes["tags"]
+				}
+				return CheckResult{
+					Name:   "main.tf references local.tags",
+					Passed: tags != nil && referencesTraversal(tags.Expr, "local", "tags"),
+				}
+			}),
+		},
+		{
+			ID:          "DPAAS007",
+			Severity:    SeverityError,
+			Description: "variables.tf keeps the null-label start and end banners",
+			Remediation: "Restore the null-label variable section from the DPaaS templates",
+			Check: variablesTfCheck(func(rc *RuleContext) []CheckResult {
+				return []CheckResult{
+					{Name: "variables.tf: Start of null-label marker", Passed: rc.mod.hasComment("variables.tf", "Start of null-label Variables")},
+					{Name: "variables.tf: End of null-label marker", Passed: rc.mod.hasComment("variables.tf", "End of null-label Variables")},
+				}
+			}),
+		},
+		{
+			ID:          "DPAAS008",
+			Severity:    SeverityError,
+			Description: "A create_<resource> flag variable is declared",
+			Remediation: "Declare a bool variable create_<resource> defaulting to true",
+			Check: variablesTfCheck(func(rc *RuleContext) []CheckResult {
+				createVar := "create_" + rc.Info.ShortName
+				_, ok := rc.mod.Variables[createVar]
+				return []CheckResult{{Name: "variables.tf: create_ flag present", Passed: ok, Message: fmt.Sprintf("Declare variable %q", createVar)}}
+			}),
+		},
+		{
+			ID:          "DPAAS009",
+			Severity:    SeverityError,
+			Description: "A <resource>_name override variable is declared",
+			Remediation: "Declare a string variable <resource>_name defaulting to null",
+			Check: variablesTfCheck(func(rc *RuleContext) []CheckResult {
+				nameVar := rc.Info.ShortName + "_name"
+				_, ok := rc.mod.Variables[nameVar]
+				return []CheckResult{{Name: "variables.tf: resource name variable present", Passed: ok, Message: fmt.Sprintf("Declare variable %q", nameVar)}}
+			}),
+		},
+		{
+			ID:          "DPAAS010",
+			Severity:    SeverityError,
+			Description: "locals.tf defines the template pack's tag set",
+			Remediation: "Define the template pack's tag local (local.dpaas_tags in the built-in pack) in locals.tf",
+			Check: localsTfCheck(func(rc *RuleContext) []CheckResult {
+				tagLocal := rc.Options.Pack.TagLocal
+				_, ok := rc.mod.Locals[tagLocal]
+				return []CheckResult{{Name: fmt.Sprintf("locals.tf: %s defined", tagLocal), Passed: ok}}
+			}),
+		},
+		{
+			ID:          "DPAAS011",
+			Severity:    SeverityError,
+			Description: "The tag set carries the template pack's required tags",
+			Remediation: `The tag local must contain every required tag key, e.g. "innersource" in local.dpaas_tags`,
+			Check: localsTfCheck(func(rc *RuleContext) []CheckResult {
+				tagLocal := rc.Options.Pack.TagLocal
+				tags, ok := rc.mod.Locals[tagLocal]
+				var results []CheckResult
+				for _, key := range rc.Options.Pack.RequiredTags {
+					results = append(results, CheckResult{
+						Name:    fmt.Sprintf("locals.tf: %s tag present", key),
+						Passed:  ok && objectHasKey(tags.Expr, key),
+						Message: fmt.Sprintf("local.%s must contain the %q key", tagLocal, key),
+					})
+				}
+				return results
+			}),
+		},
+		{
+			ID:          "DPAAS012",
+			Severity:    SeverityError,
+			Description: "local.enabled honours the create_<resource> flag",
+			Remediation: "local.enabled must reference var.create_<resource>",
+			Check: localsTfCheck(func(rc *RuleContext) []CheckResult {
+				enabled, ok := rc.mod.Locals["enabled"]
+				return []CheckResult{{
+					Name:    "locals.tf: enabled uses create_ flag",
+					Passed:  ok && referencesTraversal(enabled.Expr, "var", "create_"+rc.Info.ShortName),
+					Message: fmt.Sprintf("local.enabled must reference var.create_%s", rc.Info.ShortName),
+				}}
+			}),
+		},
+		{
+			ID:          "DPAAS013",
+			Severity:    SeverityError,
+			Description: "Every var.* reference has a matching variable block",
+			Remediation: "Declare the referenced variables or fix the references",
+			Check: func(rc *RuleContext) []CheckResult {
+				undeclared := rc.mod.undeclaredVariables()
+				return []CheckResult{{
+					Name:    "All variable references are declared",
+					Passed:  len(undeclared) == 0,
+					Message: fmt.Sprintf("Undeclared: %s", strings.Join(undeclared, ", ")),
+				}}
+			},
+		},
+		{
+			ID:          "DPAAS014",
+			Severity:    SeverityError,
+			Description: "Every schema argument and nested block is wired to an input variable",
+			Remediation: "Expose the missing arguments as variables and wire them into the resource",
+			Check: func(rc *RuleContext) []CheckResult {
+				var out []CheckResult
+				for _, info := range rc.Resources {
+					cr := rc.CoverageFor(info)
+					name := fmt.Sprintf("Argument coverage: %.0f%%", cr.CoveragePercent)
+					if len(rc.Resources) > 1 {
+						name = fmt.Sprintf("Argument coverage (%s): %.0f%%", info.ResourceType, cr.CoveragePercent)
+					}
+					out = append(out, CheckResult{
+						Name:    name,
+						Passed:  cr.CoveragePercent == 100,
+						Message: fmt.Sprintf("Missing attrs: %v  Missing blocks: %v", cr.MissingAttrs, cr.MissingBlocks),
+					})
+				}
+				return out
+			},
+		},
+		{
+			ID:          "DPAAS015",
+			Severity:    SeverityInfo,
+			Description: "Computed-only resource attributes are exported as outputs",
+			Remediation: "Add an output for each listed computed attribute",
+			Check: func(rc *RuleContext) []CheckResult {
+				if rc.Info == nil || !rc.hasFile("outputs.tf") {
+					return nil
+				}
+				var missing []string
+				for _, name := range rc.Info.ComputedOnlyAttrs {
+					if _, ok := rc.mod.Outputs[name]; !ok {
+						missing = append(missing, name)
+					}
+				}
+				return []CheckResult{{
+					Name:    "Computed attributes exported as outputs",
+					Passed:  len(missing) == 0,
+					Message: fmt.Sprintf("Missing outputs: %s", strings.Join(missing, ", ")),
+				}}
+			},
+		},
+		{
+			ID:          "DPAAS016",
+			Severity:    SeverityWarning,
+			Description: "Every input variable has a description",
+			Remediation: "Add a description to each listed variable",
+			Check: func(rc *RuleContext) []CheckResult {
+				var missing []string
+				for name, v := range rc.mod.Variables {
+					if _, ok := v.Body.Attributes["description"]; !ok {
+						missing = append(missing, name)
+					}
+				}
+				sort.Strings(missing)
+				return []CheckResult{{
+					Name:    "All variables have descriptions",
+					Passed:  len(missing) == 0,
+					Message: fmt.Sprintf("Missing description: %s", strings.Join(missing, ", ")),
+				}}
+			},
+		},
+		{
+			ID:          "TF001",
+			Severity:    SeverityError,
+			Description: "terraform init succeeds in every test scenario",
+			Remediation: "Make the providers available in the provider mirror and fix the reported init errors",
+			Check: func(rc *RuleContext) []CheckResult {
+				if tr := rc.terraformStage(); tr != nil {
+					return tr.Init
+				}
+				return nil
+			},
+		},
+		{
+			ID:          "TF002",
+			Severity:    SeverityError,
+			Description: "terraform validate reports no errors in any test scenario",
+			Remediation: "Fix the reported configuration errors",
+			Check: func(rc *RuleContext) []CheckResult {
+				if tr := rc.terraformStage(); tr != nil {
+					return tr.Validate
+				}
+				return nil
+			},
+		},
+		{
+			ID:          "TF003",
+			Severity:    SeverityError,
+			Description: "terraform test passes for the module's .tftest.hcl files",
+			Remediation: "Fix the failing test assertions",
+			Check: func(rc *RuleContext) []CheckResult {
+				if tr := rc.terraformStage(); tr != nil {
+					return tr.Test
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// mainTfCheck adapts a check on the resource "this" block; it applies only
+// when main.tf parsed and the resource type is known.
+func mainTfCheck(fn func(rc *RuleContext, this *hclsyntax.Block) CheckResult) func(rc *RuleContext) []CheckResult {
+	return func(rc *RuleContext) []CheckResult {
+		if rc.Info == nil || !rc.hasFile("main.tf") {
+			return nil
+		}
+		return []CheckResult{fn(rc, rc.mod.resource(rc.Info.ResourceType, "this"))}
+	}
+}
+
+// variablesTfCheck applies fn only when variables.tf parsed and the resource type is known.
+func variablesTfCheck(fn func(rc *RuleContext) []CheckResult) func(rc *RuleContext) []CheckResult {
+	return fileCheck("variables.tf", fn)
+}
+
+// localsTfCheck applies fn only when locals.tf parsed and the resource type is known.
+func localsTfCheck(fn func(rc *RuleContext) []CheckResult) func(rc *RuleContext) []CheckResult {
+	return fileCheck("locals.tf", fn)
+}
+
+func fileCheck(name string, fn func(rc *RuleContext) []CheckResult) func(rc *RuleContext) []CheckResult {
+	return func(rc *RuleContext) []CheckResult {
+		if rc.Info == nil || !rc.hasFile(name) {
+			return nil
+		}
+		return fn(rc)
+	}
+}
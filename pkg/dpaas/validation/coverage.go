@@ -0,0 +1,450 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// CoverageReport quantifies how many schema items are wired into the module.
+// Counts and missing lists span every nesting level; nested items are reported
+// by dotted path (e.g. "site_config.application_stack.dotnet_version").
+type CoverageReport struct {
+	SchemaAttrCount     int      `json:"schema_attr_count"`
+	GeneratedAttrCount  int      `json:"generated_attr_count"`
+	SchemaBlockCount    int      `json:"schema_block_count"`
+	GeneratedBlockCount int      `json:"generated_block_count"`
+	MissingAttrs        []string `json:"missing_attrs,omitempty"`
+	MissingBlocks       []string `json:"missing_blocks,omitempty"`
+	CoveragePercent     float64  `json:"coverage_percent"`
+
+	Entries             []CoverageEntry `json:"entries"`                         // one per schema attribute/block path
+	Levels              []LevelCoverage `json:"levels"`                          // coverage per nesting depth, 0 = top level
+	UnwiredObjectFields []string        `json:"unwired_object_fields,omitempty"` // object-typed variable fields main.tf never reads
+}
+
+// CoverageEntry maps one schema path to where it is wired in the module.
+type CoverageEntry struct {
+	Path     string `json:"path"` // schema path, e.g. "site_config.always_on"
+	Kind     string `json:"kind"` // "attribute" or "block"
+	Depth    int    `json:"depth"`
+	Wired    bool   `json:"wired"`
+	Variable string `json:"variable,omitempty"` // input variable path feeding it, e.g. "windows_web_app_enabled" or "site_config.always_on"
+	// Reference is the resource of the module feeding it instead of a
+	// variable, e.g. "azurerm_service_plan.service_plan" in a composite module.
+	Reference string `json:"reference,omitempty"`
+	Location  string `json:"location,omitempty"` // "main.tf:12" when wired
+}
+
+// LevelCoverage is the wired/total ratio for one nesting depth.
+type LevelCoverage struct {
+	Depth   int     `json:"depth"`
+	Total   int     `json:"total"`
+	Wired   int     `json:"wired"`
+	Percent float64 `json:"percent"`
+}
+
+// ---------------------------------------------------------------------------
+
+// coverageScope maps dynamic-block iterator names to the variable path they iterate.
+type coverageScope map[string]string
+
+// checkCoverage walks the schema alongside the wrapped resource block (see
+// wrappedResource). A schema item is covered only when its argument (or
+// dynamic block) is actually set from an input variable, directly, through
+// locals or through a dynamic iterator, or from another resource the module
+// declares. Renamed variables are therefore found wherever they are wired,
+// and commented-out code never counts.
+func checkCoverage(mod *parsedModule, info *schema.ResourceInfo) *CoverageReport {
+	cr := &CoverageReport{}
+
+	if info.ResourceType == schema.AzAPIResourceType {
+		return checkAzAPICoverage(mod, info)
+	}
+
+	var body *hclsyntax.Body
+	scope := coverageScope{}
+	if blk := mod.wrappedResource(info.ResourceType); blk != nil {
+		body = blk.Body
+		// a child collection: each.value reads the map variable for_each iterates
+		if forEach, ok := blk.Body.Attributes["for_each"]; ok {
+			for _, tr := range forEach.Expr.Variables() {
+				if root, name := traversalHead(tr); root == "var" && mod.Variables[name] != nil {
+					scope = scope.with("each", name)
+					break
+				}
+			}
+		}
+	}
+
+	var attrs []schema.ParsedAttribute
+	for _, a := range info.Attributes {
+		if a.Name != "id" {
+			attrs = append(attrs, a)
+		}
+	}
+	cr.walk(mod, body, scope, "", 0, attrs, info.Blocks)
+	cr.UnwiredObjectFields = unwiredObjectFields(mod, cr.Entries)
+	cr.summarise()
+	return cr
+}
+
+func (cr *CoverageReport) walk(mod *parsedModule, body *hclsyntax.Body, scope coverageScope, prefix string, depth int, attrs []schema.ParsedAttribute, blocks []schema.ParsedBlock) {
+	for _, a := range attrs {
+		e := CoverageEntry{Path: prefix + a.Name, Kind: "attribute", Depth: depth}
+		if body != nil {
+			if attr, ok := body.Attributes[a.Name]; ok {
+				if v := mod.variablePath(attr.Expr, scope); v != "" {
+					e.Wired = true
+					e.Variable = v
+					e.Location = rangeLocation(attr.SrcRange)
+				} else if ref := mod.resourceReference(attr.Expr); ref != "" {
+					e.Wired = true
+					e.Reference = ref
+					e.Location = rangeLocation(attr.SrcRange)
+				}
+			}
+		}
+		cr.Entries = append(cr.Entries, e)
+	}
+
+	for _, b := range blocks {
+		e := CoverageEntry{Path: prefix + b.Name, Kind: "block", Depth: depth}
+		var content *hclsyntax.Body
+		childScope := scope
+
+		if body != nil {
+			if blk, dynamic := blockByName(body, b.Name); blk != nil {
+				if dynamic {
+					if forEach, ok := blk.Body.Attributes["for_each"]; ok {
+						if v := mod.variablePath(forEach.Expr, scope); v != "" {
+							e.Wired = true
+							e.Variable = v
+							e.Location = rangeLocation(blk.DefRange())
+							childScope = scope.with(dynamicIterator(blk), v)
+						}
+					}
+					content = dynamicContent(blk)
+				} else {
+					content = blk.Body
+					e.Location = rangeLocation(blk.DefRange())
+					for _, attr := range blk.Body.Attributes {
+						if mod.variablePath(attr.Expr, scope) != "" {
+							e.Wired = true
+							break
+						}
+					}
+				}
+			}
+		}
+		if !e.Wired {
+			e.Location = ""
+			content = nil
+		}
+		cr.Entries = append(cr.Entries, e)
+		cr.walk(mod, content, childScope, e.Path+".", depth+1, b.Attributes, b.Blocks)
+	}
+}
+
+// checkAzAPICoverage covers an azapi_resource, whose REST properties are
+// keys of its body object rather than arguments: an attribute or block is
+// wired when its key in body, or in body.properties, is set from a variable.
+// Nested fields are not walked; a nested object is wired as a whole.
+func checkAzAPICoverage(mod *parsedModule, info *schema.ResourceInfo) *CoverageReport {
+	cr := &CoverageReport{}
+
+	args := hclsyntax.Attributes{}
+	items := map[string]hclsyntax.Expression{}
+	if blk := mod.wrappedResource(info.ResourceType); blk != nil {
+		args = blk.Body.Attributes
+		if body, ok := args["body"]; ok {
+			objectItems(body.Expr, "", items)
+		}
+	}
+	wire := func(e CoverageEntry, expr hclsyntax.Expression) {
+		if expr != nil {
+			if v := mod.variablePath(expr, coverageScope{}); v != "" {
+				e.Wired = true
+				e.Variable = v
+				e.Location = rangeLocation(expr.Range())
+			}
+		}
+		cr.Entries = append(cr.Entries, e)
+	}
+
+	for _, a := range info.Attributes {
+		var expr hclsyntax.Expression
+		switch {
+		case a.Name == "id":
+			continue
+		case a.RESTName != "":
+			expr = items[a.RESTName]
+		case a.Name == "resource_group_name" && args["parent_id"] != nil:
+			// part of the parent ID
+			expr = args["parent_id"].Expr
+		case args[a.Name] != nil:
+			expr = args[a.Name].Expr
+		}
+		wire(CoverageEntry{Path: a.Name, Kind: "attribute"}, expr)
+	}
+	for _, b := range info.Blocks {
+		wire(CoverageEntry{Path: b.Name, Kind: "block"}, items[b.RESTName])
+	}
+	cr.summarise()
+	return cr
+}
+
+// objectItems collects the items of an object constructor by key, descending
+// into the top-level properties object as "properties.<key>".
+func objectItems(expr hclsyntax.Expression, prefix string, items map[string]hclsyntax.Expression) {
+	obj, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return
+	}
+	for _, item := range obj.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.Type() != cty.String || !key.IsKnown() || key.IsNull() {
+			continue
+		}
+		items[prefix+key.AsString()] = item.ValueExpr
+		if prefix == "" && key.AsString() == "properties" {
+			objectItems(item.ValueExpr, "properties.", items)
+		}
+	}
+}
+
+func (cr *CoverageReport) summarise() {
+	levels := map[int]*LevelCoverage{}
+	for _, e := range cr.Entries {
+		lc, ok := levels[e.Depth]
+		if !ok {
+			lc = &LevelCoverage{Depth: e.Depth}
+			levels[e.Depth] = lc
+		}
+		lc.Total++
+
+		switch e.Kind {
+		case "attribute":
+			cr.SchemaAttrCount++
+			if e.Wired {
+				cr.GeneratedAttrCount++
+			} else {
+				cr.MissingAttrs = append(cr.MissingAttrs, e.Path)
+			}
+		case "block":
+			cr.SchemaBlockCount++
+			if e.Wired {
+				cr.GeneratedBlockCount++
+			} else {
+				cr.MissingBlocks = append(cr.MissingBlocks, e.Path)
+			}
+		}
+		if e.Wired {
+			lc.Wired++
+		}
+	}
+
+	for depth := 0; depth < len(levels); depth++ {
+		lc := levels[depth]
+		if lc == nil {
+			continue
+		}
+		lc.Percent = percent(lc.Wired, lc.Total)
+		cr.Levels = append(cr.Levels, *lc)
+	}
+
+	cr.CoveragePercent = percent(
+		cr.GeneratedAttrCount+cr.GeneratedBlockCount,
+		cr.SchemaAttrCount+cr.SchemaBlockCount,
+	)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+func (s coverageScope) with(iterator, varPath string) coverageScope {
+	next := make(coverageScope, len(s)+1)
+	for k, v := range s {
+		next[k] = v
+	}
+	next[iterator] = varPath
+	return next
+}
+
+// ---------------------------------------------------------------------------
+// Expression → variable path resolution
+// ---------------------------------------------------------------------------
+
+// variablePath returns the dotted input-variable path expr reads from, or ""
+// when it is not variable-driven. var.x.y → "x.y"; an iterator reference
+// it.value.z inside a dynamic block over var.x → "x.z"; locals are followed.
+func (m *parsedModule) variablePath(expr hcl.Expression, scope coverageScope) string {
+	return m.variablePathSeen(expr, scope, map[string]bool{})
+}
+
+func (m *parsedModule) variablePathSeen(expr hcl.Expression, scope coverageScope, seen map[string]bool) string {
+	for _, tr := range expr.Variables() {
+		root, name := traversalHead(tr)
+		switch {
+		case root == "var":
+			if _, ok := m.Variables[name]; ok {
+				return joinPath(name, attrSteps(tr[2:]))
+			}
+		case root == "local":
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if l, ok := m.Locals[name]; ok {
+				if v := m.variablePathSeen(l.Expr, scope, seen); v != "" {
+					return v
+				}
+			}
+		default:
+			if base, ok := scope[root]; ok {
+				switch name {
+				case "value":
+					return joinPath(base, attrSteps(tr[2:]))
+				case "key": // the map key, e.g. a child resource's name
+					return base
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// resourceReference returns the address of the module resource expr reads,
+// e.g. "azurerm_service_plan.service_plan", or "" when it reads none.
+func (m *parsedModule) resourceReference(expr hcl.Expression) string {
+	for _, tr := range expr.Variables() {
+		if len(tr) < 2 {
+			continue
+		}
+		if name, ok := tr[1].(hcl.TraverseAttr); ok && m.resource(tr.RootName(), name.Name) != nil {
+			return tr.RootName() + "." + name.Name
+		}
+	}
+	return ""
+}
+
+// attrSteps renders the attribute steps of a traversal tail, stopping at the
+// first index or splat step.
+func attrSteps(steps hcl.Traversal) []string {
+	var out []string
+	for _, s := range steps {
+		a, ok := s.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		out = append(out, a.Name)
+	}
+	return out
+}
+
+func joinPath(base string, steps []string) string {
+	if len(steps) == 0 {
+		return base
+	}
+	return base + "." + strings.Join(steps, ".")
+}
+
+// dynamicIterator returns the iterator symbol of a dynamic block — the explicit
+// `iterator` argument when set, otherwise the block label.
+func dynamicIterator(blk *hclsyntax.Block) string {
+	if it, ok := blk.Body.Attributes["iterator"]; ok {
+		if trav, ok := it.Expr.(*hclsyntax.ScopeTraversalExpr); ok {
+			return trav.Traversal.RootName()
+		}
+	}
+	return blk.Labels[0]
+}
+
+func dynamicContent(blk *hclsyntax.Block) *hclsyntax.Body {
+	for _, inner := range blk.Body.Blocks {
+		if inner.Type == "content" {
+			return inner.Body
+		}
+	}
+	return nil
+}
+
+func rangeLocation(rng hcl.Range) string {
+	return fmt.Sprintf("%s:%d", rng.Filename, rng.Start.Line)
+}
+
+// ---------------------------------------------------------------------------
+// Unwired object fields
+// ---------------------------------------------------------------------------
+
+// unwiredObjectFields lists fields declared in the object types of the variables
+// feeding the resource whose paths are never read by main.tf.
+func unwiredObjectFields(mod *parsedModule, entries []CoverageEntry) []string {
+	read := map[string]bool{}
+	roots := map[string]bool{}
+	for _, e := range entries {
+		if !e.Wired || e.Variable == "" {
+			continue
+		}
+		read[e.Variable] = true
+		roots[strings.SplitN(e.Variable, ".", 2)[0]] = true
+	}
+
+	var unwired []string
+	for name := range roots {
+		v, ok := mod.Variables[name]
+		if !ok {
+			continue
+		}
+		typeAttr, ok := v.Body.Attributes["type"]
+		if !ok {
+			continue
+		}
+		for _, field := range objectFieldPaths(typeAttr.Expr, name) {
+			if !read[field] {
+				unwired = append(unwired, field)
+			}
+		}
+	}
+	sort.Strings(unwired)
+	return unwired
+}
+
+// objectFieldPaths flattens a type constraint expression into the dotted paths
+// of every object field it declares, looking through map/list/set/optional.
+func objectFieldPaths(expr hclsyntax.Expression, prefix string) []string {
+	call, ok := expr.(*hclsyntax.FunctionCallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	switch call.Name {
+	case "map", "list", "set", "optional":
+		return objectFieldPaths(call.Args[0], prefix)
+	case "object":
+		obj, ok := call.Args[0].(*hclsyntax.ObjectConsExpr)
+		if !ok {
+			return nil
+		}
+		var paths []string
+		for _, item := range obj.Items {
+			key := hcl.ExprAsKeyword(item.KeyExpr)
+			if key == "" {
+				continue
+			}
+			path := prefix + "." + key
+			paths = append(paths, path)
+			paths = append(paths, objectFieldPaths(item.ValueExpr, path)...)
+		}
+		return paths
+	}
+	return nil
+}
@@ -0,0 +1,505 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/templates"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FixAction is one change FixModule made, or declined to make.
+type FixAction struct {
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	Description string `json:"description"`
+}
+
+// FixResult lists what FixModule changed and what it left alone.
+type FixResult struct {
+	Applied []FixAction `json:"applied"`
+	Skipped []FixAction `json:"skipped"` // ambiguous findings that need a human
+	Diff    string      `json:"diff"`    // unified diff of every changed file
+}
+
+// FixOptions configures FixModule.
+type FixOptions struct {
+	// DryRun computes the fixes and the diff without writing any file.
+	DryRun bool
+	// Pack renders the CHANGELOG.md of a module without one; nil selects
+	// the built-in pack.
+	Pack *generators.TemplatePack
+}
+
+// staticFiles are the DPaaS files copied verbatim from the templates.
+var staticFiles = map[string]string{
+	"context.tf":              templates.ContextTf,
+	"versions.tf":             templates.VersionsRootTf,
+	".gitignore":              templates.Gitignore,
+	".pre-commit-config.yaml": templates.PreCommitConfig,
+}
+
+// FixModule applies the mechanical fixes for common validation failures of
+// the primary resource described by info:
+//
+//   - missing static files (context.tf, versions.tf, .gitignore,
+//     .pre-commit-config.yaml) are created from the templates, and a missing
+//     CHANGELOG.md from the template pack
+//   - the null-label variable section and the create_<resource> flag are added
+//     to variables.tf when absent
+//   - tags = local.tags is set on the resource when it sets no tags
+//   - uncovered top-level attributes and blocks are declared as variables and
+//     wired into the resource
+//
+// Anything ambiguous — an argument set to something other than a variable,
+// a partial null-label section, missing nested arguments — is left alone and
+// reported in Skipped. Files are only rewritten in canonical HCL formatting
+// when they were already canonically formatted.
+func FixModule(modulePath string, info *schema.ResourceInfo, opts FixOptions) (*FixResult, error) {
+	mod, err := parseModule(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	if mod.Diags.HasErrors() {
+		return nil, fmt.Errorf("module has syntax errors, fix them first: %s", diagSummary(mod.Diags))
+	}
+
+	if opts.Pack == nil {
+		opts.Pack = generators.DefaultTemplatePack()
+	}
+
+	f := &fixer{mod: mod, info: info, pack: opts.Pack, result: &FixResult{}, files: map[string]*fixedFile{}}
+	if err := f.staticFiles(); err != nil {
+		return nil, err
+	}
+	f.variablesTf()
+	if err := f.mainTf(); err != nil {
+		return nil, err
+	}
+
+	if err := f.finish(modulePath, opts.DryRun); err != nil {
+		return nil, err
+	}
+	return f.result, nil
+}
+
+type fixer struct {
+	mod    *parsedModule
+	info   *schema.ResourceInfo
+	pack   *generators.TemplatePack
+	result *FixResult
+	files  map[string]*fixedFile
+}
+
+type fixedFile struct {
+	before []byte // nil when the file is new
+	after  []byte
+}
+
+func (f *fixer) applied(ruleID, file, format string, args ...any) {
+	f.result.Applied = append(f.result.Applied, FixAction{RuleID: ruleID, File: file, Description: fmt.Sprintf(format, args...)})
+}
+
+func (f *fixer) skipped(ruleID, file, format string, args ...any) {
+	f.result.Skipped = append(f.result.Skipped, FixAction{RuleID: ruleID, File: file, Description: fmt.Sprintf(format, args...)})
+}
+
+// content returns the current (possibly already fixed) content of a module file.
+func (f *fixer) content(name string) ([]byte, bool) {
+	if ff, ok := f.files[name]; ok {
+		return ff.after, true
+	}
+	src, err := os.ReadFile(filepath.Join(f.mod.Dir, name))
+	if err != nil {
+		return nil, false
+	}
+	return src, true
+}
+
+func (f *fixer) write(name string, content []byte) {
+	ff, ok := f.files[name]
+	if !ok {
+		ff = &fixedFile{}
+		if src, err := os.ReadFile(filepath.Join(f.mod.Dir, name)); err == nil {
+			ff.before = src
+		}
+		f.files[name] = ff
+	}
+	ff.after = content
+}
+
+// ---------------------------------------------------------------------------
+
+func (f *fixer) staticFiles() error {
+	names := make([]string, 0, len(staticFiles))
+	for name := range staticFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !fileExists(filepath.Join(f.mod.Dir, name)) {
+			f.write(name, []byte(staticFiles[name]))
+			f.applied("DPAAS001", name, "created %s from the DPaaS templates", name)
+		}
+	}
+	if !fileExists(filepath.Join(f.mod.Dir, "CHANGELOG.md")) {
+		changelog, err := generators.GenerateChangelog(f.info, f.pack)
+		if err != nil {
+			return err
+		}
+		f.write("CHANGELOG.md", []byte(changelog))
+		f.applied("DPAAS001", "CHANGELOG.md", "created CHANGELOG.md")
+	}
+	for _, name := range []string{"main.tf", "locals.tf", "outputs.tf", "README.md"} {
+		if !fileExists(filepath.Join(f.mod.Dir, name)) {
+			f.skipped("DPAAS001", name, "%s is missing; it has module-specific content and must be written by hand", name)
+		}
+	}
+	return nil
+}
+
+// variablesTf adds the null-label section and the create_ flag.
+func (f *fixer) variablesTf() {
+	const file = "variables.tf"
+	src, _ := f.content(file)
+	createVar := "create_" + f.info.ShortName
+	_, hasCreate := f.mod.Variables[createVar]
+
+	hasStart := f.mod.hasComment(file, "Start of null-label Variables")
+	hasEnd := f.mod.hasComment(file, "End of null-label Variables")
+	clash := ""
+	for _, name := range nullLabelVariables() {
+		if _, ok := f.mod.Variables[name]; ok {
+			clash = name
+			break
+		}
+	}
+
+	var out bytes.Buffer
+	switch {
+	case hasStart && hasEnd:
+		out.Write(src)
+	case !hasStart && !hasEnd && clash == "":
+		out.WriteString(templates.NullLabelVarsPartA)
+		out.WriteString("\n")
+		if !hasCreate {
+			out.WriteString(generators.GenerateCreateFlagVariable(f.info))
+			f.applied("DPAAS008", file, "declared variable %q", createVar)
+			hasCreate = true
+		}
+		out.WriteString(templates.NullLabelVarsPartB)
+		out.WriteString("\n")
+		if len(src) > 0 {
+			out.WriteString("\n")
+			out.Write(src)
+		}
+		f.applied("DPAAS007", file, "added the null-label variable section")
+	case clash != "":
+		out.Write(src)
+		f.skipped("DPAAS007", file, "null-label markers are missing but variable %q is already declared; restore the section by hand", clash)
+	default:
+		out.Write(src)
+		f.skipped("DPAAS007", file, "null-label section is incomplete; restore it from the templates by hand")
+	}
+
+	if !hasCreate {
+		appendBlock(&out, generators.GenerateCreateFlagVariable(f.info))
+		f.applied("DPAAS008", file, "declared variable %q", createVar)
+	}
+
+	if !bytes.Equal(out.Bytes(), src) {
+		f.write(file, out.Bytes())
+	}
+}
+
+// mainTf wires local.tags and the uncovered top-level arguments into the
+// wrapped resource, declaring the variables they need.
+func (f *fixer) mainTf() error {
+	blk := f.mod.wrappedResource(f.info.ResourceType)
+	if blk == nil {
+		f.skipped("DPAAS004", "main.tf", "no %s resource found; nothing to wire", f.info.ResourceType)
+		return nil
+	}
+	file := blk.DefRange().Filename
+
+	src, _ := f.content(file)
+	wf, diags := hclwrite.ParseConfig(src, file, hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("parse %s: %s", file, diags.Error())
+	}
+	var body *hclwrite.Body
+	for _, b := range wf.Body().Blocks() {
+		labels := b.Labels()
+		if b.Type() == "resource" && len(labels) == 2 && labels[0] == blk.Labels[0] && labels[1] == blk.Labels[1] {
+			body = b.Body()
+			break
+		}
+	}
+	if body == nil {
+		return fmt.Errorf("resource %s.%s not found in %s", blk.Labels[0], blk.Labels[1], file)
+	}
+
+	changed := false
+	varsSrc, _ := f.content("variables.tf")
+	var vars bytes.Buffer
+	vars.Write(varsSrc)
+	declared := map[string]bool{}
+	for name := range f.mod.Variables {
+		declared[name] = true
+	}
+
+	// tags
+	if f.hasSchemaAttr("tags") {
+		if tags, ok := blk.Body.Attributes["tags"]; !ok {
+			if err := setRawAttribute(body, "tags", "local.tags"); err != nil {
+				return err
+			}
+			changed = true
+			f.applied("DPAAS006", file, "set tags = local.tags on %s.%s", blk.Labels[0], blk.Labels[1])
+		} else if !referencesTraversal(tags.Expr, "local", "tags") {
+			f.skipped("DPAAS006", file, "tags is set to another expression; merge local.tags into it by hand")
+		}
+	}
+
+	// coverage
+	cr := checkCoverage(f.mod, f.info)
+	attrs := map[string]schema.ParsedAttribute{}
+	for _, a := range f.info.Attributes {
+		attrs[a.Name] = a
+	}
+	blocks := map[string]schema.ParsedBlock{}
+	for _, b := range f.info.Blocks {
+		blocks[b.Name] = b
+	}
+
+	for _, e := range cr.Entries {
+		if e.Wired {
+			continue
+		}
+		if e.Depth > 0 {
+			// when the parent block is wired, the right iterator reference for
+			// the nested argument cannot be inferred reliably
+			if isWiredPath(cr, parentPath(e.Path)) {
+				f.skipped("DPAAS014", file, "nested %s %s is not wired; add it to the enclosing block by hand", e.Kind, e.Path)
+			}
+			continue
+		}
+
+		switch e.Kind {
+		case "attribute":
+			a := attrs[e.Path]
+			if a.Name == "tags" {
+				continue
+			}
+			varName := generators.VariableName(a.Name, f.info.ShortName)
+			if set, ok := blk.Body.Attributes[a.Name]; ok {
+				if f.mod.readsUndeclaredVariable(set.Expr) {
+					f.skipped("DPAAS014", file, "%s references an undeclared variable; declare it by hand", a.Name)
+				} else {
+					f.skipped("DPAAS014", file, "%s is set to an expression that does not come from a variable", a.Name)
+				}
+				continue
+			}
+			if declared[varName] {
+				f.skipped("DPAAS014", file, "%s is not set but variable %q already exists; wire it by hand", a.Name, varName)
+				continue
+			}
+			variable, err := generators.GenerateAttributeVariable(a, f.info)
+			if err != nil {
+				return err
+			}
+			if err := setRawAttribute(body, a.Name, generators.AttributeValueExpr(a, f.info)); err != nil {
+				return err
+			}
+			appendBlock(&vars, variable)
+			declared[varName] = true
+			changed = true
+			f.applied("DPAAS014", file, "wired %s to variable %q", a.Name, varName)
+
+		case "block":
+			b := blocks[e.Path]
+			if existing, _ := blockByName(blk.Body, b.Name); existing != nil {
+				f.skipped("DPAAS014", file, "block %s is present but not driven by a variable", b.Name)
+				continue
+			}
+			if declared[b.Name] {
+				f.skipped("DPAAS014", file, "block %s is not set but variable %q already exists; wire it by hand", b.Name, b.Name)
+				continue
+			}
+			dynamic, err := generators.GenerateDynamicBlock(b)
+			if err != nil {
+				return err
+			}
+			variable, err := generators.GenerateBlockVariable(b)
+			if err != nil {
+				return err
+			}
+			if err := appendRawBlock(body, dynamic); err != nil {
+				return err
+			}
+			appendBlock(&vars, variable)
+			declared[b.Name] = true
+			changed = true
+			f.applied("DPAAS014", file, "added dynamic block %s driven by variable %q", b.Name, b.Name)
+		}
+	}
+
+	if changed {
+		f.write(file, formatLike(src, wf.Bytes()))
+	}
+	if !bytes.Equal(vars.Bytes(), varsSrc) {
+		f.write("variables.tf", formatLike(varsSrc, vars.Bytes()))
+	}
+	return nil
+}
+
+func (f *fixer) hasSchemaAttr(name string) bool {
+	for _, a := range f.info.Attributes {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// finish renders the diff and, unless dryRun, writes the changed files.
+func (f *fixer) finish(modulePath string, dryRun bool) error {
+	diff, err := writeChanges(modulePath, f.files, dryRun)
+	if err != nil {
+		return err
+	}
+	f.result.Diff = diff
+	return nil
+}
+
+// writeChanges renders the unified diff of the changed files of dir and,
+// unless dryRun, writes them.
+func writeChanges(dir string, files map[string]*fixedFile, dryRun bool) (string, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diff strings.Builder
+	for _, name := range names {
+		ff := files[name]
+		from := "a/" + name
+		if ff.before == nil {
+			from = "/dev/null"
+		}
+		d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(ff.before)),
+			B:        difflib.SplitLines(string(ff.after)),
+			FromFile: from,
+			ToFile:   "b/" + name,
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("diff %s: %w", name, err)
+		}
+		diff.WriteString(d)
+
+		if !dryRun {
+			if err := os.WriteFile(filepath.Join(dir, name), ff.after, 0644); err != nil {
+				return "", fmt.Errorf("write %s: %w", name, err)
+			}
+		}
+	}
+	return diff.String(), nil
+}
+
+// ---------------------------------------------------------------------------
+
+// nullLabelVariables returns the variables declared by the null-label template section.
+func nullLabelVariables() []string {
+	src := templates.NullLabelVarsPartA + "\n" + templates.NullLabelVarsPartB
+	f, _ := hclsyntax.ParseConfig([]byte(src), "null_label_vars.tf", hcl.InitialPos)
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, blk := range body.Blocks {
+		if blk.Type == "variable" && len(blk.Labels) == 1 {
+			names = append(names, blk.Labels[0])
+		}
+	}
+	return names
+}
+
+// setRawAttribute sets name = expr in body, where expr is HCL source.
+func setRawAttribute(body *hclwrite.Body, name, expr string) error {
+	snippet, diags := hclwrite.ParseConfig([]byte(name+" = "+expr+"\n"), "fix.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("render %s: %s", name, diags.Error())
+	}
+	body.SetAttributeRaw(name, snippet.Body().GetAttribute(name).Expr().BuildTokens(nil))
+	return nil
+}
+
+// appendRawBlock appends the block written in src to body.
+func appendRawBlock(body *hclwrite.Body, src string) error {
+	snippet, diags := hclwrite.ParseConfig([]byte(src), "fix.tf", hcl.InitialPos)
+	if diags.HasErrors() || len(snippet.Body().Blocks()) != 1 {
+		return fmt.Errorf("render block: %s", diags.Error())
+	}
+	body.AppendNewline()
+	body.AppendBlock(snippet.Body().Blocks()[0])
+	return nil
+}
+
+// appendBlock appends a top-level block to file content, separated by a blank line.
+func appendBlock(buf *bytes.Buffer, block string) {
+	if buf.Len() > 0 {
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n\n")) {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString(strings.TrimRight(block, "\n") + "\n")
+}
+
+// formatLike canonically formats out only when the original was already
+// canonically formatted, so hand-formatted files keep their layout.
+func formatLike(original, out []byte) []byte {
+	if len(original) == 0 || bytes.Equal(hclwrite.Format(original), original) {
+		return hclwrite.Format(out)
+	}
+	return out
+}
+
+func (m *parsedModule) readsUndeclaredVariable(expr hcl.Expression) bool {
+	for _, tr := range expr.Variables() {
+		if root, name := traversalHead(tr); root == "var" {
+			if _, ok := m.Variables[name]; !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parentPath(path string) string {
+	return path[:strings.LastIndex(path, ".")]
+}
+
+func isWiredPath(cr *CoverageReport, path string) bool {
+	for _, e := range cr.Entries {
+		if e.Path == path {
+			return e.Wired
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixModule_HandWrittenModule(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := t.TempDir()
+	main := `resource "azurerm_windows_web_app" "this" {
+  count = local.enabled ? 1 : 0
+
+  name                = var.windows_web_app_name
+  location            = "westeurope"
+  resource_group_name = var.resource_group_name
+}
+`
+	vars := `variable "windows_web_app_name" {
+  type = string
+}
+
+variable "resource_group_name" {
+  type = string
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(main), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(vars), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "locals.tf"), []byte("locals {\n  tags = var.tags\n}\n"), 0644))
+
+	res, err := FixModule(dir, info, FixOptions{})
+	require.NoError(t, err)
+
+	applied := map[string]bool{}
+	for _, a := range res.Applied {
+		applied[a.Description] = true
+	}
+	assert.True(t, applied["created context.tf from the DPaaS templates"])
+	assert.True(t, applied["added the null-label variable section"])
+	assert.True(t, applied[`declared variable "create_windows_web_app"`])
+	assert.True(t, applied["set tags = local.tags on azurerm_windows_web_app.this"])
+	assert.True(t, applied[`wired https_only to variable "https_only"`])
+	assert.True(t, applied[`added dynamic block site_config driven by variable "site_config"`])
+
+	var skipped []string
+	for _, s := range res.Skipped {
+		skipped = append(skipped, s.Description)
+	}
+	assert.Contains(t, skipped, "location is set to an expression that does not come from a variable")
+	assert.Contains(t, skipped, "README.md is missing; it has module-specific content and must be written by hand")
+
+	assert.Contains(t, res.Diff, "--- /dev/null\n+++ b/context.tf")
+	assert.Contains(t, res.Diff, "+  tags")
+	assert.Contains(t, res.Diff, `+  dynamic "connection_string" {`)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	for _, name := range []string{
+		"variables.tf: Start of null-label marker",
+		"variables.tf: create_ flag present",
+		"main.tf references local.tags",
+		"All variable references are declared",
+		"HCL parses without errors",
+	} {
+		assert.True(t, findCheck(t, r, name).Passed, name)
+	}
+	assert.Equal(t, []string{"location"}, r.CoverageReport.MissingAttrs)
+	assert.Empty(t, r.CoverageReport.MissingBlocks)
+
+	// the hardcoded location is left alone
+	got, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `location            = "westeurope"`)
+}
+
+func TestFixModule_DryRunAndNoop(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	res, err := FixModule(dir, info, FixOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, res.Applied, "a generated module needs no fixes")
+	assert.Empty(t, res.Diff)
+
+	mainPath := filepath.Join(dir, "main.tf")
+	raw, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(mainPath, []byte(regexp.MustCompile(`(?m)^  tags +=.*\n`).ReplaceAllString(string(raw), "")), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, ".gitignore")))
+
+	res, err = FixModule(dir, info, FixOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, res.Applied, 2)
+	assert.Contains(t, res.Diff, "+++ b/.gitignore")
+	assert.Contains(t, res.Diff, "+  tags")
+	assert.NoFileExists(t, filepath.Join(dir, ".gitignore"))
+}
+
+func TestFixModule_ChangelogFromPack(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	require.NoError(t, os.Remove(filepath.Join(dir, "CHANGELOG.md")))
+
+	res, err := FixModule(dir, info, FixOptions{Pack: contosoPack(t)})
+	require.NoError(t, err)
+	require.Len(t, res.Applied, 1)
+	assert.Equal(t, "created CHANGELOG.md", res.Applied[0].Description)
+
+	changelog, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(changelog), "# Contoso changelog")
+}
+
+func TestFixModule_RefusesSyntaxErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("resource \"x\" \"this\" {\n"), 0644))
+	_, err := FixModule(dir, schematest.WindowsWebApp(), FixOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+package validation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Output formats a validation report can be rendered in.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+	FormatJUnit = "junit"
+)
+
+// OutputFormats lists every supported format, text first.
+var OutputFormats = []string{FormatText, FormatJSON, FormatSARIF, FormatJUnit}
+
+// JSON renders the report as indented JSON.
+func (r *ValidationReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// ---------------------------------------------------------------------------
+// SARIF 2.1.0
+// ---------------------------------------------------------------------------
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "dpaas-validate"
+
+	// sarifModuleRoot is the base of every artifact URI: locations are
+	// module-relative, and the run resolves the module against %SRCROOT%.
+	sarifModuleRoot = "MODULEROOT"
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifact `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult            `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string            `json:"id"`
+	ShortDescription sarifMessage      `json:"shortDescription"`
+	Help             *sarifMessage     `json:"help,omitempty"`
+	DefaultConfig    sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI       string `json:"uri,omitempty"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+	return "error"
+}
+
+// SARIF renders failing checks as a SARIF 2.1.0 log. File locations are
+// module-relative, based on MODULEROOT, which originalUriBaseIds resolves to
+// uriBase under %SRCROOT%: the module's path in the repository, e.g.
+// "modules/app". With uriBase "" the module is the source root.
+func (r *ValidationReport) SARIF(uriBase string) ([]byte, error) {
+	driver := sarifDriver{Name: toolName, Rules: []sarifRule{}}
+	for _, rule := range r.Rules {
+		sr := sarifRule{
+			ID:               rule.ID,
+			ShortDescription: sarifMessage{Text: rule.Description},
+			DefaultConfig:    sarifRuleDefaults{Level: sarifLevel(rule.Severity)},
+		}
+		if rule.Remediation != "" {
+			sr.Help = &sarifMessage{Text: rule.Remediation}
+		}
+		driver.Rules = append(driver.Rules, sr)
+	}
+
+	results := []sarifResult{}
+	for _, c := range r.Checks {
+		if c.Passed {
+			continue
+		}
+		res := sarifResult{
+			RuleID:  c.RuleID,
+			Level:   sarifLevel(c.Severity),
+			Message: sarifMessage{Text: checkText(c)},
+		}
+		if c.File != "" {
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(c.File), URIBaseID: sarifModuleRoot}}
+			if c.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: c.Line}
+			}
+			res.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		}
+		results = append(results, res)
+	}
+
+	root := sarifArtifact{URIBaseID: sarifSourceRoot}
+	if base := strings.Trim(filepath.ToSlash(filepath.Clean(uriBase)), "/"); uriBase != "" && base != "." {
+		root.URI = base + "/"
+	}
+	return json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:               sarifTool{Driver: driver},
+			OriginalURIBaseIDs: map[string]sarifArtifact{sarifModuleRoot: root},
+			Results:            results,
+		}},
+	}, "", "  ")
+}
+
+// ---------------------------------------------------------------------------
+// JUnit XML
+// ---------------------------------------------------------------------------
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit renders every check as a test case in a single suite. Only failing
+// error-severity checks are failures; failing warnings and info findings are
+// recorded as system-out so they show up without breaking the build.
+func (r *ValidationReport) JUnit(suiteName string) ([]byte, error) {
+	suite := junitSuite{Name: suiteName}
+	for _, c := range r.Checks {
+		tc := junitCase{Name: c.Name, ClassName: c.RuleID}
+		if !c.Passed {
+			if c.Severity == SeverityError || c.Severity == "" {
+				tc.Failure = &junitFailure{Message: c.Message, Type: c.RuleID, Text: checkText(c)}
+				suite.Failures++
+			} else {
+				tc.SystemOut = fmt.Sprintf("%s: %s", strings.ToUpper(string(c.Severity)), checkText(c))
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	out, err := xml.MarshalIndent(junitSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// checkText is the one-line description of a finding used by SARIF and JUnit.
+func checkText(c CheckResult) string {
+	text := c.Name
+	if c.Message != "" {
+		text += ": " + c.Message
+	}
+	if c.File != "" {
+		loc := c.File
+		if c.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", c.File, c.Line)
+		}
+		text += " (" + loc + ")"
+	}
+	return text
+}
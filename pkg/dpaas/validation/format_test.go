@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatTestReport() *ValidationReport {
+	r := &ValidationReport{
+		Rules: []RuleSummary{
+			{ID: "DPAAS005", Severity: SeverityError, Description: "count pattern", Remediation: "use local.enabled"},
+			{ID: "DPAAS015", Severity: SeverityWarning, Description: "computed outputs"},
+			{ID: "TF002", Severity: SeverityError, Description: "validate"},
+		},
+	}
+	r.add(CheckResult{Name: "main.tf uses count pattern", RuleID: "DPAAS005", Severity: SeverityError, Message: "use local.enabled"})
+	r.add(CheckResult{Name: "Computed attributes exported as outputs", RuleID: "DPAAS015", Severity: SeverityWarning, Message: "Missing outputs: default_hostname"})
+	r.add(CheckResult{Name: "terraform validate (tests/default): Unsupported argument", RuleID: "TF002", Severity: SeverityError, File: "main.tf", Line: 7})
+	r.add(CheckResult{Name: "HCL parses without errors", RuleID: "DPAAS003", Severity: SeverityError, Passed: true})
+	return r
+}
+
+func TestFormat_JSON(t *testing.T) {
+	out, err := formatTestReport().JSON()
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, false, decoded["passed"])
+	assert.EqualValues(t, 2, decoded["errors"])
+	checks := decoded["checks"].([]any)
+	require.Len(t, checks, 4)
+	tf := checks[2].(map[string]any)
+	assert.Equal(t, "TF002", tf["rule_id"])
+	assert.Equal(t, "main.tf", tf["file"])
+	assert.EqualValues(t, 7, tf["line"])
+}
+
+func TestFormat_SARIF(t *testing.T) {
+	out, err := formatTestReport().SARIF("modules/app")
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(out, &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 3)
+	assert.Equal(t, "use local.enabled", run.Tool.Driver.Rules[0].Help.Text)
+
+	require.Len(t, run.Results, 3, "passing checks are not reported")
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Empty(t, run.Results[0].Locations)
+
+	loc := run.Results[2].Locations[0].PhysicalLocation
+	assert.Equal(t, sarifArtifact{URI: "main.tf", URIBaseID: "MODULEROOT"}, loc.ArtifactLocation, "locations are module-relative")
+	assert.Equal(t, 7, loc.Region.StartLine)
+	assert.Equal(t, map[string]sarifArtifact{"MODULEROOT": {URI: "modules/app/", URIBaseID: "%SRCROOT%"}}, run.OriginalURIBaseIDs)
+
+	out, err = formatTestReport().SARIF("")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(out, &log))
+	assert.Equal(t, map[string]sarifArtifact{"MODULEROOT": {URIBaseID: "%SRCROOT%"}}, log.Runs[0].OriginalURIBaseIDs, "the module is the source root")
+	assert.Equal(t, "main.tf", log.Runs[0].Results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestFormat_JUnit(t *testing.T) {
+	out, err := formatTestReport().JUnit("expn-tf-azure-app")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "<?xml"))
+
+	var suites junitSuites
+	require.NoError(t, xml.Unmarshal(out, &suites))
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 2, suites.Failures, "warnings do not count as failures")
+	require.Len(t, suites.Suites, 1)
+
+	cases := suites.Suites[0].Cases
+	assert.NotNil(t, cases[0].Failure)
+	assert.Nil(t, cases[1].Failure)
+	assert.Contains(t, cases[1].SystemOut, "WARNING")
+	assert.Contains(t, cases[2].Failure.Text, "main.tf:7")
+	assert.Nil(t, cases[3].Failure)
+}
+
+func TestFormat_ReportListsEnabledRules(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{Rules: &RuleConfig{Disable: []string{"DPAAS015"}}})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, rule := range r.Rules {
+		ids = append(ids, rule.ID)
+	}
+	assert.Contains(t, ids, "DPAAS001")
+	assert.NotContains(t, ids, "DPAAS015")
+}
@@ -0,0 +1,285 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// parsedModule is the syntax-level view of every .tf file in one module directory.
+// Checks work on real blocks and expressions, so commented-out code is never seen.
+type parsedModule struct {
+	Dir       string
+	Files     map[string]*hcl.File // file name → parsed file
+	Diags     hcl.Diagnostics
+	Variables map[string]*hclsyntax.Block
+	Locals    map[string]*hclsyntax.Attribute
+	Outputs   map[string]*hclsyntax.Block
+	Modules   map[string]*hclsyntax.Block
+	Resources []*hclsyntax.Block // every `resource "type" "name"` block, in file order
+}
+
+// parseModule parses the top-level .tf files of modulePath. Syntax errors are
+// collected in Diags rather than returned, so the remaining files still get checked.
+func parseModule(modulePath string) (*parsedModule, error) {
+	paths, err := filepath.Glob(filepath.Join(modulePath, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	m := newParsedModule(modulePath)
+	for _, p := range paths {
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", p, err)
+		}
+		m.parse(filepath.Base(p), src)
+	}
+	return m, nil
+}
+
+// parseGenerated parses the top-level .tf files of a generated module the
+// same way, without writing it out.
+func parseGenerated(module generators.GeneratedModule) *parsedModule {
+	m := newParsedModule("")
+	for _, name := range sortedKeys(module) {
+		if !strings.Contains(name, "/") && strings.HasSuffix(name, ".tf") {
+			m.parse(name, []byte(module[name]))
+		}
+	}
+	return m
+}
+
+func newParsedModule(dir string) *parsedModule {
+	return &parsedModule{
+		Dir:       dir,
+		Files:     map[string]*hcl.File{},
+		Variables: map[string]*hclsyntax.Block{},
+		Locals:    map[string]*hclsyntax.Attribute{},
+		Outputs:   map[string]*hclsyntax.Block{},
+		Modules:   map[string]*hclsyntax.Block{},
+	}
+}
+
+func (m *parsedModule) parse(name string, src []byte) {
+	f, diags := hclsyntax.ParseConfig(src, name, hcl.InitialPos)
+	m.Diags = append(m.Diags, diags...)
+	if f == nil {
+		return
+	}
+	m.Files[name] = f
+	m.index(f)
+}
+
+func (m *parsedModule) index(f *hcl.File) {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return
+	}
+	for _, blk := range body.Blocks {
+		switch blk.Type {
+		case "variable":
+			if len(blk.Labels) == 1 {
+				m.Variables[blk.Labels[0]] = blk
+			}
+		case "output":
+			if len(blk.Labels) == 1 {
+				m.Outputs[blk.Labels[0]] = blk
+			}
+		case "module":
+			if len(blk.Labels) == 1 {
+				m.Modules[blk.Labels[0]] = blk
+			}
+		case "resource":
+			if len(blk.Labels) == 2 {
+				m.Resources = append(m.Resources, blk)
+			}
+		case "locals":
+			for name, attr := range blk.Body.Attributes {
+				m.Locals[name] = attr
+			}
+		}
+	}
+}
+
+// resource returns the resource block with the given type and name, or nil.
+func (m *parsedModule) resource(resourceType, name string) *hclsyntax.Block {
+	for _, blk := range m.Resources {
+		if blk.Labels[0] == resourceType && blk.Labels[1] == name {
+			return blk
+		}
+	}
+	return nil
+}
+
+// wrappedResource returns the block of resourceType the module wraps: the one
+// named "this" when present, otherwise the first declared.
+func (m *parsedModule) wrappedResource(resourceType string) *hclsyntax.Block {
+	if blk := m.resource(resourceType, "this"); blk != nil {
+		return blk
+	}
+	for _, blk := range m.Resources {
+		if blk.Labels[0] == resourceType {
+			return blk
+		}
+	}
+	return nil
+}
+
+// resourceTypes returns the distinct managed resource types, those declared
+// as "this" first and the rest in file order.
+func (m *parsedModule) resourceTypes() []string {
+	seen := map[string]bool{}
+	var primary, rest []string
+	for _, blk := range m.Resources {
+		if blk.Labels[1] == "this" && !seen[blk.Labels[0]] {
+			seen[blk.Labels[0]] = true
+			primary = append(primary, blk.Labels[0])
+		}
+	}
+	for _, blk := range m.Resources {
+		if !seen[blk.Labels[0]] {
+			seen[blk.Labels[0]] = true
+			rest = append(rest, blk.Labels[0])
+		}
+	}
+	return append(primary, rest...)
+}
+
+// hasComment reports whether fileName contains a comment token mentioning text.
+// Markers such as the null-label banners are comments, so they are matched
+// against comment tokens only — never against string literals or code.
+func (m *parsedModule) hasComment(fileName, text string) bool {
+	src, err := os.ReadFile(filepath.Join(m.Dir, fileName))
+	if err != nil {
+		return false
+	}
+	tokens, _ := hclsyntax.LexConfig(src, fileName, hcl.InitialPos)
+	for _, tok := range tokens {
+		if tok.Type == hclsyntax.TokenComment && strings.Contains(string(tok.Bytes), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// Expression helpers
+// ---------------------------------------------------------------------------
+
+// traversalHead splits a traversal such as var.foo.bar into ("var", "foo").
+func traversalHead(tr hcl.Traversal) (string, string) {
+	if len(tr) < 2 {
+		return tr.RootName(), ""
+	}
+	if step, ok := tr[1].(hcl.TraverseAttr); ok {
+		return tr.RootName(), step.Name
+	}
+	return tr.RootName(), ""
+}
+
+// referencesTraversal reports whether expr references root.attr anywhere.
+func referencesTraversal(expr hcl.Expression, root, attr string) bool {
+	if expr == nil {
+		return false
+	}
+	for _, tr := range expr.Variables() {
+		r, a := traversalHead(tr)
+		if r == root && a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// isCountPattern reports whether expr is exactly `local.enabled ? 1 : 0`.
+func isCountPattern(expr hcl.Expression) bool {
+	cond, ok := expr.(*hclsyntax.ConditionalExpr)
+	if !ok {
+		return false
+	}
+	trav, ok := cond.Condition.(*hclsyntax.ScopeTraversalExpr)
+	if !ok {
+		return false
+	}
+	root, attr := traversalHead(trav.Traversal)
+	if root != "local" || attr != "enabled" || len(trav.Traversal) != 2 {
+		return false
+	}
+	return isNumberLiteral(cond.TrueResult, 1) && isNumberLiteral(cond.FalseResult, 0)
+}
+
+func isNumberLiteral(expr hcl.Expression, want int64) bool {
+	lit, ok := expr.(*hclsyntax.LiteralValueExpr)
+	if !ok || lit.Val.Type() != cty.Number || !lit.Val.IsKnown() {
+		return false
+	}
+	n, acc := lit.Val.AsBigFloat().Int64()
+	return acc == 0 && n == want
+}
+
+// objectHasKey reports whether expr is an object constructor with a literal key.
+func objectHasKey(expr hcl.Expression, key string) bool {
+	obj, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return false
+	}
+	for _, item := range obj.Items {
+		v, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || !v.IsKnown() || v.Type() != cty.String {
+			continue
+		}
+		if v.AsString() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// blockByName returns the first nested block in body that is either a static
+// block of the given type or a `dynamic "<name>"` block generating it.
+func blockByName(body *hclsyntax.Body, name string) (*hclsyntax.Block, bool) {
+	for _, blk := range body.Blocks {
+		if blk.Type == name {
+			return blk, false
+		}
+		if blk.Type == "dynamic" && len(blk.Labels) == 1 && blk.Labels[0] == name {
+			return blk, true
+		}
+	}
+	return nil, false
+}
+
+// diagSummary renders error diagnostics as "file:line: summary" lines.
+func diagSummary(diags hcl.Diagnostics) string {
+	var parts []string
+	for _, d := range diags {
+		if d.Severity != hcl.DiagError {
+			continue
+		}
+		loc := ""
+		if d.Subject != nil {
+			loc = fmt.Sprintf("%s:%d: ", d.Subject.Filename, d.Subject.Start.Line)
+		}
+		parts = append(parts, loc+d.Summary)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,213 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+)
+
+// ModuleInterface is the interface a registry module version publishes: its
+// inputs, outputs and resources as the registry reports them.
+type ModuleInterface struct {
+	Name      string
+	Version   string
+	Inputs    []InterfaceInput
+	Outputs   []string
+	Resources []string // resource addresses, e.g. "azurerm_storage_account.this"
+}
+
+// InterfaceInput is one published input variable.
+type InterfaceInput struct {
+	Name        string
+	Type        string // type constraint as written, e.g. "map(string)"
+	Description string
+	Required    bool
+}
+
+// InterfaceDiff is one difference between the published interface and the
+// generated module.
+type InterfaceDiff struct {
+	Name      string `json:"name"`
+	Published string `json:"published,omitempty"`
+	Generated string `json:"generated,omitempty"`
+	Rule      string `json:"rule,omitempty"` // DPaaS rule a convention violation breaks
+	Message   string `json:"message"`
+}
+
+// InterfaceReport compares a published module version with the module the
+// generator produces for the same resource type.
+type InterfaceReport struct {
+	ResourceType   string          `json:"resource_type"`
+	Module         string          `json:"module"`
+	Version        string          `json:"version"`
+	Matched        int             `json:"matched"` // generated inputs published with the same type
+	Generated      int             `json:"generated"`
+	MissingInputs  []InterfaceDiff `json:"missing_inputs,omitempty"`
+	TypeMismatches []InterfaceDiff `json:"type_mismatches,omitempty"`
+	MissingOutputs []InterfaceDiff `json:"missing_outputs,omitempty"`
+	ExtraInputs    []string        `json:"extra_inputs,omitempty"` // published inputs the generator does not produce
+	Conventions    []InterfaceDiff `json:"conventions,omitempty"`
+}
+
+// Consistent reports whether the published version matches the generated
+// module and follows the conventions.
+func (r *InterfaceReport) Consistent() bool {
+	return len(r.MissingInputs)+len(r.TypeMismatches)+len(r.MissingOutputs)+len(r.Conventions) == 0
+}
+
+// CompareInterface generates the DPaaS module for info with the template
+// pack and compares its inputs and outputs with the published interface:
+// generated inputs the version lacks, inputs whose type or requiredness
+// differs, missing outputs, and the DPaaS conventions visible in a
+// published interface. Extra published inputs are listed but not judged.
+func CompareInterface(published *ModuleInterface, info *schema.ResourceInfo, pack *generators.TemplatePack) (*InterfaceReport, error) {
+	if pack == nil {
+		var err error
+		if pack, err = generators.LoadTemplatePack(""); err != nil {
+			return nil, err
+		}
+	}
+	module, err := generators.GenerateModuleWithOptions(info, generators.Options{Pack: pack})
+	if err != nil {
+		return nil, err
+	}
+	mod := parseGenerated(module)
+	if len(mod.Diags) > 0 {
+		return nil, fmt.Errorf("generated module does not parse: %s", diagSummary(mod.Diags))
+	}
+
+	conventionInputs := map[string]string{
+		"create_" + info.ShortName: "DPAAS008",
+		info.ShortName + "_name":   "DPAAS009",
+		"context":                  "DPAAS007",
+		"tags":                     "DPAAS006",
+	}
+	report := &InterfaceReport{
+		ResourceType: info.ResourceType,
+		Module:       published.Name,
+		Version:      published.Version,
+		Generated:    len(mod.Variables),
+	}
+
+	// the schema arguments each generated variable feeds
+	feeds := map[string][]string{}
+	for _, e := range checkCoverage(mod, info).Entries {
+		if e.Wired && e.Variable != "" && e.Depth == 0 {
+			root, _, _ := strings.Cut(e.Variable, ".")
+			feeds[root] = append(feeds[root], e.Path)
+		}
+	}
+
+	inputs := map[string]InterfaceInput{}
+	for _, in := range published.Inputs {
+		inputs[in.Name] = in
+	}
+	for _, name := range sortedKeys(mod.Variables) {
+		decl := mod.Variables[name]
+		typ := mod.exprSource(decl, "type")
+		_, hasDefault := decl.Body.Attributes["default"]
+		required := !hasDefault
+
+		in, ok := inputs[name]
+		if !ok {
+			msg := "not published"
+			if args := feeds[name]; len(args) > 0 {
+				msg = fmt.Sprintf("not published; the module cannot set %s", strings.Join(args, ", "))
+			}
+			report.MissingInputs = append(report.MissingInputs, InterfaceDiff{Name: name, Generated: typ, Rule: conventionInputs[name], Message: msg})
+			continue
+		}
+		switch {
+		case typ != "" && normalizeType(in.Type) != normalizeType(typ):
+			report.TypeMismatches = append(report.TypeMismatches, InterfaceDiff{Name: name, Published: in.Type, Generated: typ, Message: "type differs"})
+		case in.Required != required:
+			report.TypeMismatches = append(report.TypeMismatches, InterfaceDiff{Name: name, Published: requiredness(in.Required), Generated: requiredness(required), Message: "requiredness differs"})
+		default:
+			report.Matched++
+		}
+	}
+	for _, in := range published.Inputs {
+		if _, ok := mod.Variables[in.Name]; !ok {
+			report.ExtraInputs = append(report.ExtraInputs, in.Name)
+		}
+	}
+
+	outputs := map[string]bool{}
+	for _, name := range published.Outputs {
+		outputs[name] = true
+	}
+	for _, name := range sortedKeys(mod.Outputs) {
+		if !outputs[name] {
+			report.MissingOutputs = append(report.MissingOutputs, InterfaceDiff{Name: name, Rule: "DPAAS015", Message: "not published"})
+		}
+	}
+
+	report.Conventions = publishedConventions(published, info, pack)
+	return report, nil
+}
+
+// publishedConventions checks the DPaaS conventions a published interface
+// shows beyond its inputs: the module name, the resource named "this" and
+// input descriptions. Missing convention inputs, such as the create_ flag,
+// are reported with the missing inputs.
+func publishedConventions(published *ModuleInterface, info *schema.ResourceInfo, pack *generators.TemplatePack) []InterfaceDiff {
+	var out []InterfaceDiff
+	add := func(rule, name, msg string) {
+		out = append(out, InterfaceDiff{Name: name, Rule: rule, Message: msg})
+	}
+
+	if want := pack.ModuleName(info.ShortName); published.Name != "" && published.Name != want {
+		add("", published.Name, fmt.Sprintf("module name does not follow the %s convention %q", pack.Name, want))
+	}
+	if len(published.Resources) > 0 {
+		wrapped := false
+		for _, addr := range published.Resources {
+			if addr == info.ResourceType+".this" {
+				wrapped = true
+			}
+		}
+		if !wrapped {
+			add("DPAAS004", info.ResourceType, fmt.Sprintf("no %s resource named \"this\" among %s", info.ResourceType, strings.Join(published.Resources, ", ")))
+		}
+	}
+	for _, in := range published.Inputs {
+		if strings.TrimSpace(in.Description) == "" {
+			add("DPAAS016", in.Name, "input has no description")
+		}
+	}
+	return out
+}
+
+// exprSource returns the source text of a block attribute, or "".
+func (m *parsedModule) exprSource(blk *hclsyntax.Block, attr string) string {
+	a, ok := blk.Body.Attributes[attr]
+	if !ok {
+		return ""
+	}
+	f := m.Files[a.SrcRange.Filename]
+	if f == nil {
+		return ""
+	}
+	return string(a.Expr.Range().SliceBytes(f.Bytes))
+}
+
+// normalizeType drops the layout of a type constraint, so the registry's
+// rendering and the generated source compare equal.
+func normalizeType(t string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == ',' {
+			return -1
+		}
+		return r
+	}, t)
+}
+
+func requiredness(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
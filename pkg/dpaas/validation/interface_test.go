@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareInterface(t *testing.T) {
+	info := schematest.WindowsWebApp()
+
+	// publish exactly what the generator produces
+	module := generators.GenerateModule(info, nil)
+	mod := parseGenerated(module)
+	published := &ModuleInterface{Name: "expn-tf-azure-windows-web-app", Version: "1.0.0", Resources: []string{"azurerm_windows_web_app.this"}}
+	for _, name := range sortedKeys(mod.Variables) {
+		decl := mod.Variables[name]
+		_, hasDefault := decl.Body.Attributes["default"]
+		published.Inputs = append(published.Inputs, InterfaceInput{
+			Name:        name,
+			Type:        mod.exprSource(decl, "type"),
+			Description: "d",
+			Required:    !hasDefault,
+		})
+	}
+	published.Outputs = sortedKeys(mod.Outputs)
+
+	report, err := CompareInterface(published, info, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Consistent(), "%+v", report)
+	assert.Equal(t, report.Generated, report.Matched)
+
+	// an older version: site_config flattened into one line, https_only
+	// typed as string, create flag, tags and an output missing, a custom
+	// input and a differently named resource
+	var drifted []InterfaceInput
+	for _, in := range published.Inputs {
+		switch in.Name {
+		case "create_windows_web_app", "tags":
+			continue
+		case "https_only":
+			in.Type = "string"
+		case "site_config":
+			in.Type = "object({ always_on = optional(bool), ip_restriction = optional(map(object({ action = optional(string), ip_address = optional(string) }))) })"
+		case "service_plan_id":
+			in.Required = false
+		case "location":
+			in.Description = ""
+		}
+		drifted = append(drifted, in)
+	}
+	drifted = append(drifted, InterfaceInput{Name: "app_settings", Type: "map(string)", Description: "d"})
+	old := &ModuleInterface{
+		Name:      "windows-web-app",
+		Version:   "0.3.0",
+		Inputs:    drifted,
+		Outputs:   []string{"id"},
+		Resources: []string{"azurerm_windows_web_app.main"},
+	}
+
+	report, err = CompareInterface(old, info, nil)
+	require.NoError(t, err)
+	assert.False(t, report.Consistent())
+
+	missing := map[string]InterfaceDiff{}
+	for _, d := range report.MissingInputs {
+		missing[d.Name] = d
+	}
+	assert.Len(t, missing, 2)
+	assert.Equal(t, "DPAAS008", missing["create_windows_web_app"].Rule)
+	assert.Equal(t, "DPAAS006", missing["tags"].Rule)
+	assert.Contains(t, missing["tags"].Message, "cannot set tags")
+
+	mismatched := map[string]InterfaceDiff{}
+	for _, d := range report.TypeMismatches {
+		mismatched[d.Name] = d
+	}
+	assert.Len(t, mismatched, 2, "the flattened site_config type matches")
+	assert.Equal(t, "string", mismatched["https_only"].Published)
+	assert.Equal(t, "bool", mismatched["https_only"].Generated)
+	assert.Equal(t, "optional", mismatched["service_plan_id"].Published)
+
+	require.Len(t, report.MissingOutputs, 1)
+	assert.Equal(t, "default_hostname", report.MissingOutputs[0].Name)
+	assert.Equal(t, []string{"app_settings"}, report.ExtraInputs)
+
+	rules := map[string]string{}
+	for _, c := range report.Conventions {
+		rules[c.Rule] = c.Name
+	}
+	assert.Equal(t, "windows-web-app", rules[""])
+	assert.Equal(t, "azurerm_windows_web_app", rules["DPAAS004"])
+	assert.Equal(t, "location", rules["DPAAS016"])
+}
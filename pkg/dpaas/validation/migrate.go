@@ -0,0 +1,627 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// MovedFileName is the file MigrateDirectory writes the moved blocks to.
+const MovedFileName = "moved.tf"
+
+// MigrateOptions configures MigrateDirectory.
+type MigrateOptions struct {
+	// ModulesDir holds existing modules named by the pack, e.g.
+	// expn-tf-azure-storage-account. A resource whose module is there is
+	// migrated to it, with a relative source.
+	ModulesDir string
+	// Generate migrates resources without an existing module to the module
+	// the generator produces for them, with the pack's module source.
+	Generate bool
+	// Pack names the modules and renders their source; nil for the built-in pack.
+	Pack *generators.TemplatePack
+	// DryRun computes the migration and the diff without writing any file.
+	DryRun bool
+}
+
+// MigratedResource is one resource rewritten into a module call.
+type MigratedResource struct {
+	Address string `json:"address"` // e.g. "azurerm_storage_account.logs"
+	Module  string `json:"module"`  // e.g. "module.storage_account_logs"
+	File    string `json:"file"`
+	Source  string `json:"source"`
+	// Generated is set when the module does not exist yet and has to be
+	// generated before the configuration is applied.
+	Generated bool `json:"generated"`
+}
+
+// MigrationSkip is a resource left as it was, with the reason.
+type MigrationSkip struct {
+	Address string `json:"address"`
+	File    string `json:"file"`
+	Reason  string `json:"reason"`
+}
+
+// MigrationResult lists what MigrateDirectory rewrote and what it left alone.
+type MigrationResult struct {
+	Migrated []MigratedResource `json:"migrated"`
+	Skipped  []MigrationSkip    `json:"skipped"`
+	Diff     string             `json:"diff"` // unified diff of every changed file
+}
+
+// MigrateDirectory rewrites the bare azurerm resources of a root module into
+// calls of their DPaaS modules:
+//
+//   - each argument is passed as the module variable generated for it, the
+//     name as <short_name>_name, and static nested blocks as the object or
+//     map values of their block variables
+//   - depends_on is kept, and provider becomes the module's providers map
+//   - references to the resource elsewhere in the directory are rewritten to
+//     the module's outputs
+//   - a moved block per resource, in moved.tf, moves its state into the
+//     module so nothing is replaced
+//
+// Resources using count, for_each, dynamic blocks, lifecycle or provisioners,
+// arguments the module has no variable for, or attributes the module does not
+// output are left alone and reported in Skipped.
+func MigrateDirectory(dir string, resolve SchemaResolver, opts MigrateOptions) (*MigrationResult, error) {
+	mod, err := parseModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	if mod.Diags.HasErrors() {
+		return nil, fmt.Errorf("configuration has syntax errors, fix them first: %s", diagSummary(mod.Diags))
+	}
+	if opts.Pack == nil {
+		opts.Pack = generators.DefaultTemplatePack()
+	}
+
+	m := &migrator{mod: mod, resolve: resolve, opts: opts, targets: map[string]*moduleTarget{}, errs: map[string]error{}}
+	result := &MigrationResult{}
+	labels := map[string]bool{}
+	for label := range mod.Modules {
+		labels[label] = true
+	}
+
+	// plan every azurerm resource against its module
+	byAddress := map[string]*migration{}
+	var planned []*migration
+	for _, blk := range mod.Resources {
+		resourceType := blk.Labels[0]
+		if !strings.HasPrefix(resourceType, "azurerm_") {
+			continue
+		}
+		address := resourceType + "." + blk.Labels[1]
+		file := filepath.Base(blk.DefRange().Filename)
+		skip := func(format string, args ...any) {
+			result.Skipped = append(result.Skipped, MigrationSkip{Address: address, File: file, Reason: fmt.Sprintf(format, args...)})
+		}
+
+		target, err := m.target(resourceType)
+		if err != nil {
+			skip("%v", err)
+			continue
+		}
+		label := moduleLabel(target.info.ShortName, blk.Labels[1])
+		if labels[label] {
+			skip("module %q is already declared", label)
+			continue
+		}
+		src := mod.Files[file].Bytes
+		if _, err := target.moduleCall(blk, src, label); err != nil {
+			skip("%v", err)
+			continue
+		}
+		labels[label] = true
+		mg := &migration{address: address, file: file, label: label, labels: blk.Labels, target: target}
+		byAddress[address] = mg
+		planned = append(planned, mg)
+	}
+
+	// every reference to a migrated resource must map onto the module
+	edits := map[string][]sourceEdit{}
+	for _, name := range sortedKeys(mod.Files) {
+		body := mod.Files[name].Body.(*hclsyntax.Body)
+		walkReferences(body, "", func(tr hcl.Traversal, context string) {
+			root, attr := traversalHead(tr)
+			mg := byAddress[root+"."+attr]
+			if mg == nil || mg.skip != "" {
+				return
+			}
+			end, attrName := tr[1].SourceRange(), ""
+			if len(tr) > 2 {
+				if step, ok := tr[2].(hcl.TraverseAttr); ok {
+					end, attrName = step.SrcRange, step.Name
+				}
+			}
+			expr, ok := mg.target.reference(mg.label, attrName, context)
+			if !ok {
+				ref := mg.address
+				if attrName != "" {
+					ref += "." + attrName
+				}
+				mg.skip = fmt.Sprintf("%s is referenced at %s:%d, which %s does not output", ref, name, tr.SourceRange().Start.Line, mg.target.name)
+				return
+			}
+			edits[name] = append(edits[name], sourceEdit{start: tr[0].SourceRange().Start.Byte, end: end.End.Byte, text: expr, migration: mg})
+		})
+	}
+
+	var migrated []*migration
+	for _, mg := range planned {
+		if mg.skip != "" {
+			result.Skipped = append(result.Skipped, MigrationSkip{Address: mg.address, File: mg.file, Reason: mg.skip})
+			continue
+		}
+		migrated = append(migrated, mg)
+	}
+	if len(migrated) == 0 {
+		return result, nil
+	}
+
+	// rewrite the references, then replace each resource with its module call
+	contents := map[string][]byte{}
+	for name, f := range mod.Files {
+		contents[name] = f.Bytes
+	}
+	for name, list := range edits {
+		var kept []sourceEdit
+		for _, e := range list {
+			if e.migration.skip == "" {
+				kept = append(kept, e)
+			}
+		}
+		contents[name] = applyEdits(contents[name], kept)
+	}
+	for _, mg := range migrated {
+		src := contents[mg.file]
+		f, diags := hclsyntax.ParseConfig(src, mg.file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%s after rewriting references: %s", mg.file, diagSummary(diags))
+		}
+		blk := findBlock(f.Body.(*hclsyntax.Body), "resource", mg.labels)
+		if blk == nil {
+			return nil, fmt.Errorf("%s: %s disappeared while rewriting", mg.file, mg.address)
+		}
+		call, err := mg.target.moduleCall(blk, src, mg.label)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", mg.address, err)
+		}
+		rng := blk.Range()
+		contents[mg.file] = applyEdits(src, []sourceEdit{{start: rng.Start.Byte, end: rng.End.Byte, text: strings.TrimRight(call, "\n")}})
+		result.Migrated = append(result.Migrated, MigratedResource{
+			Address:   mg.address,
+			Module:    "module." + mg.label,
+			File:      mg.file,
+			Source:    mg.target.source,
+			Generated: mg.target.generated,
+		})
+	}
+
+	moved := bytes.NewBuffer(append([]byte(nil), contents[MovedFileName]...))
+	for _, mg := range migrated {
+		f := hclwrite.NewEmptyFile()
+		body := f.Body().AppendNewBlock("moved", nil).Body()
+		if err := setRawAttribute(body, "from", mg.address); err != nil {
+			return nil, err
+		}
+		if err := setRawAttribute(body, "to", mg.target.address(mg.label)); err != nil {
+			return nil, err
+		}
+		appendBlock(moved, string(f.Bytes()))
+	}
+	contents[MovedFileName] = moved.Bytes()
+
+	files := map[string]*fixedFile{}
+	for name, content := range contents {
+		var before []byte
+		if f, ok := mod.Files[name]; ok {
+			before = f.Bytes
+		}
+		after := formatLike(before, content)
+		if !bytes.Equal(before, after) {
+			files[name] = &fixedFile{before: before, after: after}
+		}
+	}
+	if result.Diff, err = writeChanges(dir, files, opts.DryRun); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type migrator struct {
+	mod     *parsedModule
+	resolve SchemaResolver
+	opts    MigrateOptions
+	targets map[string]*moduleTarget
+	errs    map[string]error
+}
+
+// migration is one resource being rewritten into a module call.
+type migration struct {
+	address string
+	file    string
+	label   string   // module label
+	labels  []string // resource block labels
+	target  *moduleTarget
+	skip    string // set when a reference cannot be rewritten
+}
+
+// moduleTarget is the module a resource type migrates to, with the
+// interface its calls are checked against.
+type moduleTarget struct {
+	info      *schema.ResourceInfo
+	name      string // module name, e.g. expn-tf-azure-storage-account
+	source    string
+	generated bool
+	iface     *parsedModule
+	wrapped   string // label of the wrapped resource in the module
+	counted   bool   // the wrapped resource uses count
+}
+
+// target resolves the module for a resource type: an existing one in
+// ModulesDir, otherwise, with Generate, the one the generator produces.
+func (m *migrator) target(resourceType string) (*moduleTarget, error) {
+	if t, ok := m.targets[resourceType]; ok {
+		return t, m.errs[resourceType]
+	}
+	t, err := m.newTarget(resourceType)
+	m.targets[resourceType], m.errs[resourceType] = t, err
+	return t, err
+}
+
+func (m *migrator) newTarget(resourceType string) (*moduleTarget, error) {
+	resolved, err := m.resolve([]string{resourceType})
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no schema for %s", resourceType)
+	}
+	info := *resolved[0]
+	info.ModuleName = m.opts.Pack.ModuleName(info.ShortName)
+	t := &moduleTarget{info: &info, name: info.ModuleName}
+
+	local := filepath.Join(m.opts.ModulesDir, info.ModuleName)
+	switch {
+	case m.opts.ModulesDir != "" && fileExists(filepath.Join(local, "main.tf")):
+		if t.iface, err = parseModule(local); err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(m.mod.Dir, local)
+		if err != nil {
+			return nil, err
+		}
+		t.source = filepath.ToSlash(rel)
+		if !strings.HasPrefix(t.source, ".") {
+			t.source = "./" + t.source
+		}
+	case m.opts.Generate:
+		files, err := generators.GenerateModuleWithOptions(&info, generators.Options{Pack: m.opts.Pack})
+		if err != nil {
+			return nil, fmt.Errorf("generate %s: %w", info.ModuleName, err)
+		}
+		t.iface = parseGenerated(files)
+		if t.source, err = m.opts.Pack.ModuleSource(&info); err != nil {
+			return nil, err
+		}
+		t.generated = true
+	default:
+		return nil, fmt.Errorf("no module %s in %s", info.ModuleName, m.opts.ModulesDir)
+	}
+
+	blk := t.iface.wrappedResource(resourceType)
+	if blk == nil {
+		return nil, fmt.Errorf("module %s does not declare a %s", info.ModuleName, resourceType)
+	}
+	if _, ok := blk.Body.Attributes["for_each"]; ok {
+		return nil, fmt.Errorf("module %s creates its %s for_each, so the resource cannot be moved into it", info.ModuleName, resourceType)
+	}
+	_, t.counted = blk.Body.Attributes["count"]
+	t.wrapped = blk.Labels[1]
+	return t, nil
+}
+
+// moduleLabel names the module call replacing a resource: the short name,
+// suffixed with the resource name unless that adds nothing.
+func moduleLabel(shortName, resourceName string) string {
+	switch resourceName {
+	case shortName, "this", "main":
+		return shortName
+	}
+	return shortName + "_" + resourceName
+}
+
+// address is where the resource lives inside the module call.
+func (t *moduleTarget) address(label string) string {
+	addr := fmt.Sprintf("module.%s.%s.%s", label, t.info.ResourceType, t.wrapped)
+	if t.counted {
+		addr += "[0]"
+	}
+	return addr
+}
+
+// reference returns the expression replacing a reference to attr of the
+// migrated resource, or to the resource itself when attr is empty. context
+// is "depends_on" or "address" for the arguments taking a static address.
+func (t *moduleTarget) reference(label, attr, context string) (string, bool) {
+	switch {
+	case context == "address":
+		return t.address(label), attr == ""
+	case context == "depends_on" && attr == "":
+		return "module." + label, true
+	}
+
+	base := "module." + label
+	index := ""
+	if t.counted {
+		index = "[0]"
+	}
+	if out := t.outputValue(attr); out != nil && attr != "" {
+		switch {
+		case t.wholeResource(out):
+			return base + "." + attr + index + "." + attr, true
+		case isSplat(out):
+			return "one(" + base + "." + attr + ")", true
+		}
+		return base + "." + attr, true
+	}
+	// an output holding the whole resource exposes every attribute
+	for _, name := range sortedKeys(t.iface.Outputs) {
+		if t.wholeResource(t.outputValue(name)) {
+			ref := base + "." + name + index
+			if attr != "" {
+				ref += "." + attr
+			}
+			return ref, true
+		}
+	}
+	return "", false
+}
+
+func (t *moduleTarget) outputValue(name string) hcl.Expression {
+	if blk := t.iface.Outputs[name]; blk != nil {
+		if attr, ok := blk.Body.Attributes["value"]; ok {
+			return attr.Expr
+		}
+	}
+	return nil
+}
+
+// wholeResource reports whether an output's value is the wrapped resource itself.
+func (t *moduleTarget) wholeResource(expr hcl.Expression) bool {
+	st, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(st.Traversal) != 2 {
+		return false
+	}
+	root, name := traversalHead(st.Traversal)
+	return root == t.info.ResourceType && name == t.wrapped
+}
+
+func isSplat(expr hcl.Expression) bool {
+	_, ok := expr.(*hclsyntax.SplatExpr)
+	return ok
+}
+
+// moduleCall renders the module block replacing a resource block, whose
+// expressions are copied from src. An error explains why the resource
+// cannot be migrated.
+func (t *moduleTarget) moduleCall(blk *hclsyntax.Block, src []byte, label string) (string, error) {
+	info := t.info
+	type arg struct{ name, expr string }
+	var args, blocks, meta []arg
+	for _, attr := range attributesInOrder(blk.Body) {
+		expr := string(attr.Expr.Range().SliceBytes(src))
+		switch attr.Name {
+		case "count", "for_each":
+			return "", fmt.Errorf("uses %s; migrate it by hand", attr.Name)
+		case "depends_on":
+			meta = append(meta, arg{"depends_on", expr})
+			continue
+		case "provider":
+			meta = append(meta, arg{"providers", "{ azurerm = " + expr + " }"})
+			continue
+		}
+		if schemaAttribute(info.Attributes, attr.Name) == nil {
+			return "", fmt.Errorf("%s is not an argument of %s", attr.Name, info.ResourceType)
+		}
+		name := generators.VariableName(attr.Name, info.ShortName)
+		if attr.Name == "name" {
+			name = info.ShortName + "_name"
+		}
+		args = append(args, arg{name, expr})
+	}
+
+	order, groups := groupBlocks(blk.Body.Blocks)
+	for _, typ := range order {
+		switch typ {
+		case "lifecycle", "provisioner", "connection", "dynamic":
+			return "", fmt.Errorf("has a %s block; migrate it by hand", typ)
+		}
+		block := schemaBlock(info.Blocks, typ)
+		if block == nil {
+			return "", fmt.Errorf("%s is not a block of %s", typ, info.ResourceType)
+		}
+		value, err := blockValue(*block, groups[typ], src)
+		if err != nil {
+			return "", err
+		}
+		blocks = append(blocks, arg{block.Name, value})
+	}
+
+	set := map[string]bool{}
+	for _, a := range append(args, blocks...) {
+		if t.iface.Variables[a.name] == nil {
+			return "", fmt.Errorf("module %s has no variable %s", t.name, a.name)
+		}
+		set[a.name] = true
+	}
+	for _, name := range sortedKeys(t.iface.Variables) {
+		if _, hasDefault := t.iface.Variables[name].Body.Attributes["default"]; !hasDefault && !set[name] {
+			return "", fmt.Errorf("module %s requires %s, which the resource does not set", t.name, name)
+		}
+	}
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body().AppendNewBlock("module", []string{label}).Body()
+	body.SetAttributeValue("source", cty.StringVal(t.source))
+	for _, group := range [][]arg{args, blocks, meta} {
+		if len(group) > 0 {
+			body.AppendNewline()
+		}
+		for _, a := range group {
+			if err := setRawAttribute(body, a.name, a.expr); err != nil {
+				return "", err
+			}
+		}
+	}
+	return string(hclwrite.Format(f.Bytes())), nil
+}
+
+// blockValue converts static nested blocks into the value of their block
+// variable: an object for single blocks, otherwise a map keyed "<block>-<n>".
+func blockValue(block schema.ParsedBlock, blocks []*hclsyntax.Block, src []byte) (string, error) {
+	if generators.IsSingleBlock(block) {
+		if len(blocks) > 1 {
+			return "", fmt.Errorf("has %d %s blocks, but the module takes one", len(blocks), block.Name)
+		}
+		return blockObject(block, blocks[0], src)
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, blk := range blocks {
+		obj, err := blockObject(block, blk, src)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s-%d = %s\n", block.Name, i+1, obj)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+func blockObject(block schema.ParsedBlock, blk *hclsyntax.Block, src []byte) (string, error) {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, attr := range attributesInOrder(blk.Body) {
+		if schemaAttribute(block.Attributes, attr.Name) == nil {
+			return "", fmt.Errorf("%s is not an argument of the %s block", attr.Name, block.Name)
+		}
+		fmt.Fprintf(&b, "%s = %s\n", attr.Name, attr.Expr.Range().SliceBytes(src))
+	}
+	order, groups := groupBlocks(blk.Body.Blocks)
+	for _, typ := range order {
+		nested := schemaBlock(block.Blocks, typ)
+		if nested == nil {
+			return "", fmt.Errorf("%s is not a block of %s", typ, block.Name)
+		}
+		value, err := blockValue(*nested, groups[typ], src)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s = %s\n", nested.Name, value)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+func schemaAttribute(attrs []schema.ParsedAttribute, name string) *schema.ParsedAttribute {
+	for i := range attrs {
+		if attrs[i].Name == name {
+			return &attrs[i]
+		}
+	}
+	return nil
+}
+
+func schemaBlock(blocks []schema.ParsedBlock, name string) *schema.ParsedBlock {
+	for i := range blocks {
+		if blocks[i].Name == name {
+			return &blocks[i]
+		}
+	}
+	return nil
+}
+
+// attributesInOrder returns the attributes of body in source order.
+func attributesInOrder(body *hclsyntax.Body) []*hclsyntax.Attribute {
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+	return attrs
+}
+
+// groupBlocks groups blocks by type, in order of first appearance.
+func groupBlocks(blocks hclsyntax.Blocks) ([]string, map[string][]*hclsyntax.Block) {
+	var order []string
+	groups := map[string][]*hclsyntax.Block{}
+	for _, blk := range blocks {
+		if groups[blk.Type] == nil {
+			order = append(order, blk.Type)
+		}
+		groups[blk.Type] = append(groups[blk.Type], blk)
+	}
+	return order, groups
+}
+
+// walkReferences calls fn with every traversal in body and its nested
+// blocks. context is "depends_on" inside depends_on and "address" inside
+// the arguments of moved, import and removed blocks, which take addresses.
+func walkReferences(body *hclsyntax.Body, blockType string, fn func(tr hcl.Traversal, context string)) {
+	for _, attr := range body.Attributes {
+		context := ""
+		switch {
+		case attr.Name == "depends_on":
+			context = "depends_on"
+		case blockType == "moved" || blockType == "import" || blockType == "removed":
+			if attr.Name != "id" {
+				context = "address"
+			}
+		}
+		for _, tr := range attr.Expr.Variables() {
+			fn(tr, context)
+		}
+	}
+	for _, blk := range body.Blocks {
+		walkReferences(blk.Body, blk.Type, fn)
+	}
+}
+
+func findBlock(body *hclsyntax.Body, blockType string, labels []string) *hclsyntax.Block {
+	for _, blk := range body.Blocks {
+		if blk.Type == blockType && strings.Join(blk.Labels, ".") == strings.Join(labels, ".") {
+			return blk
+		}
+	}
+	return nil
+}
+
+// sourceEdit replaces src[start:end] with text.
+type sourceEdit struct {
+	start, end int
+	text       string
+	migration  *migration // the resource a rewritten reference points at
+}
+
+// applyEdits applies non-overlapping edits to src.
+func applyEdits(src []byte, edits []sourceEdit) []byte {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDirectory(t *testing.T) {
+	resolve := func(types []string) ([]*schema.ResourceInfo, error) {
+		if types[0] != "azurerm_windows_web_app" {
+			return nil, os.ErrNotExist
+		}
+		return []*schema.ResourceInfo{schematest.WindowsWebApp()}, nil
+	}
+	legacy := `resource "azurerm_resource_group" "rg" {
+  name     = "rg"
+  location = "eastus2"
+}
+
+resource "azurerm_windows_web_app" "app" {
+  name                = "legacy-app"
+  location            = azurerm_resource_group.rg.location
+  resource_group_name = azurerm_resource_group.rg.name
+  service_plan_id     = "/subscriptions/x/plan"
+  enabled             = false
+
+  site_config {
+    always_on = true
+
+    ip_restriction {
+      action     = "Allow"
+      ip_address = "10.0.0.0/8"
+    }
+    ip_restriction {
+      action     = "Deny"
+      ip_address = "0.0.0.0/0"
+    }
+  }
+
+  connection_string {
+    name  = "db"
+    type  = "SQLAzure"
+    value = var.db
+  }
+
+  depends_on = [azurerm_resource_group.rg]
+}
+
+resource "azurerm_windows_web_app" "pinned" {
+  name                = "pinned-app"
+  location            = "eastus2"
+  resource_group_name = "rg"
+  service_plan_id     = azurerm_windows_web_app.app.service_plan_id
+
+  site_config {}
+
+  lifecycle {
+    ignore_changes = [tags]
+  }
+}
+
+variable "db" {
+  type      = string
+  sensitive = true
+}
+
+output "hostname" {
+  value = azurerm_windows_web_app.app.default_hostname
+}
+
+output "app_url" {
+  value = "https://${azurerm_windows_web_app.app.name}/"
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(legacy), 0644))
+
+	r, err := MigrateDirectory(dir, resolve, MigrateOptions{Generate: true})
+	require.NoError(t, err)
+	require.Len(t, r.Migrated, 1)
+	assert.Equal(t, MigratedResource{
+		Address:   "azurerm_windows_web_app.app",
+		Module:    "module.windows_web_app_app",
+		File:      "main.tf",
+		Source:    "git::https://code.experian.local/scm/DPAAS/expn-tf-azure-windows-web-app.git",
+		Generated: true,
+	}, r.Migrated[0])
+	require.Len(t, r.Skipped, 2)
+	assert.Equal(t, "azurerm_resource_group.rg", r.Skipped[0].Address)
+	assert.Equal(t, "azurerm_windows_web_app.pinned", r.Skipped[1].Address)
+	assert.Contains(t, r.Skipped[1].Reason, "lifecycle")
+
+	main, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	f, diags := hclsyntax.ParseConfig(main, "main.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	call := findBlock(f.Body.(*hclsyntax.Body), "module", []string{"windows_web_app_app"})
+	require.NotNil(t, call, string(main))
+	for _, name := range []string{"source", "windows_web_app_name", "windows_web_app_enabled", "location", "service_plan_id", "site_config", "connection_string", "depends_on"} {
+		assert.Contains(t, call.Body.Attributes, name)
+	}
+	assert.NotContains(t, string(main), `resource "azurerm_windows_web_app" "app"`)
+	assert.Contains(t, string(main), "ip_restriction-2 = {\n")
+	assert.Contains(t, string(main), "value = one(module.windows_web_app_app.default_hostname)")
+	assert.Contains(t, string(main), `"https://${module.windows_web_app_app.id[0].name}/"`)
+	assert.Contains(t, string(main), "service_plan_id     = module.windows_web_app_app.id[0].service_plan_id", "the skipped resource's references are rewritten too")
+
+	moved, err := os.ReadFile(filepath.Join(dir, MovedFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(moved), "from = azurerm_windows_web_app.app\n")
+	assert.Contains(t, string(moved), "to   = module.windows_web_app_app.azurerm_windows_web_app.this[0]\n")
+	assert.Contains(t, r.Diff, "+++ b/moved.tf")
+
+	// an existing module is used with a relative source, and only resources it
+	// can take are migrated
+	root := t.TempDir()
+	modules := filepath.Join(root, "modules")
+	_, err = generators.WriteModule(filepath.Join(modules, "expn-tf-azure-windows-web-app"), generators.GenerateModule(schematest.WindowsWebApp(), nil))
+	require.NoError(t, err)
+	live := filepath.Join(root, "live")
+	require.NoError(t, os.MkdirAll(live, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(live, "main.tf"), []byte(legacy), 0644))
+
+	r, err = MigrateDirectory(live, resolve, MigrateOptions{ModulesDir: modules, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, r.Migrated, 1)
+	assert.Equal(t, "../modules/expn-tf-azure-windows-web-app", r.Migrated[0].Source)
+	assert.False(t, r.Migrated[0].Generated)
+	after, err := os.ReadFile(filepath.Join(live, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(after), "a dry run writes nothing")
+	assert.NoFileExists(t, filepath.Join(live, MovedFileName))
+}
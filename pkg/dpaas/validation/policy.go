@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// policyBaseline are the security settings generated policies enforce.
+func policyBaseline() []securitySetting {
+	var baseline []securitySetting
+	baseline = append(baseline, publicAccessSettings...)
+	baseline = append(baseline, transportSettings...)
+	baseline = append(baseline, encryptionSettings...)
+	return baseline
+}
+
+// PolicySettings returns the public access, transport and encryption settings
+// of the security baseline the resource has, for generators.GeneratePolicies.
+func PolicySettings(info *schema.ResourceInfo) []generators.PolicySetting {
+	var out []generators.PolicySetting
+	for _, p := range settingPaths(info, policyBaseline()) {
+		out = append(out, generators.PolicySetting{Path: p.path, Values: p.setting.Values, NullOK: p.setting.DefaultSecure})
+	}
+	return out
+}
+
+// CrossCheckPolicies checks each rule of a generated policy set against the
+// variables of the module at modulePath, recording the variable that feeds
+// the argument and where the module accepts values the policy denies.
+func CrossCheckPolicies(modulePath string, info *schema.ResourceInfo, ps *generators.PolicySet) error {
+	mod, err := parseModule(modulePath)
+	if err != nil {
+		return err
+	}
+	if mod.wrappedResource(info.ResourceType) == nil {
+		return fmt.Errorf("%s does not declare a %s resource", modulePath, info.ResourceType)
+	}
+
+	settings := map[string]securitySetting{}
+	for _, p := range settingPaths(info, policyBaseline()) {
+		settings[p.path] = p.setting
+	}
+	wired := map[string]CoverageEntry{}
+	for _, e := range checkCoverage(mod, info).Entries {
+		wired[e.Path] = e
+	}
+	for i := range ps.Rules {
+		r := &ps.Rules[i]
+		setting, isSetting := settings[r.Path]
+		crossCheck(r, mod, wired[r.Path], setting, isSetting && r.Kind == "security")
+	}
+	return nil
+}
+
+// crossCheck records where the module's variables disagree with the rule:
+// an argument no variable sets, an enum variable validating other values,
+// or a variable defaulting to a value the policy rejects.
+func crossCheck(r *generators.PolicyRule, mod *parsedModule, e CoverageEntry, setting securitySetting, isSetting bool) {
+	if !e.Wired || e.Variable == "" {
+		switch {
+		case r.Kind == "tags":
+			r.Findings = append(r.Findings, "tags are not set from local.tags; callers cannot add the required tags")
+		case r.NullOK:
+			r.Findings = append(r.Findings, fmt.Sprintf("no module variable sets %s; the provider default applies", r.Path))
+		default:
+			r.Findings = append(r.Findings, fmt.Sprintf("no module variable sets %s, so every plan fails the policy", r.Path))
+		}
+		return
+	}
+	r.Variable = e.Variable
+
+	// object fields are validated and defaulted inside their block variable
+	decl := mod.Variables[e.Variable]
+	if decl == nil {
+		return
+	}
+
+	switch r.Kind {
+	case "enum":
+		validated := validationValues(decl)
+		if validated == nil {
+			r.Findings = append(r.Findings, fmt.Sprintf("var.%s has no validation; the policy is the only check of its value", e.Variable))
+			return
+		}
+		var policyOnly, variableOnly []string
+		for _, v := range r.Allowed {
+			if !validated[v.(string)] {
+				policyOnly = append(policyOnly, v.(string))
+			}
+		}
+		allowed := map[string]bool{}
+		for _, v := range r.Allowed {
+			allowed[v.(string)] = true
+		}
+		for _, v := range sortedKeys(validated) {
+			if !allowed[v] {
+				variableOnly = append(variableOnly, v)
+			}
+		}
+		if len(variableOnly) > 0 {
+			r.Findings = append(r.Findings, fmt.Sprintf("var.%s accepts %s, which the policy rejects", e.Variable, strings.Join(variableOnly, ", ")))
+		}
+		if len(policyOnly) > 0 {
+			r.Findings = append(r.Findings, fmt.Sprintf("the policy allows %s, which var.%s rejects", strings.Join(policyOnly, ", "), e.Variable))
+		}
+	case "security":
+		def, ok := decl.Body.Attributes["default"]
+		if !ok || !isSetting {
+			return
+		}
+		v, diags := def.Expr.Value(nil)
+		switch {
+		case diags.HasErrors() || !v.IsWhollyKnown():
+		case v.IsNull() && !r.NullOK:
+			r.Findings = append(r.Findings, fmt.Sprintf("var.%s defaults to null, which fails the policy unless callers set it to %s", e.Variable, setting.Want))
+		case !v.IsNull() && !setting.Secure(v):
+			r.Findings = append(r.Findings, fmt.Sprintf("var.%s defaults to %s, which fails the policy; want %s", e.Variable, renderValue(v), setting.Want))
+		}
+	}
+}
+
+// validationValues collects the string lists a variable's validation
+// conditions check membership in, e.g. contains(["Standard", "Premium"], var.x).
+// It returns nil when the variable has no such validation.
+func validationValues(decl *hclsyntax.Block) map[string]bool {
+	var values map[string]bool
+	for _, blk := range decl.Body.Blocks {
+		cond, ok := blk.Body.Attributes["condition"]
+		if blk.Type != "validation" || !ok {
+			continue
+		}
+		hclsyntax.VisitAll(cond.Expr, func(n hclsyntax.Node) hcl.Diagnostics {
+			tuple, ok := n.(*hclsyntax.TupleConsExpr)
+			if !ok {
+				return nil
+			}
+			for _, e := range tuple.Exprs {
+				v, diags := e.Value(nil)
+				if diags.HasErrors() || v.Type() != cty.String || v.IsNull() {
+					return nil
+				}
+			}
+			for _, e := range tuple.Exprs {
+				v, _ := e.Value(nil)
+				if values == nil {
+					values = map[string]bool{}
+				}
+				values[v.AsString()] = true
+			}
+			return nil
+		})
+	}
+	return values
+}
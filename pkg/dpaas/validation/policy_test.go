@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossCheckPolicies(t *testing.T) {
+	info := schematest.StorageAccount()
+	dir := t.TempDir()
+	_, err := generators.WriteModule(dir, generators.GenerateModule(info, []string{"default"}))
+	require.NoError(t, err)
+
+	// a hand edit lets the variable accept a value the schema does not list
+	vars, err := os.ReadFile(filepath.Join(dir, "variables.tf"))
+	require.NoError(t, err)
+	require.Contains(t, string(vars), `["Standard", "Premium"]`)
+	vars = []byte(strings.Replace(string(vars), `["Standard", "Premium"]`, `["Standard", "Premium", "Basic"]`, 1))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), vars, 0644))
+
+	security := PolicySettings(info)
+	require.Len(t, security, 2)
+	assert.Equal(t, generators.PolicySetting{Path: "min_tls_version", Values: tlsPolicyValues, NullOK: true}, security[0])
+
+	ps, err := generators.GeneratePolicies(info, generators.PolicyOptions{Rego: true, RequiredTags: []string{"AppID", "innersource"}, Security: security})
+	require.NoError(t, err)
+	require.NoError(t, CrossCheckPolicies(dir, info, ps))
+
+	rules := map[string]generators.PolicyRule{}
+	for _, r := range ps.Rules {
+		rules[r.Name] = r
+	}
+	require.Len(t, rules, 5)
+	assert.Equal(t, []string{"var.account_tier accepts Basic, which the policy rejects"}, rules["account_tier_allowed"].Findings)
+	assert.Empty(t, rules["min_tls_version_allowed"].Findings)
+	assert.Empty(t, rules["min_tls_version_secure"].Findings, "TLS is secure by default")
+	assert.Equal(t, "public_network_access_enabled", rules["public_network_access_enabled_secure"].Variable)
+	assert.Equal(t, []string{"var.public_network_access_enabled defaults to null, which fails the policy unless callers set it to false"}, rules["public_network_access_enabled_secure"].Findings)
+	assert.Equal(t, "tags", rules["required_tags"].Variable)
+	assert.Empty(t, rules["required_tags"].Findings)
+
+	err = CrossCheckPolicies(writeTestModule(t, schematest.WindowsWebApp()), info, ps)
+	assert.ErrorContains(t, err, "does not declare a azurerm_storage_account resource")
+}
@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	slug "github.com/hashicorp/go-slug"
+)
+
+// changelogHeading matches a Keep a Changelog release heading, e.g.
+// "## [1.2.0] - 2025-01-31" or "## 1.2.0".
+var changelogHeading = regexp.MustCompile(`^##\s+\[?v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?)\]?(?:\s|$)`)
+
+// ChangelogVersion returns the latest released version in the module's
+// CHANGELOG.md: the first release heading, skipping "Unreleased".
+func ChangelogVersion(modulePath string) (string, error) {
+	path := filepath.Join(modulePath, "CHANGELOG.md")
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := changelogHeading.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			return m[1], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s has no release heading such as \"## [1.0.0] - <date>\"", path)
+}
+
+// PackModule packages the module directory as a configuration slug, the
+// tar.gz archive the private registry takes for a module version. .git and
+// .terraform directories and anything matched by .terraformignore are left
+// out.
+func PackModule(modulePath string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	if _, err := slug.Pack(modulePath, &buf, true); err != nil {
+		return nil, fmt.Errorf("packaging %s: %w", modulePath, err)
+	}
+	return &buf, nil
+}
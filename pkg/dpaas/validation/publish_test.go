@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		changelog string
+		want      string
+	}{
+		{"keep a changelog", "# Changelog\n\n## [1.0.0] - 2025-01-31\n\n### Added\n", "1.0.0"},
+		{"unreleased skipped", "# Changelog\n\n## [Unreleased]\n\n## [1.3.0] - 2025-03-01\n\n## [1.2.0] - 2025-02-01\n", "1.3.0"},
+		{"plain heading", "# Changelog\n\n## v2.1.0-rc.1\n", "2.1.0-rc.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte(tt.changelog), 0644))
+			got, err := ChangelogVersion(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte("# Changelog\n\n## [Unreleased]\n"), 0644))
+	_, err := ChangelogVersion(dir)
+	assert.ErrorContains(t, err, "no release heading")
+}
+
+func TestPackModule(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"main.tf":                          "# main\n",
+		"examples/basic/main.tf":           "# example\n",
+		".terraform/providers/lock":        "x",
+		"tests/default/.terraform/modules": "x",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	buf, err := PackModule(dir)
+	require.NoError(t, err)
+
+	gz, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var files []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if h.Typeflag == tar.TypeReg {
+			files = append(files, h.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"main.tf", "examples/basic/main.tf"}, files)
+}
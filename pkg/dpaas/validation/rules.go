@@ -0,0 +1,340 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+)
+
+// RuleConfigFileName is looked up in the module directory when no explicit
+// rule configuration is supplied.
+const RuleConfigFileName = ".dpaas-rules.json"
+
+// Severity ranks a rule. Only failing error-severity checks fail a report.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func (s Severity) valid() bool {
+	return s == SeverityError || s == SeverityWarning || s == SeverityInfo
+}
+
+// Rule is one named validation standard. Check returns the individual
+// assertions the rule makes; it returns nothing when the rule does not apply.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	Remediation string
+	Check       func(rc *RuleContext) []CheckResult
+}
+
+// RuleContext is what a rule sees: the module on disk, its HCL parse, and the
+// schemas of the resources it wraps. Info is the primary resource the DPaaS
+// conventions apply to (nil when unknown); Resources lists every wrapped
+// resource, primary first.
+type RuleContext struct {
+	ModulePath string
+	Info       *schema.ResourceInfo
+	Resources  []*schema.ResourceInfo
+	Options    Options
+
+	mod           *parsedModule
+	coverage      map[string]*CoverageReport
+	terraform     *terraformResults
+	scenarioCache *[]scenario
+}
+
+// Coverage returns the argument coverage report of the primary resource.
+func (rc *RuleContext) Coverage() *CoverageReport {
+	if rc.Info == nil {
+		return nil
+	}
+	return rc.CoverageFor(rc.Info)
+}
+
+// CoverageFor returns the argument coverage report of one wrapped resource,
+// computing it once.
+func (rc *RuleContext) CoverageFor(info *schema.ResourceInfo) *CoverageReport {
+	if rc.coverage == nil {
+		rc.coverage = map[string]*CoverageReport{}
+	}
+	cr, ok := rc.coverage[info.ResourceType]
+	if !ok {
+		cr = checkCoverage(rc.mod, info)
+		rc.coverage[info.ResourceType] = cr
+	}
+	return cr
+}
+
+// hasFile reports whether the named top-level .tf file parsed.
+func (rc *RuleContext) hasFile(name string) bool {
+	_, ok := rc.mod.Files[name]
+	return ok
+}
+
+func (rc *RuleContext) terraformStage() *terraformResults {
+	if rc.terraform == nil && rc.Options.Terraform != nil {
+		rc.terraform = runTerraformStage(rc.ModulePath, rc.Options.Terraform)
+	}
+	return rc.terraform
+}
+
+// ---------------------------------------------------------------------------
+// Rule set
+// ---------------------------------------------------------------------------
+
+// RuleSet is an ordered registry of rules keyed by ID.
+type RuleSet struct {
+	rules    []Rule
+	disabled map[string]bool
+}
+
+// DefaultRuleSet returns the built-in DPaaS and security rules, all enabled.
+func DefaultRuleSet() *RuleSet {
+	rs := &RuleSet{disabled: map[string]bool{}}
+	for _, r := range append(builtinRules(), securityRules()...) {
+		// builtin IDs are unique; Register only fails on duplicates
+		_ = rs.Register(r)
+	}
+	return rs
+}
+
+// Register adds a rule. IDs must be unique within the set.
+func (rs *RuleSet) Register(r Rule) error {
+	if r.ID == "" || r.Check == nil {
+		return fmt.Errorf("rule must have an ID and a Check function")
+	}
+	if !r.Severity.valid() {
+		return fmt.Errorf("rule %s: invalid severity %q", r.ID, r.Severity)
+	}
+	if rs.find(r.ID) >= 0 {
+		return fmt.Errorf("rule %s is already registered", r.ID)
+	}
+	rs.rules = append(rs.rules, r)
+	return nil
+}
+
+// Rules returns every registered rule, enabled or not, in registration order.
+func (rs *RuleSet) Rules() []Rule {
+	return append([]Rule(nil), rs.rules...)
+}
+
+// Enabled reports whether the rule with the given ID will run.
+func (rs *RuleSet) Enabled(id string) bool {
+	return rs.find(id) >= 0 && !rs.disabled[id]
+}
+
+// Apply layers a configuration on top of the set: disables and re-enables
+// rules, overrides severities and registers custom rules.
+func (rs *RuleSet) Apply(cfg *RuleConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for _, c := range cfg.Custom {
+		r, err := c.rule()
+		if err != nil {
+			return err
+		}
+		if err := rs.Register(r); err != nil {
+			return err
+		}
+	}
+	for _, id := range cfg.Disable {
+		if rs.find(id) < 0 {
+			return fmt.Errorf("cannot disable unknown rule %q", id)
+		}
+		rs.disabled[id] = true
+	}
+	for _, id := range cfg.Enable {
+		if rs.find(id) < 0 {
+			return fmt.Errorf("cannot enable unknown rule %q", id)
+		}
+		delete(rs.disabled, id)
+	}
+	ids := make([]string, 0, len(cfg.Severity))
+	for id := range cfg.Severity {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		sev := cfg.Severity[id]
+		i := rs.find(id)
+		if i < 0 {
+			return fmt.Errorf("cannot override severity of unknown rule %q", id)
+		}
+		if !sev.valid() {
+			return fmt.Errorf("rule %s: invalid severity %q", id, sev)
+		}
+		rs.rules[i].Severity = sev
+	}
+	return nil
+}
+
+func (rs *RuleSet) find(id string) int {
+	for i, r := range rs.rules {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// run evaluates every enabled rule and stamps rule metadata onto its checks.
+func (rs *RuleSet) run(rc *RuleContext, r *ValidationReport) {
+	for _, rule := range rs.rules {
+		if rs.disabled[rule.ID] {
+			continue
+		}
+		r.Rules = append(r.Rules, RuleSummary{
+			ID:          rule.ID,
+			Severity:    rule.Severity,
+			Description: rule.Description,
+			Remediation: rule.Remediation,
+		})
+		for _, c := range rule.Check(rc) {
+			c.RuleID = rule.ID
+			if c.Severity == "" {
+				c.Severity = rule.Severity
+			}
+			if !c.Passed && c.Message == "" {
+				c.Message = rule.Remediation
+			}
+			r.add(c)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Configuration
+// ---------------------------------------------------------------------------
+
+// RuleConfig customises the rule set for a team or module. It is read from
+// JSON, e.g.
+//
+//	{
+//	  "disable":  ["DPAAS015"],
+//	  "severity": {"DPAAS014": "warning"},
+//	  "custom":   [{"id": "TEAM001", "kind": "file_exists", "file": "examples/basic/main.tf",
+//	                "severity": "warning", "description": "Module ships a basic example"}]
+//	}
+type RuleConfig struct {
+	Disable  []string            `json:"disable,omitempty"`
+	Enable   []string            `json:"enable,omitempty"`
+	Severity map[string]Severity `json:"severity,omitempty"`
+	Custom   []CustomRule        `json:"custom,omitempty"`
+}
+
+// CustomRule is a declarative rule defined in configuration.
+//
+// Kinds:
+//   - file_exists:       File must exist (relative to the module root)
+//   - variable_exists:   variable Name must be declared
+//   - local_exists:      local value Name must be defined
+//   - output_exists:     output Name must be declared
+//   - resource_argument: the wrapped primary resource must set Argument
+type CustomRule struct {
+	ID          string   `json:"id"`
+	Kind        string   `json:"kind"`
+	Severity    Severity `json:"severity,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	File        string   `json:"file,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Argument    string   `json:"argument,omitempty"`
+}
+
+// LoadRuleConfig reads a JSON rule configuration file.
+func LoadRuleConfig(path string) (*RuleConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg RuleConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parse rule config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// loadModuleRuleConfig returns the module's own .dpaas-rules.json, if any.
+func loadModuleRuleConfig(modulePath string) (*RuleConfig, error) {
+	path := filepath.Join(modulePath, RuleConfigFileName)
+	if !fileExists(path) {
+		return nil, nil
+	}
+	return LoadRuleConfig(path)
+}
+
+func (c CustomRule) rule() (Rule, error) {
+	if c.ID == "" {
+		return Rule{}, fmt.Errorf("custom rule is missing an id")
+	}
+	sev := c.Severity
+	if sev == "" {
+		sev = SeverityError
+	}
+	r := Rule{ID: c.ID, Severity: sev, Description: c.Description, Remediation: c.Remediation}
+
+	need := func(field, value string) error {
+		if value == "" {
+			return fmt.Errorf("custom rule %s (%s) requires %q", c.ID, c.Kind, field)
+		}
+		return nil
+	}
+
+	switch c.Kind {
+	case "file_exists":
+		if err := need("file", c.File); err != nil {
+			return Rule{}, err
+		}
+		r.Check = func(rc *RuleContext) []CheckResult {
+			return []CheckResult{{
+				Name:   fmt.Sprintf("File exists: %s", c.File),
+				Passed: fileExists(filepath.Join(rc.ModulePath, c.File)),
+			}}
+		}
+	case "variable_exists", "local_exists", "output_exists":
+		if err := need("name", c.Name); err != nil {
+			return Rule{}, err
+		}
+		r.Check = func(rc *RuleContext) []CheckResult {
+			var ok bool
+			switch c.Kind {
+			case "variable_exists":
+				_, ok = rc.mod.Variables[c.Name]
+			case "local_exists":
+				_, ok = rc.mod.Locals[c.Name]
+			case "output_exists":
+				_, ok = rc.mod.Outputs[c.Name]
+			}
+			return []CheckResult{{Name: fmt.Sprintf("%s: %s", c.Kind, c.Name), Passed: ok}}
+		}
+	case "resource_argument":
+		if err := need("argument", c.Argument); err != nil {
+			return Rule{}, err
+		}
+		r.Check = func(rc *RuleContext) []CheckResult {
+			if rc.Info == nil {
+				return nil
+			}
+			blk := rc.mod.wrappedResource(rc.Info.ResourceType)
+			ok := blk != nil && blk.Body.Attributes[c.Argument] != nil
+			return []CheckResult{{Name: fmt.Sprintf("main.tf sets %s", c.Argument), Passed: ok}}
+		}
+	default:
+		return Rule{}, fmt.Errorf("custom rule %s: unknown kind %q", c.ID, c.Kind)
+	}
+	if r.Description == "" {
+		r.Description = fmt.Sprintf("Custom %s rule", c.Kind)
+	}
+	return r, nil
+}
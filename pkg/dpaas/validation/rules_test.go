@@ -0,0 +1,191 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func breakCountPattern(t *testing.T, dir string) {
+	t.Helper()
+	mainPath := filepath.Join(dir, "main.tf")
+	raw, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	content := []byte(strings.Replace(string(raw), "local.enabled ? 1 : 0", "1", 1))
+	require.NoError(t, os.WriteFile(mainPath, content, 0644))
+}
+
+// contosoPack loads a template pack whose tag local and required tags differ
+// from the built-in pack's.
+func contosoPack(t *testing.T) *generators.TemplatePack {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"pack.json": `{
+  "name": "contoso",
+  "module_prefix": "ctso-azure-",
+  "source": "git::https://git.contoso.example/terraform/{{.ModuleName}}.git",
+  "label": {"namespace": "ctso", "tenant": "platform", "environment": "dev"},
+  "tags": {"Owner": "platform-team"},
+  "tag_local": "managed_tags",
+  "required_tags": ["managed-by", "repository"],
+  "scenario_defaults": {"location": "West Europe", "resource_group_name": "rg-platform-dev", "subscription_id": "00000000-0000-0000-0000-000000000001"}
+}`,
+		"locals.tf.tmpl": `locals {
+  managed_tags = { "managed-by" = "terraform", "repository" = "{{.Source}}" }
+  tags         = merge(var.tags, local.managed_tags)
+  enabled      = module.this.enabled && var.create_{{.ShortName}}
+}
+`,
+		"readme.md.tmpl":    "# Contoso {{.DisplayName}}\n\n{{.LastReview}}\n\n{{.TerraformDocs}}\n",
+		"changelog.md.tmpl": "# Contoso changelog\n\n## 0.1.0 - {{.Date}}\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	pack, err := generators.LoadTemplatePack(dir)
+	require.NoError(t, err)
+	return pack
+}
+
+func TestRules_ChecksCarryRuleMetadata(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	breakCountPattern(t, dir)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	c := findCheck(t, r, "main.tf uses count pattern")
+	assert.Equal(t, "DPAAS005", c.RuleID)
+	assert.Equal(t, SeverityError, c.Severity)
+	assert.Equal(t, "Resource must use: count = local.enabled ? 1 : 0", c.Message)
+	assert.Equal(t, 1, r.Errors)
+	assert.False(t, r.Passed)
+}
+
+func TestRules_SeverityOverrideOnlyErrorsFail(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	breakCountPattern(t, dir)
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{
+		Rules: &RuleConfig{
+			Severity: map[string]Severity{"DPAAS005": SeverityWarning},
+			Disable:  []string{"SEC002", "SEC004"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, r.Passed)
+	assert.Equal(t, 0, r.Errors)
+	assert.Equal(t, 1, r.Warnings)
+	assert.Less(t, r.PassedChecks, r.TotalChecks)
+}
+
+func TestRules_DisableAndCustomFromModuleConfig(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	breakCountPattern(t, dir)
+
+	cfg := `{
+  "disable": ["DPAAS005"],
+  "custom": [
+    {"id": "TEAM001", "kind": "file_exists", "file": "examples/private_endpoint/main.tf", "severity": "info",
+     "remediation": "Add a private endpoint example"},
+    {"id": "TEAM002", "kind": "output_exists", "name": "default_hostname"}
+  ]
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RuleConfigFileName), []byte(cfg), 0644))
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	for _, c := range r.Checks {
+		assert.NotEqual(t, "DPAAS005", c.RuleID)
+	}
+	example := findCheck(t, r, "File exists: examples/private_endpoint/main.tf")
+	assert.Equal(t, "TEAM001", example.RuleID)
+	assert.False(t, example.Passed)
+	assert.Equal(t, "Add a private endpoint example", example.Message)
+	assert.True(t, findCheck(t, r, "output_exists: default_hostname").Passed)
+
+	assert.True(t, r.Passed, "info findings do not fail the report")
+	assert.Equal(t, 1, r.Infos)
+}
+
+func TestRuleSet_ApplyRejectsUnknownRules(t *testing.T) {
+	assert.Error(t, DefaultRuleSet().Apply(&RuleConfig{Disable: []string{"NOPE"}}))
+	assert.Error(t, DefaultRuleSet().Apply(&RuleConfig{Severity: map[string]Severity{"DPAAS001": "fatal"}}))
+	assert.Error(t, DefaultRuleSet().Apply(&RuleConfig{Custom: []CustomRule{{ID: "X", Kind: "bogus"}}}))
+	assert.Error(t, DefaultRuleSet().Apply(&RuleConfig{Custom: []CustomRule{{ID: "DPAAS001", Kind: "file_exists", File: "a"}}}))
+}
+
+func TestRules_ExtraRules(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{
+		ExtraRules: []Rule{{
+			ID:       "GO001",
+			Severity: SeverityError,
+			Check: func(rc *RuleContext) []CheckResult {
+				return []CheckResult{{Name: "always fails"}}
+			},
+		}},
+	})
+	require.NoError(t, err)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "GO001", findCheck(t, r, "always fails").RuleID)
+}
+
+func TestRules_InputDescriptions(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	assert.True(t, findCheck(t, r, "All variables have descriptions").Passed)
+
+	undocumented := "\nvariable \"sku\" {\n  type = string\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extra_variables.tf"), []byte(undocumented), 0644))
+
+	r, err = ValidateModule(dir, info)
+	require.NoError(t, err)
+	c := findCheck(t, r, "All variables have descriptions")
+	assert.Equal(t, "DPAAS016", c.RuleID)
+	assert.Equal(t, SeverityWarning, c.Severity)
+	assert.Equal(t, "Missing description: sku", c.Message)
+	assert.False(t, c.Passed)
+}
+
+func TestRules_TagConventionsFromPack(t *testing.T) {
+	pack := contosoPack(t)
+	info := schematest.WindowsWebApp()
+	info.ModuleName = pack.ModuleName(info.ShortName)
+	files, err := generators.GenerateModuleWithOptions(info, generators.Options{Scenarios: []string{"default"}, Pack: pack})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	_, err = generators.WriteModule(dir, files)
+	require.NoError(t, err)
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{Pack: pack})
+	require.NoError(t, err)
+	assert.Equal(t, "DPAAS010", findCheck(t, r, "locals.tf: managed_tags defined").RuleID)
+	for _, name := range []string{"locals.tf: managed_tags defined", "locals.tf: managed-by tag present", "locals.tf: repository tag present"} {
+		assert.True(t, findCheck(t, r, name).Passed, name)
+	}
+
+	// the built-in pack's conventions do not hold for this module
+	r, err = ValidateModule(dir, info)
+	require.NoError(t, err)
+	assert.False(t, findCheck(t, r, "locals.tf: dpaas_tags defined").Passed)
+	c := findCheck(t, r, "locals.tf: innersource tag present")
+	assert.False(t, c.Passed)
+	assert.Equal(t, `local.dpaas_tags must contain the "innersource" key`, c.Message)
+}
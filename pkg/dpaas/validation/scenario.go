@@ -0,0 +1,223 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// scenario is one tests/<name> root module and the arguments it passes to
+// the module under test.
+type scenario struct {
+	Name     string // "tests/default"
+	File     string // "tests/default/main.tf"
+	Args     map[string]*hclsyntax.Attribute
+	Disabled bool // the scenario turns the module off, so nothing is created
+}
+
+// scenarioValue is what a schema path evaluates to in one scenario, with the
+// range of the expression it came from (nil when the argument is unset).
+type scenarioValue struct {
+	Value cty.Value
+	Range *hcl.Range
+}
+
+// loadScenarios parses every tests/*/main.tf and picks out the call to the
+// module under test (source "../.."). Scenarios without such a call are skipped.
+func loadScenarios(modulePath string) []scenario {
+	mains, _ := filepath.Glob(filepath.Join(modulePath, "tests", "*", "main.tf"))
+	sort.Strings(mains)
+
+	var out []scenario
+	for _, p := range mains {
+		src, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		name := filepath.ToSlash(filepath.Join("tests", filepath.Base(filepath.Dir(p))))
+		f, diags := hclsyntax.ParseConfig(src, name+"/main.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+		for _, blk := range f.Body.(*hclsyntax.Body).Blocks {
+			if blk.Type != "module" {
+				continue
+			}
+			source, ok := blk.Body.Attributes["source"]
+			if !ok {
+				continue
+			}
+			if v, diags := source.Expr.Value(nil); diags.HasErrors() || v.Type() != cty.String || filepath.Clean(v.AsString()) != filepath.Join("..", "..") {
+				continue
+			}
+			out = append(out, scenario{Name: name, File: name + "/main.tf", Args: blk.Body.Attributes})
+			break
+		}
+	}
+	return out
+}
+
+// scenarios returns the module's test scenarios, marking those that disable
+// the wrapped resource.
+func (rc *RuleContext) scenarios() []scenario {
+	if rc.scenarioCache == nil {
+		scenarios := loadScenarios(rc.ModulePath)
+		for i, sc := range scenarios {
+			for _, flag := range rc.disableFlags() {
+				if a, ok := sc.Args[flag]; ok {
+					if v, _ := a.Expr.Value(nil); v.Type() == cty.Bool && v.IsKnown() && !v.IsNull() && v.False() {
+						scenarios[i].Disabled = true
+					}
+				}
+			}
+		}
+		rc.scenarioCache = &scenarios
+	}
+	return *rc.scenarioCache
+}
+
+func (rc *RuleContext) disableFlags() []string {
+	flags := []string{"enabled"}
+	if rc.Info != nil {
+		flags = append(flags, "create_"+rc.Info.ShortName)
+	}
+	return flags
+}
+
+// valuesAt evaluates what the schema path of a wrapped resource is set to in
+// a scenario: one value per block instance when the path lies under a
+// multi-valued block, and one element per instance when path is itself a
+// block. Values the scenario computes from other resources are unknown.
+func (rc *RuleContext) valuesAt(sc scenario, info *schema.ResourceInfo, path string) []scenarioValue {
+	var entry *CoverageEntry
+	for i, e := range rc.CoverageFor(info).Entries {
+		if e.Path == path {
+			entry = &rc.CoverageFor(info).Entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil
+	}
+
+	if !entry.Wired || entry.Variable == "" {
+		if entry.Depth > 0 {
+			return nil
+		}
+		// a top-level argument set to a literal in the resource itself
+		if blk := rc.mod.wrappedResource(info.ResourceType); blk != nil {
+			if a, ok := blk.Body.Attributes[path]; ok {
+				v, _ := a.Expr.Value(nil)
+				rng := a.Expr.Range()
+				return []scenarioValue{{Value: v, Range: &rng}}
+			}
+		}
+		return []scenarioValue{{Value: cty.NullVal(cty.DynamicPseudoType)}}
+	}
+
+	steps := strings.Split(entry.Variable, ".")
+	root, rng := rc.variableValue(sc, steps[0])
+	vals := []cty.Value{root}
+	for _, step := range steps[1:] {
+		vals = getAttrAll(vals, step)
+	}
+	if entry.Kind == "block" {
+		vals = instances(vals)
+	}
+
+	out := make([]scenarioValue, len(vals))
+	for i, v := range vals {
+		out[i] = scenarioValue{Value: v, Range: rng}
+	}
+	return out
+}
+
+// variableValue returns the value a scenario gives an input variable — its
+// argument, else the variable default, else null — converted to the
+// variable's type constraint with optional attribute defaults applied.
+func (rc *RuleContext) variableValue(sc scenario, name string) (cty.Value, *hcl.Range) {
+	val := cty.NullVal(cty.DynamicPseudoType)
+	var rng *hcl.Range
+
+	decl := rc.mod.Variables[name]
+	if a, ok := sc.Args[name]; ok {
+		val, _ = a.Expr.Value(nil)
+		r := a.Expr.Range()
+		rng = &r
+	} else if decl != nil {
+		if def, ok := decl.Body.Attributes["default"]; ok {
+			val, _ = def.Expr.Value(nil)
+			r := def.Expr.Range()
+			rng = &r
+		}
+	}
+
+	if decl != nil {
+		if typeAttr, ok := decl.Body.Attributes["type"]; ok {
+			ty, defaults, diags := typeexpr.TypeConstraintWithDefaults(typeAttr.Expr)
+			if !diags.HasErrors() {
+				if defaults != nil {
+					val = defaults.Apply(val)
+				}
+				if conv, err := convert.Convert(val, ty); err == nil {
+					val = conv
+				}
+			}
+		}
+	}
+	return val, rng
+}
+
+// getAttrAll reads attribute name from every value, looking through
+// collections of objects. Null parents are dropped: when a block is absent,
+// so are its arguments.
+func getAttrAll(vals []cty.Value, name string) []cty.Value {
+	var out []cty.Value
+	for _, v := range vals {
+		switch {
+		case v.IsNull():
+		case !v.IsKnown():
+			out = append(out, cty.DynamicVal)
+		case v.Type().IsObjectType():
+			if v.Type().HasAttribute(name) {
+				out = append(out, v.GetAttr(name))
+			} else {
+				out = append(out, cty.NullVal(cty.DynamicPseudoType))
+			}
+		case v.CanIterateElements():
+			var elems []cty.Value
+			for it := v.ElementIterator(); it.Next(); {
+				_, e := it.Element()
+				elems = append(elems, e)
+			}
+			out = append(out, getAttrAll(elems, name)...)
+		}
+	}
+	return out
+}
+
+// instances expands block values into one object per block instance.
+func instances(vals []cty.Value) []cty.Value {
+	var out []cty.Value
+	for _, v := range vals {
+		switch {
+		case v.IsNull():
+		case !v.IsKnown() || v.Type().IsObjectType():
+			out = append(out, v)
+		case v.CanIterateElements():
+			for it := v.ElementIterator(); it.Next(); {
+				_, e := it.Element()
+				out = append(out, instances([]cty.Value{e})...)
+			}
+		}
+	}
+	return out
+}
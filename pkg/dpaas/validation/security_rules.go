@@ -0,0 +1,373 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// securitySetting is a security-relevant argument, recognised by name at any
+// nesting level of the resource schema.
+type securitySetting struct {
+	Attr string
+	// Secure reports whether a known, non-null value is acceptable.
+	Secure func(v cty.Value) bool
+	// DefaultSecure reports whether leaving the argument unset is acceptable,
+	// i.e. whether the provider default is the secure value.
+	DefaultSecure bool
+	// Want describes the secure value for messages.
+	Want string
+	// Values are the secure values as generated policies compare them:
+	// exactly, so every spelling the providers use is listed.
+	Values []any
+}
+
+// tlsPolicyValues are the spellings of TLS 1.2 and 1.3 tlsAtLeast12 accepts.
+var tlsPolicyValues = []any{"1.2", "1.3", "TLS1_2", "TLS1_3", "TLS12", "TLS13", "Tls12", "Tls13"}
+
+var publicAccessSettings = []securitySetting{
+	{Attr: "public_network_access_enabled", Secure: isFalse, Want: "false", Values: []any{false}},
+	{Attr: "public_network_access", Secure: stringIn("Disabled"), Want: `"Disabled"`, Values: []any{"Disabled"}},
+	{Attr: "allow_nested_items_to_be_public", Secure: isFalse, Want: "false", Values: []any{false}},
+	{Attr: "anonymous_pull_enabled", Secure: isFalse, DefaultSecure: true, Want: "false", Values: []any{false}},
+}
+
+var transportSettings = []securitySetting{
+	{Attr: "min_tls_version", Secure: tlsAtLeast12, DefaultSecure: true, Want: "TLS 1.2 or later", Values: tlsPolicyValues},
+	{Attr: "minimum_tls_version", Secure: tlsAtLeast12, DefaultSecure: true, Want: "TLS 1.2 or later", Values: tlsPolicyValues},
+	{Attr: "minimal_tls_version", Secure: tlsAtLeast12, DefaultSecure: true, Want: "TLS 1.2 or later", Values: tlsPolicyValues},
+	{Attr: "scm_minimum_tls_version", Secure: tlsAtLeast12, DefaultSecure: true, Want: "TLS 1.2 or later", Values: tlsPolicyValues},
+	{Attr: "ssl_minimal_tls_version_enforced", Secure: tlsAtLeast12, DefaultSecure: true, Want: "TLS 1.2 or later", Values: tlsPolicyValues},
+	{Attr: "https_only", Secure: isTrue, Want: "true", Values: []any{true}},
+	{Attr: "https_traffic_only_enabled", Secure: isTrue, DefaultSecure: true, Want: "true", Values: []any{true}},
+	{Attr: "enable_https_traffic_only", Secure: isTrue, DefaultSecure: true, Want: "true", Values: []any{true}},
+	{Attr: "ssl_enforcement_enabled", Secure: isTrue, Want: "true", Values: []any{true}},
+	{Attr: "non_ssl_port_enabled", Secure: isFalse, DefaultSecure: true, Want: "false", Values: []any{false}},
+	{Attr: "enable_non_ssl_port", Secure: isFalse, DefaultSecure: true, Want: "false", Values: []any{false}},
+}
+
+var encryptionSettings = []securitySetting{
+	{Attr: "infrastructure_encryption_enabled", Secure: isTrue, Want: "true", Values: []any{true}},
+	{Attr: "encryption_at_host_enabled", Secure: isTrue, Want: "true", Values: []any{true}},
+	{Attr: "disk_encryption_enabled", Secure: isTrue, Want: "true", Values: []any{true}},
+	{Attr: "transparent_data_encryption_enabled", Secure: isTrue, DefaultSecure: true, Want: "true", Values: []any{true}},
+}
+
+// securityRules returns the native security rule pack. The rules evaluate
+// the values each tests/ scenario passes to the module, so a finding names the
+// scenario that would deploy the insecure configuration. Scenarios that
+// disable the module are skipped.
+func securityRules() []Rule {
+	return []Rule{
+		{
+			ID:          "SEC001",
+			Severity:    SeverityWarning,
+			Description: "Resources are not reachable from the public internet",
+			Remediation: "Disable public network access by default and in every test scenario; use private endpoints",
+			Check:       settingsCheck("Public network access", publicAccessSettings),
+		},
+		{
+			ID:          "SEC002",
+			Severity:    SeverityWarning,
+			Description: "Traffic is encrypted in transit with TLS 1.2 or later",
+			Remediation: "Enforce HTTPS and a minimum TLS version of 1.2",
+			Check:       settingsCheck("Encryption in transit", transportSettings),
+		},
+		{
+			ID:          "SEC003",
+			Severity:    SeverityWarning,
+			Description: "Data is encrypted at rest",
+			Remediation: "Enable the encryption settings the resource offers",
+			Check:       settingsCheck("Encryption at rest", encryptionSettings),
+		},
+		{
+			ID:          "SEC004",
+			Severity:    SeverityWarning,
+			Description: "The module can send resource logs to a diagnostic destination",
+			Remediation: "Add an azurerm_monitor_diagnostic_setting for the resource, driven by a diagnostic_settings variable",
+			Check: func(rc *RuleContext) []CheckResult {
+				if rc.Info == nil || !supportsDiagnostics(rc.Info.ResourceType) {
+					return nil
+				}
+				ok := false
+				for _, blk := range rc.mod.Resources {
+					if blk.Labels[0] == "azurerm_monitor_diagnostic_setting" {
+						ok = true
+					}
+				}
+				for name := range rc.mod.Variables {
+					if strings.Contains(name, "diagnostic") {
+						ok = true
+					}
+				}
+				return []CheckResult{{Name: "Diagnostic settings configurable", Passed: ok}}
+			},
+		},
+		{
+			ID:          "SEC005",
+			Severity:    SeverityError,
+			Description: "No network security rule allows inbound traffic from any source to all or administrative ports",
+			Remediation: "Restrict source_address_prefix to known ranges and avoid opening ports 22 and 3389 or all ports",
+			Check:       nsgRulesCheck,
+		},
+	}
+}
+
+// settingsCheck evaluates every matching setting of every wrapped resource in
+// every enabled scenario. Values a scenario computes from other resources are
+// unknown and not judged.
+func settingsCheck(label string, settings []securitySetting) func(rc *RuleContext) []CheckResult {
+	return func(rc *RuleContext) []CheckResult {
+		var out []CheckResult
+		for _, info := range rc.Resources {
+			paths := settingPaths(info, settings)
+			if len(paths) == 0 {
+				continue
+			}
+			for _, sc := range rc.scenarios() {
+				if sc.Disabled {
+					continue
+				}
+				for _, p := range paths {
+					for _, sv := range rc.valuesAt(sc, info, p.path) {
+						if !sv.Value.IsWhollyKnown() {
+							continue
+						}
+						c := CheckResult{Name: fmt.Sprintf("%s (%s): %s", label, sc.Name, p.path)}
+						switch {
+						case sv.Value.IsNull() && p.setting.DefaultSecure:
+							c.Passed = true
+						case sv.Value.IsNull():
+							c.Message = fmt.Sprintf("%s is not set, so the provider default applies; set it to %s", p.path, p.setting.Want)
+						default:
+							c.Passed = p.setting.Secure(sv.Value)
+							c.Message = fmt.Sprintf("%s = %s; want %s", p.path, renderValue(sv.Value), p.setting.Want)
+						}
+						if sv.Range != nil {
+							c.File, c.Line = sv.Range.Filename, sv.Range.Start.Line
+						}
+						out = append(out, c)
+					}
+				}
+			}
+		}
+		return out
+	}
+}
+
+type settingPath struct {
+	path    string
+	setting securitySetting
+}
+
+// settingPaths lists the schema paths of info whose attribute name matches a setting.
+func settingPaths(info *schema.ResourceInfo, settings []securitySetting) []settingPath {
+	byName := map[string]securitySetting{}
+	for _, s := range settings {
+		byName[s.Attr] = s
+	}
+	var out []settingPath
+	var walk func(prefix string, attrs []schema.ParsedAttribute, blocks []schema.ParsedBlock)
+	walk = func(prefix string, attrs []schema.ParsedAttribute, blocks []schema.ParsedBlock) {
+		for _, a := range attrs {
+			if s, ok := byName[a.Name]; ok {
+				out = append(out, settingPath{path: prefix + a.Name, setting: s})
+			}
+		}
+		for _, b := range blocks {
+			walk(prefix+b.Name+".", b.Attributes, b.Blocks)
+		}
+	}
+	walk("", info.Attributes, info.Blocks)
+	return out
+}
+
+// nsgRulesCheck flags inbound allow rules open to any source on all ports or
+// on SSH/RDP, both for azurerm_network_security_rule and for the
+// security_rule blocks of azurerm_network_security_group.
+func nsgRulesCheck(rc *RuleContext) []CheckResult {
+	var out []CheckResult
+	for _, info := range rc.Resources {
+		isRule := info.ResourceType == "azurerm_network_security_rule"
+		hasBlock := false
+		for _, b := range info.Blocks {
+			hasBlock = hasBlock || b.Name == "security_rule"
+		}
+		if !isRule && !hasBlock {
+			continue
+		}
+
+		for _, sc := range rc.scenarios() {
+			if sc.Disabled {
+				continue
+			}
+			var rules []cty.Value
+			if isRule {
+				fields := map[string]cty.Value{}
+				for _, f := range nsgRuleFields {
+					if vals := rc.valuesAt(sc, info, f); len(vals) == 1 {
+						fields[f] = vals[0].Value
+					}
+				}
+				rules = append(rules, cty.ObjectVal(fields))
+			} else {
+				for _, sv := range rc.valuesAt(sc, info, "security_rule") {
+					rules = append(rules, sv.Value)
+				}
+			}
+
+			var open []string
+			for _, r := range rules {
+				if reason := wideOpenRule(r); reason != "" {
+					open = append(open, reason)
+				}
+			}
+			out = append(out, CheckResult{
+				Name:    fmt.Sprintf("Network security rules (%s)", sc.Name),
+				Passed:  len(open) == 0,
+				Message: strings.Join(open, "; "),
+				File:    sc.File,
+			})
+		}
+	}
+	return out
+}
+
+var nsgRuleFields = []string{
+	"name", "direction", "access",
+	"source_address_prefix", "source_address_prefixes",
+	"destination_port_range", "destination_port_ranges",
+}
+
+// wideOpenRule describes why an NSG rule object is wide open, or returns "".
+func wideOpenRule(rule cty.Value) string {
+	field := func(name string) cty.Value {
+		if rule.IsNull() || !rule.IsKnown() || !rule.Type().IsObjectType() || !rule.Type().HasAttribute(name) {
+			return cty.NullVal(cty.DynamicPseudoType)
+		}
+		return rule.GetAttr(name)
+	}
+	if !stringIn("Inbound")(field("direction")) || !stringIn("Allow")(field("access")) {
+		return ""
+	}
+
+	anySource := false
+	for _, src := range append([]cty.Value{field("source_address_prefix")}, elements(field("source_address_prefixes"))...) {
+		anySource = anySource || stringIn("*", "0.0.0.0/0", "Internet", "Any")(src)
+	}
+	if !anySource {
+		return ""
+	}
+
+	for _, port := range append([]cty.Value{field("destination_port_range")}, elements(field("destination_port_ranges"))...) {
+		if !port.IsKnown() || port.IsNull() || port.Type() != cty.String {
+			continue
+		}
+		p := port.AsString()
+		name := "rule"
+		if n := field("name"); n.IsKnown() && !n.IsNull() && n.Type() == cty.String {
+			name = fmt.Sprintf("rule %q", n.AsString())
+		}
+		switch {
+		case p == "*" || p == "0-65535":
+			return fmt.Sprintf("%s allows inbound traffic from any source to all ports", name)
+		case portInRange(p, 22) || portInRange(p, 3389):
+			return fmt.Sprintf("%s allows inbound traffic from any source to port range %s", name, p)
+		}
+	}
+	return ""
+}
+
+// supportsDiagnostics excludes resource types that are not log sources
+// themselves: associations, assignments, child rules and settings.
+func supportsDiagnostics(resourceType string) bool {
+	switch resourceType {
+	case "azurerm_resource_group", "azurerm_monitor_diagnostic_setting", "azurerm_network_security_rule":
+		return false
+	}
+	for _, suffix := range []string{"_association", "_assignment", "_rule", "_lock"} {
+		if strings.HasSuffix(resourceType, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// ---------------------------------------------------------------------------
+// Value predicates
+// ---------------------------------------------------------------------------
+
+func isTrue(v cty.Value) bool {
+	return v.Type() == cty.Bool && v.True()
+}
+
+func isFalse(v cty.Value) bool {
+	return v.Type() == cty.Bool && v.False()
+}
+
+func stringIn(allowed ...string) func(v cty.Value) bool {
+	return func(v cty.Value) bool {
+		if !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+			return false
+		}
+		for _, a := range allowed {
+			if strings.EqualFold(v.AsString(), a) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// tlsAtLeast12 accepts the spellings providers use for TLS 1.2 and 1.3
+// ("1.2", "TLS1_2", "TLS12", ...).
+func tlsAtLeast12(v cty.Value) bool {
+	if v.Type() != cty.String {
+		return false
+	}
+	s := strings.NewReplacer("TLS", "", "tls", "", "_", ".", "v", "").Replace(v.AsString())
+	switch s {
+	case "1.2", "12", "1.3", "13":
+		return true
+	}
+	return false
+}
+
+func elements(v cty.Value) []cty.Value {
+	if !v.IsKnown() || v.IsNull() || !v.CanIterateElements() {
+		return nil
+	}
+	var out []cty.Value
+	for it := v.ElementIterator(); it.Next(); {
+		_, e := it.Element()
+		out = append(out, e)
+	}
+	return out
+}
+
+// portInRange reports whether port falls in a port or "low-high" range.
+func portInRange(rng string, port int) bool {
+	var lo, hi int
+	if n, _ := fmt.Sscanf(rng, "%d-%d", &lo, &hi); n == 2 {
+		return lo <= port && port <= hi
+	}
+	if n, _ := fmt.Sscanf(rng, "%d", &lo); n == 1 {
+		return lo == port
+	}
+	return false
+}
+
+func renderValue(v cty.Value) string {
+	if v.Type() == cty.String {
+		return fmt.Sprintf("%q", v.AsString())
+	}
+	if v.Type() == cty.Bool {
+		return fmt.Sprintf("%t", v.True())
+	}
+	if v.Type() == cty.Number {
+		return v.AsBigFloat().Text('f', -1)
+	}
+	return v.GoString()
+}
@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func writeScenario(t *testing.T, dir, name, args string) {
+	t.Helper()
+	scenarioDir := filepath.Join(dir, "tests", name)
+	require.NoError(t, os.MkdirAll(scenarioDir, 0755))
+	main := "module \"this\" {\n  source = \"../..\"\n" + args + "}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(scenarioDir, "main.tf"), []byte(main), 0644))
+}
+
+func TestSecurityRules_EvaluateScenarios(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	writeScenario(t, dir, "insecure", "  https_only = false\n")
+	writeScenario(t, dir, "secure", "  https_only = true\n")
+	writeScenario(t, dir, "off", "  enabled    = false\n  https_only = false\n")
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	insecure := findCheck(t, r, "Encryption in transit (tests/insecure): https_only")
+	assert.False(t, insecure.Passed)
+	assert.Equal(t, "SEC002", insecure.RuleID)
+	assert.Equal(t, SeverityWarning, insecure.Severity)
+	assert.Equal(t, "https_only = false; want true", insecure.Message)
+	assert.Equal(t, "tests/insecure/main.tf", insecure.File)
+	assert.Equal(t, 3, insecure.Line)
+
+	assert.True(t, findCheck(t, r, "Encryption in transit (tests/secure): https_only").Passed)
+	// unset, and the provider default allows plain HTTP
+	assert.False(t, findCheck(t, r, "Encryption in transit (tests/default): https_only").Passed)
+
+	for _, c := range r.Checks {
+		assert.NotContains(t, c.Name, "tests/off", "disabled scenarios are skipped")
+	}
+	assert.False(t, findCheck(t, r, "Diagnostic settings configurable").Passed)
+	assert.True(t, r.Passed, "security warnings do not fail the report")
+}
+
+func TestSecurityRules_WideOpenNSGRule(t *testing.T) {
+	info := &schema.ResourceInfo{
+		ResourceType: "azurerm_network_security_group",
+		ShortName:    "network_security_group",
+		ModuleName:   "expn-tf-azure-network-security-group",
+		DisplayName:  "Network Security Group",
+		Attributes: []schema.ParsedAttribute{
+			{Name: "location", TFType: "string", Required: true},
+			{Name: "name", TFType: "string", Required: true},
+			{Name: "resource_group_name", TFType: "string", Required: true},
+		},
+		Blocks: []schema.ParsedBlock{
+			{
+				Name:        "security_rule",
+				NestingMode: "set",
+				Attributes: []schema.ParsedAttribute{
+					{Name: "access", TFType: "string", Required: true},
+					{Name: "destination_port_range", TFType: "string", Optional: true},
+					{Name: "direction", TFType: "string", Required: true},
+					{Name: "name", TFType: "string", Required: true},
+					{Name: "source_address_prefix", TFType: "string", Optional: true},
+				},
+			},
+		},
+	}
+	dir := t.TempDir()
+	_, err := generators.WriteModule(dir, generators.GenerateModule(info, []string{"default"}))
+	require.NoError(t, err)
+	writeScenario(t, dir, "rdp", `  security_rule = [{
+    name                   = "rdp"
+    direction              = "Inbound"
+    access                 = "Allow"
+    source_address_prefix  = "*"
+    destination_port_range = "3000-4000"
+  }]
+`)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	c := findCheck(t, r, "Network security rules (tests/rdp)")
+	assert.False(t, c.Passed)
+	assert.Equal(t, "SEC005", c.RuleID)
+	assert.Equal(t, `rule "rdp" allows inbound traffic from any source to port range 3000-4000`, c.Message)
+	assert.True(t, findCheck(t, r, "Network security rules (tests/default)").Passed)
+	assert.False(t, r.Passed)
+}
+
+func TestWideOpenRule(t *testing.T) {
+	rule := func(source, port string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"direction":              cty.StringVal("Inbound"),
+			"access":                 cty.StringVal("Allow"),
+			"source_address_prefix":  cty.StringVal(source),
+			"destination_port_range": cty.StringVal(port),
+		})
+	}
+	assert.NotEmpty(t, wideOpenRule(rule("*", "*")))
+	assert.NotEmpty(t, wideOpenRule(rule("Internet", "22")))
+	assert.NotEmpty(t, wideOpenRule(rule("0.0.0.0/0", "0-65535")))
+	assert.Empty(t, wideOpenRule(rule("10.0.0.0/8", "22")))
+	assert.Empty(t, wideOpenRule(rule("*", "443")))
+	assert.Empty(t, wideOpenRule(cty.NullVal(cty.DynamicPseudoType)))
+}
+
+func TestUpdateReadmeBenchmarks(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	require.NoError(t, UpdateReadmeBenchmarks(dir, r, "2026-01-02"))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	readme := string(raw)
+	assert.Contains(t, readme, "**Last Module Review**: 2026-01-02\n")
+	assert.Contains(t, readme, "[![DPaaS standards](https://img.shields.io/badge/DPaaS_standards-passed-green)]() | 2026-01-02 |")
+	assert.Contains(t, readme, "[![DPaaS security](https://img.shields.io/badge/DPaaS_security-2_findings-yellow)]()")
+	assert.NotContains(t, readme, "terraform validate", "the terraform stage did not run")
+	assert.NotContains(t, readme, "tflint")
+	assert.NotContains(t, readme, "Not yet scanned")
+}
@@ -0,0 +1,313 @@
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultTerraformTimeout = 5 * time.Minute
+
+// TerraformOptions configures the opt-in Terraform CLI stage of validation.
+type TerraformOptions struct {
+	// PluginMirrorDir is a filesystem provider mirror (the layout written by
+	// `terraform providers mirror`). When set, init installs providers from it
+	// only, so the stage works offline.
+	PluginMirrorDir string
+	// Binary is the terraform executable; defaults to "terraform" on PATH.
+	Binary string
+	// Timeout bounds each terraform invocation; defaults to five minutes.
+	Timeout time.Duration
+}
+
+// tfDiagnostic is the diagnostic shape shared by `validate -json` and the
+// machine-readable UI of `test -json`.
+type tfDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Range    *struct {
+		Filename string `json:"filename"`
+		Start    struct {
+			Line int `json:"line"`
+		} `json:"start"`
+	} `json:"range"`
+}
+
+type tfValidateOutput struct {
+	Valid       bool           `json:"valid"`
+	Diagnostics []tfDiagnostic `json:"diagnostics"`
+}
+
+type tfTestMessage struct {
+	Type        string        `json:"type"`
+	Diagnostic  *tfDiagnostic `json:"diagnostic"`
+	TestSummary *struct {
+		Status  string `json:"status"`
+		Passed  int    `json:"passed"`
+		Failed  int    `json:"failed"`
+		Errored int    `json:"errored"`
+		Skipped int    `json:"skipped"`
+	} `json:"test_summary"`
+}
+
+// ---------------------------------------------------------------------------
+
+// terraformResults holds the checks produced by each CLI stage.
+type terraformResults struct {
+	Init     []CheckResult
+	Validate []CheckResult
+	Test     []CheckResult
+}
+
+// runTerraformStage runs init and validate in every tests/<scenario> root
+// module and terraform test against the module's tests/*.tftest.hcl files,
+// recording each outcome and diagnostic as a check. Modules without
+// .tftest.hcl files are tested scenario by scenario instead.
+func runTerraformStage(modulePath string, opts *TerraformOptions) *terraformResults {
+	tr := &terraformResults{}
+
+	binary := opts.Binary
+	if binary == "" {
+		binary = "terraform"
+	}
+	if _, err := exec.LookPath(binary); err != nil {
+		tr.Init = append(tr.Init, CheckResult{Name: "terraform CLI available", Message: fmt.Sprintf("%s not found on PATH", binary)})
+		return tr
+	}
+
+	env := append(os.Environ(), "TF_IN_AUTOMATION=1", "TF_INPUT=0")
+	if opts.PluginMirrorDir != "" {
+		if _, err := os.Stat(opts.PluginMirrorDir); err != nil {
+			tr.Init = append(tr.Init, CheckResult{Name: "terraform provider mirror available", Message: err.Error()})
+			return tr
+		}
+		cliConfig, err := writeMirrorCLIConfig(opts.PluginMirrorDir)
+		if err != nil {
+			tr.Init = append(tr.Init, CheckResult{Name: "terraform provider mirror available", Message: err.Error()})
+			return tr
+		}
+		defer os.Remove(cliConfig)
+		env = append(env, "TF_CLI_CONFIG_FILE="+cliConfig)
+	}
+
+	scenarios, _ := filepath.Glob(filepath.Join(modulePath, "tests", "*", "main.tf"))
+	sort.Strings(scenarios)
+	tfTests, _ := filepath.Glob(filepath.Join(modulePath, "tests", "*.tftest.hcl"))
+
+	for _, mainTf := range scenarios {
+		dir := filepath.Dir(mainTf)
+		rel := filepath.ToSlash(filepath.Join("tests", filepath.Base(dir)))
+		run := tfRunner{binary: binary, dir: dir, env: env, timeout: opts.Timeout}
+		cleanup := run.preserveWorkdir()
+
+		out, err := run.exec("init", "-backend=false", "-input=false", "-no-color")
+		tr.Init = append(tr.Init, CheckResult{Name: fmt.Sprintf("terraform init (%s)", rel), Passed: err == nil, Message: lastLines(out, 10)})
+		if err == nil {
+			out, err = run.exec("validate", "-json", "-no-color")
+			tr.Validate = append(tr.Validate, validateResults(rel, out, err)...)
+
+			// scenarios are only tested one by one when the module has no
+			// .tftest.hcl files of its own
+			if len(tfTests) == 0 {
+				out, err = run.exec("test", "-json", "-no-color")
+				tr.Test = append(tr.Test, testResults(rel, rel, out, err)...)
+			}
+		}
+		cleanup()
+	}
+
+	// tests/*.tftest.hcl run from the module root, where terraform test
+	// finds them by default
+	if len(tfTests) > 0 {
+		run := tfRunner{binary: binary, dir: modulePath, env: env, timeout: opts.Timeout}
+		cleanup := run.preserveWorkdir()
+
+		out, err := run.exec("init", "-backend=false", "-input=false", "-no-color")
+		tr.Init = append(tr.Init, CheckResult{Name: "terraform init (module)", Passed: err == nil, Message: lastLines(out, 10)})
+		if err == nil {
+			out, err = run.exec("test", "-json", "-no-color")
+			tr.Test = append(tr.Test, testResults("module", ".", out, err)...)
+		}
+		cleanup()
+	}
+	return tr
+}
+
+// validateResults turns `terraform validate -json` output into checks: one
+// per diagnostic, plus an overall result when there are no errors.
+func validateResults(scenario string, out []byte, runErr error) []CheckResult {
+	name := fmt.Sprintf("terraform validate (%s)", scenario)
+	var v tfValidateOutput
+	if err := json.Unmarshal(out, &v); err != nil {
+		return []CheckResult{{Name: name, Message: firstNonEmpty(errString(runErr), lastLines(out, 10))}}
+	}
+	checks, errors := diagnosticChecks("terraform validate", scenario, scenario, v.Diagnostics)
+	if errors == 0 {
+		checks = append(checks, CheckResult{Name: name, Passed: v.Valid})
+	}
+	return checks
+}
+
+// testResults turns the JSON-lines UI of `terraform test -json` run in dir
+// into checks labelled with label.
+func testResults(label, dir string, out []byte, runErr error) []CheckResult {
+	var diags []tfDiagnostic
+	summary := ""
+	passed := runErr == nil
+
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var msg tfTestMessage
+		if json.Unmarshal(sc.Bytes(), &msg) != nil {
+			continue
+		}
+		switch {
+		case msg.Type == "diagnostic" && msg.Diagnostic != nil:
+			diags = append(diags, *msg.Diagnostic)
+		case msg.Type == "test_summary" && msg.TestSummary != nil:
+			s := msg.TestSummary
+			summary = fmt.Sprintf("%s: %d passed, %d failed, %d errored, %d skipped", s.Status, s.Passed, s.Failed, s.Errored, s.Skipped)
+			passed = passed && s.Failed == 0 && s.Errored == 0
+		}
+	}
+
+	checks, _ := diagnosticChecks("terraform test", label, dir, diags)
+	return append(checks, CheckResult{
+		Name:    fmt.Sprintf("terraform test (%s)", label),
+		Passed:  passed,
+		Message: firstNonEmpty(summary, errString(runErr)),
+	})
+}
+
+// diagnosticChecks records one failed check per diagnostic, resolving file
+// paths reported relative to dir against the module root. Warning
+// diagnostics carry warning severity. It also returns the number of error
+// diagnostics.
+func diagnosticChecks(stage, label, dir string, diags []tfDiagnostic) ([]CheckResult, int) {
+	var checks []CheckResult
+	errors := 0
+	for _, d := range diags {
+		c := CheckResult{
+			Name:    fmt.Sprintf("%s (%s): %s", stage, label, d.Summary),
+			Message: strings.TrimSpace(d.Detail),
+		}
+		if d.Severity == "error" {
+			errors++
+		} else {
+			c.Severity = SeverityWarning
+		}
+		if d.Range != nil {
+			c.File = filepath.ToSlash(filepath.Clean(filepath.Join(dir, d.Range.Filename)))
+			c.Line = d.Range.Start.Line
+		}
+		checks = append(checks, c)
+	}
+	return checks, errors
+}
+
+// ---------------------------------------------------------------------------
+
+type tfRunner struct {
+	binary  string
+	dir     string
+	env     []string
+	timeout time.Duration
+}
+
+func (t tfRunner) exec(args ...string) ([]byte, error) {
+	timeout := t.timeout
+	if timeout == 0 {
+		timeout = defaultTerraformTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, t.binary, args...)
+	cmd.Dir = t.dir
+	cmd.Env = t.env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil && stdout.Len() == 0 {
+		return stderr.Bytes(), err
+	}
+	return stdout.Bytes(), err
+}
+
+// preserveWorkdir returns a func that removes the .terraform directory and
+// lock file created by init, unless they were already present beforehand.
+func (t tfRunner) preserveWorkdir() func() {
+	var created []string
+	for _, name := range []string{".terraform", ".terraform.lock.hcl"} {
+		if !fileExists(filepath.Join(t.dir, name)) {
+			created = append(created, filepath.Join(t.dir, name))
+		}
+	}
+	return func() {
+		for _, p := range created {
+			os.RemoveAll(p)
+		}
+	}
+}
+
+// writeMirrorCLIConfig writes a temporary CLI configuration that installs
+// every provider from the given filesystem mirror and nowhere else.
+func writeMirrorCLIConfig(mirrorDir string) (string, error) {
+	abs, err := filepath.Abs(mirrorDir)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "dpaas-terraformrc-*")
+	if err != nil {
+		return "", fmt.Errorf("create CLI config: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, `provider_installation {
+  filesystem_mirror {
+    path    = %q
+    include = ["*/*/*"]
+  }
+}
+`, filepath.ToSlash(abs))
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("write CLI config: %w", err)
+	}
+	return f.Name(), nil
+}
+
+func lastLines(out []byte, n int) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTerraform writes a shell script that answers init, validate -json and
+// test -json with canned output, standing in for the real CLI.
+func fakeTerraform(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform script requires a POSIX shell")
+	}
+	script := `#!/bin/sh
+case "$1" in
+  init)
+    [ -n "$TF_CLI_CONFIG_FILE" ] && grep -q filesystem_mirror "$TF_CLI_CONFIG_FILE" || exit 1
+    mkdir -p .terraform
+    echo "Terraform has been successfully initialized!" ;;
+  validate)
+    cat <<'EOF'
+{"format_version":"1.0","valid":false,"error_count":1,"warning_count":0,"diagnostics":[{"severity":"error","summary":"Unsupported argument","detail":"An argument named \"bogus\" is not expected here.","range":{"filename":"../../main.tf","start":{"line":7,"column":3,"byte":0},"end":{"line":7,"column":8,"byte":5}}}]}
+EOF
+    exit 1 ;;
+  test)
+    echo '{"@level":"info","@message":"Found 0 files and 0 run blocks","type":"test_abstract"}'
+    echo '{"@level":"info","@message":"Success! 0 passed, 0 failed.","type":"test_summary","test_summary":{"status":"pending","passed":0,"failed":0,"errored":0,"skipped":0}}' ;;
+esac
+`
+	path := filepath.Join(t.TempDir(), "terraform")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestValidateModuleWithOptions_TerraformStage(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	mirror := t.TempDir()
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{
+		Terraform: &TerraformOptions{PluginMirrorDir: mirror, Binary: fakeTerraform(t)},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, findCheck(t, r, "terraform init (tests/default)").Passed)
+	assert.True(t, findCheck(t, r, "terraform init (module)").Passed)
+	assert.True(t, findCheck(t, r, "terraform test (module)").Passed)
+
+	diag := findCheck(t, r, "terraform validate (tests/default): Unsupported argument")
+	assert.False(t, diag.Passed)
+	assert.Equal(t, "main.tf", diag.File)
+	assert.Equal(t, 7, diag.Line)
+	assert.False(t, r.Passed)
+
+	assert.NoDirExists(t, filepath.Join(dir, "tests", "default", ".terraform"),
+		"working directory created by init is cleaned up")
+	assert.NoDirExists(t, filepath.Join(dir, ".terraform"))
+}
+
+func TestValidateModuleWithOptions_MissingMirror(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{
+		Terraform: &TerraformOptions{PluginMirrorDir: filepath.Join(dir, "nope"), Binary: fakeTerraform(t)},
+	})
+	require.NoError(t, err)
+	assert.False(t, findCheck(t, r, "terraform provider mirror available").Passed)
+}
+
+func TestValidateModuleWithOptions_TestsScenariosWithoutTfTests(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	tfTests, err := filepath.Glob(filepath.Join(dir, "tests", "*.tftest.hcl"))
+	require.NoError(t, err)
+	require.NotEmpty(t, tfTests, "generated modules ship .tftest.hcl files")
+	for _, p := range tfTests {
+		require.NoError(t, os.Remove(p))
+	}
+
+	r, err := ValidateModuleWithOptions(dir, info, Options{
+		Terraform: &TerraformOptions{PluginMirrorDir: t.TempDir(), Binary: fakeTerraform(t)},
+	})
+	require.NoError(t, err)
+	assert.True(t, findCheck(t, r, "terraform test (tests/default)").Passed)
+	for _, c := range r.Checks {
+		assert.NotEqual(t, "terraform init (module)", c.Name)
+	}
+}
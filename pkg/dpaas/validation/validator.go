@@ -3,173 +3,240 @@ package validation
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
+	"sort"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // ValidationReport is the top-level result returned to the caller.
+// Passed is false only when an error-severity check fails; failing warning
+// and info checks are counted but do not fail the report.
 type ValidationReport struct {
-	Passed         bool
-	TotalChecks    int
-	PassedChecks   int
-	Checks         []CheckResult
-	CoverageReport *CoverageReport
+	Passed         bool            `json:"passed"`
+	TotalChecks    int             `json:"total_checks"`
+	PassedChecks   int             `json:"passed_checks"`
+	Errors         int             `json:"errors"`
+	Warnings       int             `json:"warnings"`
+	Infos          int             `json:"infos"`
+	Checks         []CheckResult   `json:"checks"`
+	Rules          []RuleSummary   `json:"rules"`
+	CoverageReport *CoverageReport `json:"coverage,omitempty"` // primary resource
+
+	// ResourceCoverage has one entry per wrapped resource, primary first.
+	ResourceCoverage []ResourceCoverage `json:"resource_coverage,omitempty"`
+
+	// Fix records the fixes applied before validating, when requested.
+	Fix *FixResult `json:"fix,omitempty"`
 }
 
-// CheckResult is one named assertion.
+// ResourceCoverage is the argument coverage of one wrapped resource.
+type ResourceCoverage struct {
+	ResourceType string          `json:"resource_type"`
+	Address      string          `json:"address,omitempty"` // e.g. "azurerm_subnet.this"; empty when not declared
+	Coverage     *CoverageReport `json:"coverage"`
+}
+
+// CheckResult is one named assertion made by a rule. File and Line locate the
+// finding when it comes from a diagnostic (module-relative path, 1-based line).
 type CheckResult struct {
-	Name    string
-	Passed  bool
-	Message string
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Message  string   `json:"message,omitempty"`
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
 }
 
-// CoverageReport quantifies how many schema items appeared in the generated module.
-type CoverageReport struct {
-	SchemaAttrCount     int
-	GeneratedAttrCount  int
-	SchemaBlockCount    int
-	GeneratedBlockCount int
-	MissingAttrs        []string
-	MissingBlocks       []string
-	CoveragePercent     float64
+// RuleSummary describes one rule that ran, for report consumers such as SARIF.
+type RuleSummary struct {
+	ID          string   `json:"id"`
+	Severity    Severity `json:"severity"`
+	Description string   `json:"description"`
+	Remediation string   `json:"remediation,omitempty"`
+}
+
+// Options selects the optional validation stages and rule configuration.
+type Options struct {
+	// Terraform enables running terraform init, validate and test against every
+	// tests/* scenario. Nil skips the stage.
+	Terraform *TerraformOptions
+	// Rules customises the rule set. When nil, the module's own
+	// .dpaas-rules.json is used if present.
+	Rules *RuleConfig
+	// ExtraRules are registered after the built-in and configured rules.
+	ExtraRules []Rule
+	// Pack supplies the tag conventions the module is checked against; nil
+	// selects the built-in pack.
+	Pack *generators.TemplatePack
 }
 
 // ---------------------------------------------------------------------------
 
 // ValidateModule runs every DPaaS standard check against a module directory.
+// Checks operate on a real HCL parse of the module's .tf files.
 func ValidateModule(modulePath string, info *schema.ResourceInfo) (*ValidationReport, error) {
-	r := &ValidationReport{}
+	return ValidateModuleWithOptions(modulePath, info, Options{})
+}
 
-	// ── 1. required-file existence ──────────────────────────────────────────
-	for _, f := range []string{
-		"context.tf", "locals.tf", "main.tf", "outputs.tf",
-		"variables.tf", "versions.tf", "README.md", "CHANGELOG.md",
-		".pre-commit-config.yaml", ".gitignore",
-	} {
-		r.addCheck(fmt.Sprintf("File exists: %s", f), fileExists(filepath.Join(modulePath, f)), "")
-	}
-
-	// ── 2. tests/ has at least one scenario ────────────────────────────────
-	testMains, _ := filepath.Glob(filepath.Join(modulePath, "tests", "*", "main.tf"))
-	r.addCheck("tests/ has at least one scenario", len(testMains) > 0, "")
-
-	// ── 3. main.tf structure ────────────────────────────────────────────────
-	if mainTf, err := os.ReadFile(filepath.Join(modulePath, "main.tf")); err == nil {
-		content := string(mainTf)
-		r.addCheck("main.tf uses count pattern",
-			containsCountPattern(content),
-			"Resource must use: count = local.enabled ? 1 : 0")
-		r.addCheck("main.tf resource named 'this'",
-			strings.Contains(content, fmt.Sprintf(`"%s" "this"`, info.ResourceType)),
-			"Resource block must be named 'this'")
-		r.addCheck("main.tf references local.tags",
-			strings.Contains(content, "local.tags"),
-			"Tags attribute must reference local.tags")
-	}
-
-	// ── 4. variables.tf null-label markers ──────────────────────────────────
-	if varsTf, err := os.ReadFile(filepath.Join(modulePath, "variables.tf")); err == nil {
-		content := string(varsTf)
-		r.addCheck("variables.tf: Start of null-label marker",
-			strings.Contains(content, "Start of null-label Variables"), "")
-		r.addCheck("variables.tf: End of null-label marker",
-			strings.Contains(content, "End of null-label Variables"), "")
-		r.addCheck("variables.tf: create_ flag present",
-			strings.Contains(content, fmt.Sprintf("create_%s", info.ShortName)), "")
-		r.addCheck("variables.tf: resource name variable present",
-			strings.Contains(content, fmt.Sprintf("%s_name", info.ShortName)), "")
-	}
-
-	// ── 5. locals.tf DPaaS tags ─────────────────────────────────────────────
-	if localsTf, err := os.ReadFile(filepath.Join(modulePath, "locals.tf")); err == nil {
-		content := string(localsTf)
-		r.addCheck("locals.tf: dpaas_tags defined",
-			strings.Contains(content, "dpaas_tags"), "")
-		r.addCheck("locals.tf: innersource tag present",
-			strings.Contains(content, `"innersource"`), "")
-		r.addCheck("locals.tf: enabled uses create_ flag",
-			strings.Contains(content, fmt.Sprintf("create_%s", info.ShortName)), "")
-	}
-
-	// ── 6. argument coverage ────────────────────────────────────────────────
+// ValidateModuleWithOptions is ValidateModule with the optional stages in opts.
+func ValidateModuleWithOptions(modulePath string, info *schema.ResourceInfo, opts Options) (*ValidationReport, error) {
+	var infos []*schema.ResourceInfo
 	if info != nil {
-		cr := checkCoverage(modulePath, info)
-		r.CoverageReport = cr
-		r.addCheck(
-			fmt.Sprintf("Argument coverage: %.0f%%", cr.CoveragePercent),
-			cr.CoveragePercent == 100,
-			fmt.Sprintf("Missing attrs: %v  Missing blocks: %v", cr.MissingAttrs, cr.MissingBlocks),
-		)
+		infos = []*schema.ResourceInfo{info}
 	}
-
-	r.Passed = r.PassedChecks == r.TotalChecks
-	return r, nil
+	return ValidateResources(modulePath, infos, opts)
 }
 
-// ---------------------------------------------------------------------------
+// ValidateResources validates a module that wraps one or more resources. The
+// first entry is the primary resource the DPaaS naming and count conventions
+// apply to; argument coverage is reported for every entry. Each resource is
+// matched to its "this" block, or to the first block of its type.
+func ValidateResources(modulePath string, infos []*schema.ResourceInfo, opts Options) (*ValidationReport, error) {
+	mod, err := parseModule(modulePath)
+	if err != nil {
+		return nil, err
+	}
 
-func (r *ValidationReport) addCheck(name string, passed bool, message string) {
-	r.TotalChecks++
-	if passed {
-		r.PassedChecks++
+	rs, err := buildRuleSet(modulePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Pack == nil {
+		opts.Pack = generators.DefaultTemplatePack()
+	}
+
+	rc := &RuleContext{ModulePath: modulePath, Resources: infos, Options: opts, mod: mod}
+	if len(infos) > 0 {
+		rc.Info = infos[0]
 	}
-	r.Checks = append(r.Checks, CheckResult{Name: name, Passed: passed, Message: message})
+	r := &ValidationReport{}
+	rs.run(rc, r)
+	r.CoverageReport = rc.Coverage()
+	for _, info := range infos {
+		rcov := ResourceCoverage{ResourceType: info.ResourceType, Coverage: rc.CoverageFor(info)}
+		if blk := mod.wrappedResource(info.ResourceType); blk != nil {
+			rcov.Address = blk.Labels[0] + "." + blk.Labels[1]
+		}
+		r.ResourceCoverage = append(r.ResourceCoverage, rcov)
+	}
+	r.Passed = r.Errors == 0
+	return r, nil
 }
 
-func checkCoverage(modulePath string, info *schema.ResourceInfo) *CoverageReport {
-	cr := &CoverageReport{}
+// DetectResourceTypes lists the managed resource types a module declares in
+// its top-level .tf files: types declared as "this" first, then the rest in
+// file order. It is used when the caller does not name the resource type.
+func DetectResourceTypes(modulePath string) ([]string, error) {
+	mod, err := parseModule(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	return mod.resourceTypes(), nil
+}
 
-	mainRaw, err1 := os.ReadFile(filepath.Join(modulePath, "main.tf"))
-	varsRaw, err2 := os.ReadFile(filepath.Join(modulePath, "variables.tf"))
-	if err1 != nil || err2 != nil {
-		return cr
+// ReadAzAPIResource reads the azapi_resource a module wraps from the Azure
+// REST API spec it was generated from, so its coverage can be checked: there
+// is no provider schema for azapi_resource bodies. The resource type and API
+// version come from the type argument of the module's azapi_resource block.
+func ReadAzAPIResource(modulePath string, spec []byte) (*schema.AzAPIResource, error) {
+	mod, err := parseModule(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	blk := mod.wrappedResource(schema.AzAPIResourceType)
+	if blk == nil {
+		return nil, fmt.Errorf("%s declares no %s", modulePath, schema.AzAPIResourceType)
+	}
+	address := blk.Labels[0] + "." + blk.Labels[1]
+	attr, ok := blk.Body.Attributes["type"]
+	if !ok {
+		return nil, fmt.Errorf("%s has no type argument", address)
+	}
+	v, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || v.Type() != cty.String || v.IsNull() {
+		return nil, fmt.Errorf("%s: type must be a literal string such as \"Microsoft.App/containerApps@2024-03-01\"", address)
 	}
-	mainContent := string(mainRaw)
-	varsContent := string(varsRaw)
+	res, err := schema.ParseRESTAPISpec(spec, v.AsString())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", address, err)
+	}
+	return res, nil
+}
 
-	for _, a := range info.Attributes {
-		if a.Name == "id" {
-			continue
-		}
-		cr.SchemaAttrCount++
-		if strings.Contains(varsContent, fmt.Sprintf(`variable "%s"`, a.Name)) {
-			cr.GeneratedAttrCount++
-		} else {
-			cr.MissingAttrs = append(cr.MissingAttrs, a.Name)
+// buildRuleSet assembles the built-in rules, the configured overrides and any
+// extra rules supplied by the caller.
+func buildRuleSet(modulePath string, opts Options) (*RuleSet, error) {
+	rs := DefaultRuleSet()
+
+	cfg := opts.Rules
+	if cfg == nil {
+		var err error
+		if cfg, err = loadModuleRuleConfig(modulePath); err != nil {
+			return nil, err
 		}
 	}
-
-	for _, blk := range info.Blocks {
-		cr.SchemaBlockCount++
-		hasDynamic := strings.Contains(mainContent, fmt.Sprintf(`dynamic "%s"`, blk.Name))
-		hasVar := strings.Contains(varsContent, fmt.Sprintf(`variable "%s"`, blk.Name))
-		if hasDynamic && hasVar {
-			cr.GeneratedBlockCount++
-		} else {
-			cr.MissingBlocks = append(cr.MissingBlocks, blk.Name)
+	if err := rs.Apply(cfg); err != nil {
+		return nil, err
+	}
+	for _, rule := range opts.ExtraRules {
+		if err := rs.Register(rule); err != nil {
+			return nil, err
 		}
 	}
+	return rs, nil
+}
+
+// ---------------------------------------------------------------------------
 
-	total := cr.SchemaAttrCount + cr.SchemaBlockCount
-	matched := cr.GeneratedAttrCount + cr.GeneratedBlockCount
-	if total > 0 {
-		cr.CoveragePercent = float64(matched) / float64(total) * 100
+func (r *ValidationReport) add(c CheckResult) {
+	r.TotalChecks++
+	if c.Passed {
+		r.PassedChecks++
+	} else {
+		switch c.Severity {
+		case SeverityWarning:
+			r.Warnings++
+		case SeverityInfo:
+			r.Infos++
+		default:
+			r.Errors++
+		}
 	}
-	return cr
+	r.Checks = append(r.Checks, c)
 }
 
-// containsCountPattern checks for "count = local.enabled ? 1 : 0" regardless of whitespace alignment.
-func containsCountPattern(content string) bool {
-	for _, line := range strings.Split(content, "\n") {
-		trimmed := strings.TrimSpace(line)
-		normalized := strings.Join(strings.Fields(trimmed), " ")
-		if normalized == "count = local.enabled ? 1 : 0" {
-			return true
+// undeclaredVariables lists var.* references that have no matching variable block.
+func (m *parsedModule) undeclaredVariables() []string {
+	seen := map[string]bool{}
+	var missing []string
+	for _, f := range m.Files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
 		}
+		_ = hclsyntax.VisitAll(body, func(n hclsyntax.Node) hcl.Diagnostics {
+			trav, ok := n.(*hclsyntax.ScopeTraversalExpr)
+			if !ok {
+				return nil
+			}
+			root, name := traversalHead(trav.Traversal)
+			if root != "var" || name == "" || seen[name] {
+				return nil
+			}
+			seen[name] = true
+			if _, declared := m.Variables[name]; !declared {
+				missing = append(missing, name)
+			}
+			return nil
+		})
 	}
-	return false
+	sort.Strings(missing)
+	return missing
 }
 
 func fileExists(path string) bool {
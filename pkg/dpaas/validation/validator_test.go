@@ -0,0 +1,332 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema/schematest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestModule(t *testing.T, info *schema.ResourceInfo) string {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := generators.WriteModule(dir, generators.GenerateModule(info, []string{"default"}))
+	require.NoError(t, err)
+	return dir
+}
+
+func findCheck(t *testing.T, r *ValidationReport, name string) CheckResult {
+	t.Helper()
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("check %q not found", name)
+	return CheckResult{}
+}
+
+func TestValidateModule_GeneratedModulePasses(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	for _, c := range r.Checks {
+		if strings.HasPrefix(c.RuleID, "SEC") {
+			// security findings depend on the scenario values and are warnings
+			assert.Equal(t, SeverityWarning, c.Severity, c.Name)
+			continue
+		}
+		assert.True(t, c.Passed, "%s: %s", c.Name, c.Message)
+	}
+	assert.True(t, r.Passed)
+	require.NotNil(t, r.CoverageReport)
+	assert.Equal(t, 100.0, r.CoverageReport.CoveragePercent)
+}
+
+func TestValidateModule_RenamedVariableIsCovered(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	assert.NotContains(t, r.CoverageReport.MissingAttrs, "enabled",
+		"enabled is wired through var.windows_web_app_enabled")
+}
+
+func TestValidateModule_CommentedOutCodeDoesNotCount(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	mainPath := filepath.Join(dir, "main.tf")
+	raw, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	content := string(raw)
+	content = strings.Replace(content, "  https_only ", "  # https_only ", 1)
+	content = strings.Replace(content, "  tags ", "  # tags ", 1)
+	require.NoError(t, os.WriteFile(mainPath, []byte(content), 0644))
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	assert.False(t, r.Passed)
+	assert.False(t, findCheck(t, r, "main.tf references local.tags").Passed)
+	assert.Contains(t, r.CoverageReport.MissingAttrs, "https_only")
+}
+
+func TestValidateModule_SyntaxErrorsAreReported(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.tf"), []byte("resource \"x\" {\n"), 0644))
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	c := findCheck(t, r, "HCL parses without errors")
+	assert.False(t, c.Passed)
+	assert.Contains(t, c.Message, "broken.tf:")
+}
+
+func TestValidateModule_UndeclaredVariable(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extra.tf"),
+		[]byte("locals {\n  extra = var.does_not_exist\n}\n"), 0644))
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+
+	c := findCheck(t, r, "All variable references are declared")
+	assert.False(t, c.Passed)
+	assert.Contains(t, c.Message, "does_not_exist")
+}
+
+func TestCheckCoverage_NestedPaths(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	cr := r.CoverageReport
+
+	byPath := map[string]CoverageEntry{}
+	for _, e := range cr.Entries {
+		byPath[e.Path] = e
+	}
+
+	enabled := byPath["enabled"]
+	assert.True(t, enabled.Wired)
+	assert.Equal(t, "windows_web_app_enabled", enabled.Variable)
+	assert.Contains(t, enabled.Location, "main.tf:")
+
+	nested := byPath["site_config.ip_restriction.ip_address"]
+	assert.True(t, nested.Wired)
+	assert.Equal(t, 2, nested.Depth)
+	assert.Equal(t, "site_config.ip_restriction.ip_address", nested.Variable)
+
+	require.Len(t, cr.Levels, 3)
+	for _, lc := range cr.Levels {
+		assert.Equal(t, 100.0, lc.Percent, "depth %d", lc.Depth)
+	}
+	assert.Empty(t, cr.UnwiredObjectFields)
+}
+
+func TestCheckCoverage_UnwiredObjectField(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	dir := writeTestModule(t, info)
+
+	mainPath := filepath.Join(dir, "main.tf")
+	raw, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	content := strings.Replace(string(raw), "ip_address = ip_restriction.value.ip_address", "", 1)
+	require.NoError(t, os.WriteFile(mainPath, []byte(content), 0644))
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	cr := r.CoverageReport
+
+	assert.Contains(t, cr.MissingAttrs, "site_config.ip_restriction.ip_address")
+	assert.Contains(t, cr.UnwiredObjectFields, "site_config.ip_restriction.ip_address")
+	assert.Less(t, cr.Levels[2].Percent, 100.0)
+	assert.Equal(t, 100.0, cr.Levels[0].Percent)
+}
+
+func TestValidateResources_HandWrittenMultiResourceModule(t *testing.T) {
+	dir := t.TempDir()
+	main := `resource "azurerm_resource_group" "rg" {
+  name     = var.resource_group_name
+  location = var.location
+}
+
+resource "azurerm_storage_account" "this" {
+  name                = var.name
+  resource_group_name = azurerm_resource_group.rg.name
+  location            = "westeurope"
+}
+`
+	vars := `variable "name" {}
+variable "resource_group_name" {}
+variable "location" {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(main), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(vars), 0644))
+
+	types, err := DetectResourceTypes(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"azurerm_storage_account", "azurerm_resource_group"}, types)
+
+	storage := &schema.ResourceInfo{ResourceType: "azurerm_storage_account", ShortName: "storage_account", Attributes: []schema.ParsedAttribute{
+		{Name: "location"}, {Name: "name"}, {Name: "resource_group_name"},
+	}}
+	rg := &schema.ResourceInfo{ResourceType: "azurerm_resource_group", ShortName: "resource_group", Attributes: []schema.ParsedAttribute{
+		{Name: "location"}, {Name: "name"},
+	}}
+
+	r, err := ValidateResources(dir, []*schema.ResourceInfo{storage, rg}, Options{})
+	require.NoError(t, err)
+
+	require.Len(t, r.ResourceCoverage, 2)
+	assert.Equal(t, "azurerm_storage_account.this", r.ResourceCoverage[0].Address)
+	assert.Equal(t, []string{"location"}, r.ResourceCoverage[0].Coverage.MissingAttrs, "resource_group_name is wired from the module's resource group")
+	assert.Equal(t, "azurerm_resource_group.rg", r.ResourceCoverage[1].Address)
+	assert.Equal(t, float64(100), r.ResourceCoverage[1].Coverage.CoveragePercent)
+	assert.Same(t, r.ResourceCoverage[0].Coverage, r.CoverageReport)
+
+	for _, e := range r.ResourceCoverage[0].Coverage.Entries {
+		if e.Path == "resource_group_name" {
+			assert.Equal(t, "azurerm_resource_group.rg", e.Reference)
+		}
+	}
+	assert.False(t, findCheck(t, r, "Argument coverage (azurerm_storage_account): 67%").Passed)
+	assert.True(t, findCheck(t, r, "Argument coverage (azurerm_resource_group): 100%").Passed)
+	assert.True(t, findCheck(t, r, "main.tf resource named 'this'").Passed)
+}
+
+func TestValidateModule_UserDefinedScenario(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	files, err := generators.GenerateModuleWithOptions(info, generators.Options{
+		Scenarios: []string{"default"},
+		Custom: []generators.Scenario{{
+			Name:      "https_only",
+			Variables: map[string]any{"https_only": true, "site_config": map[string]any{"always_on": true}},
+		}},
+	})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	_, err = generators.WriteModule(dir, files)
+	require.NoError(t, err)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	assert.True(t, findCheck(t, r, "Encryption in transit (tests/https_only): https_only").Passed)
+}
+
+func TestValidateModule_GeneratedFixtures(t *testing.T) {
+	info := schematest.WindowsWebApp()
+	info.Attributes = append(info.Attributes,
+		schema.ParsedAttribute{Name: "virtual_network_subnet_id", TFType: "string", Required: true},
+		schema.ParsedAttribute{Name: "key_vault_reference_identity_id", TFType: "string", Optional: true},
+		schema.ParsedAttribute{Name: "log_analytics_workspace_id", TFType: "string", Optional: true},
+	)
+	files, err := generators.GenerateModuleWithOptions(info, generators.Options{
+		Scenarios: []string{"default", "complete", "disabled"},
+		Fixtures:  true,
+	})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	_, err = generators.WriteModule(dir, files)
+	require.NoError(t, err)
+
+	r, err := ValidateModule(dir, info)
+	require.NoError(t, err)
+	assert.True(t, r.Passed)
+}
+
+// validateGenerated writes a generated module and validates it against infos,
+// requiring every error-severity check to pass.
+func validateGenerated(t *testing.T, files generators.GeneratedModule, infos ...*schema.ResourceInfo) *ValidationReport {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := generators.WriteModule(dir, files)
+	require.NoError(t, err)
+	r, err := ValidateResources(dir, infos, Options{})
+	require.NoError(t, err)
+	for _, c := range r.Checks {
+		assert.True(t, c.Passed || c.Severity != SeverityError, "%s: %s", c.Name, c.Message)
+	}
+	return r
+}
+
+func TestValidateResources_GeneratedCompositeModule(t *testing.T) {
+	webApp, plan := schematest.LinuxWebAppWithServicePlan()
+	files, err := generators.GenerateCompositeModule(generators.Composite{
+		Resources: []*schema.ResourceInfo{webApp, plan},
+		Relationships: []generators.Relationship{
+			{Resource: "azurerm_linux_web_app", Argument: "location", Target: "azurerm_service_plan", Attribute: "location"},
+		},
+	}, generators.Options{Scenarios: []string{"default", "complete", "disabled"}})
+	require.NoError(t, err)
+
+	r := validateGenerated(t, files, webApp, plan)
+	require.Len(t, r.ResourceCoverage, 2)
+	assert.Equal(t, "azurerm_service_plan.service_plan", r.ResourceCoverage[1].Address)
+	assert.Equal(t, float64(100), r.ResourceCoverage[1].Coverage.CoveragePercent)
+	assert.True(t, r.Passed)
+}
+
+func TestValidateResources_GeneratedChildCollection(t *testing.T) {
+	account, container := schematest.StorageAccountWithContainer()
+	files, err := generators.GenerateCompositeModule(generators.Composite{
+		Resources: []*schema.ResourceInfo{account},
+		Children:  []*schema.ResourceInfo{container},
+	}, generators.Options{Scenarios: []string{"default", "complete", "disabled"}})
+	require.NoError(t, err)
+
+	r := validateGenerated(t, files, account, container)
+	require.Len(t, r.ResourceCoverage, 2)
+	assert.Equal(t, "azurerm_storage_container.storage_container", r.ResourceCoverage[1].Address)
+	assert.Equal(t, float64(100), r.ResourceCoverage[1].Coverage.CoveragePercent)
+	assert.True(t, r.Passed)
+}
+
+func TestValidateResources_GeneratedAzAPIModule(t *testing.T) {
+	res, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "Microsoft.App/containerApps")
+	require.NoError(t, err)
+	res.Info.ModuleName = generators.DefaultTemplatePack().ModuleName(res.Info.ShortName)
+	files, err := generators.GenerateAzAPIModule(res, generators.Options{Scenarios: []string{"default", "complete", "disabled"}})
+	require.NoError(t, err)
+
+	r := validateGenerated(t, files, res.Info)
+	require.NotNil(t, r.CoverageReport)
+	assert.Equal(t, 100.0, r.CoverageReport.CoveragePercent, "missing attrs %v blocks %v", r.CoverageReport.MissingAttrs, r.CoverageReport.MissingBlocks)
+}
+
+func TestReadAzAPIResource(t *testing.T) {
+	res, err := schema.ParseRESTAPISpec([]byte(schematest.ContainerAppsSpec), "Microsoft.App/containerApps@2023-05-01")
+	require.NoError(t, err)
+	files, err := generators.GenerateAzAPIModule(res, generators.Options{Scenarios: []string{"default"}})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	_, err = generators.WriteModule(dir, files)
+	require.NoError(t, err)
+
+	got, err := ReadAzAPIResource(dir, []byte(schematest.ContainerAppsSpec))
+	require.NoError(t, err)
+	assert.Equal(t, "Microsoft.App/containerApps@2023-05-01", got.TypeVersion(), "the API version comes from the module")
+	assert.Equal(t, schema.AzAPIResourceType, got.Info.ResourceType)
+
+	r, err := ValidateResources(dir, []*schema.ResourceInfo{got.Info}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, r.CoverageReport.CoveragePercent)
+
+	_, err = ReadAzAPIResource(writeTestModule(t, schematest.WindowsWebApp()), []byte(schematest.ContainerAppsSpec))
+	assert.ErrorContains(t, err, "declares no azapi_resource")
+}
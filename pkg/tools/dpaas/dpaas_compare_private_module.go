@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// DPaaSComparePrivateModule registers the dpaas_compare_private_module tool.
+func DPaaSComparePrivateModule(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("dpaas_compare_private_module",
+			mcp.WithDescription(`Checks a published private registry module for consistency with the DPaaS generator. The inputs, outputs and resources the version publishes (as get_private_module_details shows them) are compared with a freshly generated DPaaS module for the same resource type:
+- generated inputs the version does not publish, with the arguments they would set
+- inputs whose type or requiredness differs
+- generated outputs the version does not publish
+- convention violations: module name, the resource named "this", the create_ flag, name override, null-label context and tags inputs, input descriptions
+
+Published inputs the generator does not produce are listed but not judged. This tool requires a valid Terraform token to be configured.`),
+			mcp.WithTitleAnnotation("DPaaS: Compare a private module with the generated module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name")),
+			mcp.WithString("private_module_id",
+				mcp.Required(),
+				mcp.Description("The private module ID in the format 'module-namespace/module-name/module-provider-name', as returned by 'search_private_modules'")),
+			mcp.WithString("private_module_version",
+				mcp.Description("Version to compare. Default: the latest version")),
+			mcp.WithString("resource_type",
+				mcp.Description("The azurerm resource type the module wraps. Default: the azurerm resource the module publishes, preferring one named 'this'")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack the module is generated with and whose naming it must follow: a built-in pack name or a pack directory. Default: 'experian'")),
+			mcp.WithString("output_format",
+				mcp.Enum(validation.FormatText, validation.FormatJSON),
+				mcp.Description("Report format: 'text' (default) or 'json' (also returned as structured content)")),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return dpaasComparePrivateModuleHandler(ctx, request, logger)
+		},
+	}
+}
+
+func dpaasComparePrivateModuleHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	orgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: terraform_org_name", err)
+	}
+	orgName = strings.TrimSpace(orgName)
+
+	moduleID, err := request.RequireString("private_module_id")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: private_module_id", err)
+	}
+	parts := strings.Split(strings.TrimSpace(moduleID), "/")
+	if len(parts) != 3 {
+		return DPaaSToolErrorf(logger, "private_module_id must be in format 'module-namespace/module-name/module-provider-name'")
+	}
+
+	format := strings.TrimSpace(strings.ToLower(request.GetString("output_format", validation.FormatText)))
+	if format != validation.FormatText && format != validation.FormatJSON {
+		return DPaaSToolErrorf(logger, "invalid output_format %q, expected text or json", format)
+	}
+
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to get Terraform client - ensure TFE_TOKEN and TFE_ADDRESS are configured", err)
+	}
+
+	logger.Infof("[dpaas] reading published interface of %s", moduleID)
+	details, err := tfeClient.RegistryModules.ReadTerraformRegistryModule(ctx, tfe.RegistryModuleID{
+		Organization: orgName,
+		Namespace:    parts[0],
+		Name:         parts[1],
+		Provider:     parts[2],
+		RegistryName: tfe.PrivateRegistry,
+	}, strings.TrimSpace(request.GetString("private_module_version", "")))
+	if err != nil {
+		return DPaaSToolError(logger, fmt.Sprintf("failed to read module %s - use search_private_modules to find valid module IDs", moduleID), err)
+	}
+	published := publishedInterface(details)
+
+	resourceType := strings.TrimSpace(request.GetString("resource_type", ""))
+	if resourceType == "" {
+		if resourceType = wrappedResourceType(details.Root.Resources); resourceType == "" {
+			return DPaaSToolErrorf(logger, "%s publishes no azurerm resource; pass resource_type explicitly", moduleID)
+		}
+		logger.Infof("[dpaas] comparing against the generated module for %s", resourceType)
+	}
+
+	info, err := extractResourceInfo(resourceType, logger)
+	if err != nil {
+		return DPaaSToolError(logger, fmt.Sprintf("failed to extract schema for %s", resourceType), err)
+	}
+
+	report, err := validation.CompareInterface(published, info, pack)
+	if err != nil {
+		return DPaaSToolError(logger, "comparison failed", err)
+	}
+
+	if format == validation.FormatJSON {
+		result, err := mcp.NewToolResultJSON(report)
+		if err != nil {
+			return DPaaSToolError(logger, "failed to render report", err)
+		}
+		return result, nil
+	}
+	return mcp.NewToolResultText(formatInterfaceReport(moduleID, report)), nil
+}
+
+// publishedInterface converts the registry's module details into the
+// interface the comparison reads.
+func publishedInterface(m *tfe.TerraformRegistryModule) *validation.ModuleInterface {
+	mi := &validation.ModuleInterface{Name: m.Name, Version: m.Version}
+	for _, in := range m.Root.Inputs {
+		mi.Inputs = append(mi.Inputs, validation.InterfaceInput{
+			Name:        in.Name,
+			Type:        in.Type,
+			Description: in.Description,
+			Required:    in.Required,
+		})
+	}
+	for _, out := range m.Root.Outputs {
+		mi.Outputs = append(mi.Outputs, out.Name)
+	}
+	for _, r := range m.Root.Resources {
+		mi.Resources = append(mi.Resources, r.Type+"."+r.Name)
+	}
+	return mi
+}
+
+// wrappedResourceType picks the azurerm resource a published module wraps:
+// the one named "this", or else the first.
+func wrappedResourceType(resources []tfe.Resource) string {
+	first := ""
+	for _, r := range resources {
+		if !strings.HasPrefix(r.Type, "azurerm_") {
+			continue
+		}
+		if r.Name == "this" {
+			return r.Type
+		}
+		if first == "" {
+			first = r.Type
+		}
+	}
+	return first
+}
+
+func formatInterfaceReport(moduleID string, r *validation.InterfaceReport) string {
+	var b strings.Builder
+
+	status := "CONSISTENT"
+	if !r.Consistent() {
+		status = "DRIFTED"
+	}
+	b.WriteString(fmt.Sprintf("Interface check: %s %s (%s): %s\n", moduleID, r.Version, r.ResourceType, status))
+	b.WriteString(fmt.Sprintf("Generated inputs published unchanged: %d/%d\n", r.Matched, r.Generated))
+
+	section := func(title string, diffs []validation.InterfaceDiff, line func(validation.InterfaceDiff) string) {
+		if len(diffs) == 0 {
+			return
+		}
+		b.WriteString(fmt.Sprintf("\n%s (%d):\n", title, len(diffs)))
+		for _, d := range diffs {
+			rule := ""
+			if d.Rule != "" {
+				rule = " [" + d.Rule + "]"
+			}
+			b.WriteString(fmt.Sprintf("  - %s%s\n", line(d), rule))
+		}
+	}
+	section("Missing inputs", r.MissingInputs, func(d validation.InterfaceDiff) string {
+		return fmt.Sprintf("%s (%s): %s", d.Name, oneLine(d.Generated), d.Message)
+	})
+	section("Type mismatches", r.TypeMismatches, func(d validation.InterfaceDiff) string {
+		return fmt.Sprintf("%s: %s, published %s, generated %s", d.Name, d.Message, oneLine(d.Published), oneLine(d.Generated))
+	})
+	section("Missing outputs", r.MissingOutputs, func(d validation.InterfaceDiff) string {
+		return d.Name
+	})
+	section("Convention violations", r.Conventions, func(d validation.InterfaceDiff) string {
+		return fmt.Sprintf("%s: %s", d.Name, d.Message)
+	})
+	if len(r.ExtraInputs) > 0 {
+		b.WriteString(fmt.Sprintf("\nPublished inputs the generator does not produce (%d): %s\n", len(r.ExtraInputs), strings.Join(r.ExtraInputs, ", ")))
+	}
+	if !r.Consistent() {
+		b.WriteString("\nRegenerate the module with dpaas_generate_innersource_module to see the expected interface, or fix it in place with dpaas_validate_module and fix: true.\n")
+	}
+	return b.String()
+}
+
+// oneLine collapses a multi-line type constraint for the text report.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -3,9 +3,10 @@ package tools
 import (
 	"context"
 	"fmt"
-	"os/exec"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
@@ -23,25 +24,45 @@ func DPaaSGenerateModule(logger *log.Logger) server.ServerTool {
 
 This tool:
 1. Extracts the full resource schema from the azurerm provider
-2. Generates all required files following DPaaS standards (expn-tf-azure-{resource} naming)
-3. Creates test scenarios with all required attributes under tests/
-4. Validates argument coverage and DPaaS standards compliance
+2. Generates all required files following DPaaS standards, with the naming, module source, tags and README layout of the selected template pack (expn-tf-azure-{resource} by default)
+3. Creates test scenarios with all required attributes under tests/, plus terraform test files that run against a mock azurerm provider
+4. Validates argument coverage, DPaaS standards and security rules, and records the results in the README benchmark table
 5. Returns a full validation report
 
-The module folder will be named following DPaaS convention: expn-tf-azure-{resource}
-All arguments from the provider schema are included — nothing is hardcoded.`),
+The module folder is named by the template pack's module prefix: expn-tf-azure-{resource} for the built-in pack.
+All arguments from the provider schema are included — nothing is hardcoded.
+
+Pass several resource types to generate one composite module, e.g. an App Service Plan with a Linux Web App. The first resource is the primary one; the others' variables are prefixed with their short name (service_plan_sku_name), and arguments that reference another resource of the module (service_plan_id) are wired to it instead of exposed as inputs.
+
+Pass child resource types to create any number of them with the module, e.g. storage containers with a storage account. Each child type becomes a map(object) variable keyed by name (storage_containers) that the child resource is created for_each of, its parent ID or name (storage_account_id, virtual_network_name) is wired from the parent resource, and its computed attributes are exported as a map output.
+
+For an Azure resource type the azurerm provider does not cover, pass a local Azure REST API spec (the swagger JSON of the resource provider) as rest_api_spec and the ARM type as resource_type (e.g. 'Microsoft.App/containerApps'). The module then wraps azapi_resource: the PUT request body's properties become snake_case variables and nested blocks, assembled into the body in main.tf, read-only properties become outputs, and the layout, tags and null-label naming are the same as for azurerm modules.`),
 			mcp.WithTitleAnnotation("DPaaS: Generate innersource Terraform module"),
 			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(false),
 			mcp.WithString("resource_type",
 				mcp.Required(),
-				mcp.Description("Azure resource type (e.g. 'azurerm_bastion_host', 'azurerm_virtual_network')")),
+				mcp.Description("Azure resource type (e.g. 'azurerm_bastion_host', 'azurerm_virtual_network'), or a comma-separated list for a composite module with the primary resource first (e.g. 'azurerm_linux_web_app,azurerm_service_plan'). With rest_api_spec, the ARM resource type, optionally with an API version (e.g. 'Microsoft.App/containerApps@2024-03-01')")),
 			mcp.WithString("output_path",
 				mcp.Required(),
-				mcp.Description("Parent directory where the module folder will be created (module will be named expn-tf-azure-{resource})")),
+				mcp.Description("Parent directory where the module folder will be created (module will be named {module_prefix}{resource}, expn-tf-azure-{resource} by default)")),
 			mcp.WithString("test_scenarios",
 				mcp.Description("Comma-separated list of test scenarios to generate. Available: default, complete, disabled. Default: 'default'. Example: 'default,complete,disabled'")),
+			mcp.WithString("scenario_file",
+				mcp.Description("Path to a JSON scenario file with user-defined scenarios (variable overrides, provider configuration and expectations) and the location, resource group and subscription every scenario uses. Every scenario in the file is generated in addition to test_scenarios. Defaults to .dpaas-scenarios.json in the module directory when present")),
+			mcp.WithBoolean("fixtures",
+				mcp.Description("Emit a fixtures.tf in each scenario that creates the resource group, VNet and subnet, Key Vault and Log Analytics workspace the module call needs, and reference them instead of placeholder IDs so the scenario can be applied. Default: false")),
+			mcp.WithString("relationships",
+				mcp.Description("Comma-separated relationships between the resources of a composite module, as <type>.<argument>=<type>.<attribute> (e.g. 'azurerm_linux_web_app.service_plan_id=azurerm_service_plan.id'). Arguments named <short_name>_id or <short_name>_name after another resource of the module are wired without being listed")),
+			mcp.WithString("child_resource_types",
+				mcp.Description("Comma-separated child resource types created once per entry of a map variable and attached to their parent resource in the module (e.g. 'azurerm_storage_container' with 'azurerm_storage_account', 'azurerm_subnet' with 'azurerm_virtual_network')")),
+			mcp.WithString("rest_api_spec",
+				mcp.Description("Path to an Azure REST API spec JSON file describing resource_type, for resource types missing from the azurerm provider. The module wraps azapi_resource instead of an azurerm resource")),
+			mcp.WithString("template_pack",
+				mcp.Description("Organisation template pack: the name of a built-in pack or the path to a pack directory with a pack.json and locals.tf, README.md and CHANGELOG.md templates. Sets the module naming, module source, tags, null-label inputs and scenario defaults. Default: 'experian'")),
+			withOutputFormat(),
+			withURIBase(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return dpaasGenerateModuleHandler(ctx, request, logger)
@@ -50,13 +71,51 @@ All arguments from the provider schema are included — nothing is hardcoded.`),
 }
 
 func dpaasGenerateModuleHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	resourceType, err := request.RequireString("resource_type")
+	rawTypes, err := request.RequireString("resource_type")
 	if err != nil {
 		return DPaaSToolError(logger, "missing required input: resource_type", err)
 	}
-	resourceType = strings.TrimSpace(strings.ToLower(resourceType))
-	if !strings.HasPrefix(resourceType, "azurerm_") {
-		return DPaaSToolErrorf(logger, "resource_type must start with 'azurerm_'")
+	specPath := strings.TrimSpace(request.GetString("rest_api_spec", ""))
+	// with a REST API spec, resource_type is an ARM type read from the spec
+	var resourceTypes []string
+	if specPath == "" {
+		for _, t := range strings.Split(rawTypes, ",") {
+			t = strings.TrimSpace(strings.ToLower(t))
+			if !strings.HasPrefix(t, "azurerm_") {
+				return DPaaSToolErrorf(logger, "resource_type must start with 'azurerm_', got %q", t)
+			}
+			resourceTypes = append(resourceTypes, t)
+		}
+	}
+
+	var childTypes []string
+	for _, t := range strings.Split(request.GetString("child_resource_types", ""), ",") {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if t == "" {
+			continue
+		}
+		if !strings.HasPrefix(t, "azurerm_") {
+			return DPaaSToolErrorf(logger, "child_resource_types must start with 'azurerm_', got %q", t)
+		}
+		childTypes = append(childTypes, t)
+	}
+	if specPath != "" && len(childTypes) > 0 {
+		return DPaaSToolErrorf(logger, "child_resource_types cannot be combined with rest_api_spec")
+	}
+
+	var relationships []generators.Relationship
+	for _, raw := range strings.Split(request.GetString("relationships", ""), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		rel, err := generators.ParseRelationship(raw)
+		if err != nil {
+			return DPaaSToolError(logger, "invalid relationships", err)
+		}
+		relationships = append(relationships, rel)
+	}
+	if specPath != "" && len(relationships) > 0 {
+		return DPaaSToolErrorf(logger, "relationships cannot be combined with rest_api_spec")
 	}
 
 	outputPath, err := request.RequireString("output_path")
@@ -64,52 +123,152 @@ func dpaasGenerateModuleHandler(_ context.Context, request mcp.CallToolRequest,
 		return DPaaSToolError(logger, "missing required input: output_path", err)
 	}
 
-	// 1. extract schema
-	logger.Infof("[dpaas] extracting schema for %s", resourceType)
-	info, err := schema.ExtractResourceSchema(resourceType, logger)
+	format, err := outputFormat(request)
 	if err != nil {
-		return DPaaSToolError(logger, fmt.Sprintf("schema extraction failed for %s", resourceType), err)
+		return DPaaSToolError(logger, "invalid output_format", err)
 	}
 
-	// 2. fetch docs and merge enum values + descriptions (non-fatal if fetch fails)
-	logger.Infof("[dpaas] fetching provider docs …")
-	docsInfo := schema.FetchDocsInfo(resourceType)
-	if docsInfo != nil {
-		schema.MergeDocsInfo(info, docsInfo)
-		logger.Infof("[dpaas] merged %d enum value sets and %d descriptions from provider docs", len(docsInfo.Enums), len(docsInfo.Descriptions))
-	} else {
-		logger.Warn("[dpaas] could not fetch provider docs – falling back to schema-only enums")
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+
+	// 1-2. extract each schema and merge the provider docs, or read the
+	// REST API spec of an azapi_resource module
+	var infos []*schema.ResourceInfo
+	var azapi *schema.AzAPIResource
+	if specPath != "" {
+		raw, err := os.ReadFile(specPath)
+		if err != nil {
+			return DPaaSToolError(logger, "failed to read rest_api_spec", err)
+		}
+		if azapi, err = schema.ParseRESTAPISpec(raw, rawTypes); err != nil {
+			return DPaaSToolError(logger, "failed to read the resource type from rest_api_spec", err)
+		}
+		logger.Infof("[dpaas] %s from %s: %d arguments, %d blocks", azapi.TypeVersion(), specPath, len(azapi.Info.Attributes), len(azapi.Info.Blocks))
+		infos = append(infos, azapi.Info)
+	}
+	for _, resourceType := range resourceTypes {
+		info, err := extractResourceInfo(resourceType, logger)
+		if err != nil {
+			return DPaaSToolError(logger, fmt.Sprintf("schema extraction failed for %s", resourceType), err)
+		}
+		infos = append(infos, info)
+	}
+	var children []*schema.ResourceInfo
+	for _, resourceType := range childTypes {
+		child, err := extractResourceInfo(resourceType, logger)
+		if err != nil {
+			return DPaaSToolError(logger, fmt.Sprintf("schema extraction failed for %s", resourceType), err)
+		}
+		children = append(children, child)
 	}
+	info := infos[0]
+	composite := generators.Composite{Resources: infos, Children: children, Relationships: relationships}
 
 	// 3. parse test scenarios
-	scenariosStr := request.GetString("test_scenarios", "")
-	scenarios := parseTestScenarios(scenariosStr)
-	logger.Infof("[dpaas] test scenarios: %v", scenarios)
+	// The module folder is named by the pack: {module_prefix}{resource}
+	info.ModuleName = pack.ModuleName(info.ShortName)
+	modulePath := filepath.Join(outputPath, info.ModuleName)
+	opts := generators.Options{
+		Scenarios: parseTestScenarios(request.GetString("test_scenarios", "")),
+		Fixtures:  request.GetBool("fixtures", false),
+		Pack:      pack,
+	}
+	scenarioFile, err := loadScenarioFile(request, modulePath)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load scenario_file", err)
+	}
+	if scenarioFile != nil {
+		opts.Custom = scenarioFile.Scenarios
+		opts.Defaults = scenarioFile.Defaults
+	}
+	logger.Infof("[dpaas] test scenarios: %v (+%d user-defined)", opts.Scenarios, len(opts.Custom))
 
 	// 4. generate all files
 	logger.Infof("[dpaas] generating module files for %s", info.ModuleName)
-	module := generators.GenerateModule(info, scenarios)
+	var module generators.GeneratedModule
+	if azapi != nil {
+		module, err = generators.GenerateAzAPIModule(azapi, opts)
+	} else if len(infos) == 1 && len(children) == 0 && len(relationships) == 0 {
+		module, err = generators.GenerateModuleWithOptions(info, opts)
+	} else {
+		module, err = generators.GenerateCompositeModule(composite, opts)
+	}
+	if err != nil {
+		return DPaaSToolError(logger, "failed to generate module", err)
+	}
+
+	// keep the hand-written sections of an existing README; only the
+	// generated inputs and outputs tables are refreshed
+	if existing, err := os.ReadFile(filepath.Join(modulePath, "README.md")); err == nil {
+		if docs, err := generators.TerraformDocs(module); err == nil {
+			if readme, ok := generators.UpdateReadmeTfDocs(string(existing), docs); ok {
+				module["README.md"] = readme
+			}
+		}
+	}
 
 	// 5. write to disk using the correct DPaaS module naming convention
-	// The module folder should always be: expn-tf-azure-{resource}
-	modulePath := filepath.Join(outputPath, info.ModuleName)
 	written, err := generators.WriteModule(modulePath, module)
 	if err != nil {
 		return DPaaSToolError(logger, "failed to write module files", err)
 	}
 
-	// 6. format with terraform fmt
-	logger.Info("[dpaas] formatting module with terraform fmt …")
-	if err := formatModule(modulePath, logger); err != nil {
-		logger.Warnf("[dpaas] terraform fmt failed (non-fatal): %v", err)
-		// Don't fail the generation if fmt fails - it's not critical
+	// 6. validate
+	logger.Info("[dpaas] validating generated module …")
+	report, err := validation.ValidateResources(modulePath, append(infos, children...), validation.Options{Pack: pack})
+	if err == nil {
+		if err := validation.UpdateReadmeBenchmarks(modulePath, report, time.Now().Format("2006-01-02")); err != nil {
+			logger.Warnf("[dpaas] README benchmark update failed (non-fatal): %v", err)
+		}
 	}
 
-	// 7. validate
-	logger.Info("[dpaas] validating generated module …")
-	report, _ := validation.ValidateModule(modulePath, info)
+	if format != validation.FormatText {
+		if err != nil {
+			return DPaaSToolError(logger, "validation of the generated module failed", err)
+		}
+		return renderValidationReport(logger, request, format, modulePath, report)
+	}
+	text := formatGenerationReport(info, modulePath, written, report)
+	if len(infos) > 1 || len(children) > 0 {
+		text += formatWiring(composite)
+	}
+	return mcp.NewToolResultText(text), nil
+}
+
+// extractResourceInfo extracts a resource's schema and merges the enum values
+// and descriptions from the provider docs (non-fatal if the fetch fails).
+func extractResourceInfo(resourceType string, logger *log.Logger) (*schema.ResourceInfo, error) {
+	logger.Infof("[dpaas] extracting schema for %s", resourceType)
+	info, err := schema.ExtractResourceSchema(resourceType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("[dpaas] fetching provider docs …")
+	docsInfo := schema.FetchDocsInfo(resourceType)
+	if docsInfo != nil {
+		schema.MergeDocsInfo(info, docsInfo)
+		logger.Infof("[dpaas] merged %d enum value sets and %d descriptions from provider docs", len(docsInfo.Enums), len(docsInfo.Descriptions))
+	} else {
+		logger.Warn("[dpaas] could not fetch provider docs – falling back to schema-only enums")
+	}
+	return info, nil
+}
 
-	return mcp.NewToolResultText(formatGenerationReport(info, modulePath, written, report)), nil
+// formatWiring lists the cross-references of a composite module.
+func formatWiring(c generators.Composite) string {
+	wiring, err := c.Wiring()
+	if err != nil || len(wiring) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nWired between resources:\n")
+	for _, w := range wiring {
+		b.WriteString(fmt.Sprintf("  - %s.%s ← %s.%s\n", w.Resource, w.Argument, w.Target, w.Attribute))
+	}
+	return b.String()
 }
 
 func formatGenerationReport(info *schema.ResourceInfo, modulePath string, written []string, report *validation.ValidationReport) string {
@@ -126,29 +285,14 @@ func formatGenerationReport(info *schema.ResourceInfo, modulePath string, writte
 		return b.String()
 	}
 
-	b.WriteString(fmt.Sprintf("\nValidation: %d/%d checks passed\n\n", report.PassedChecks, report.TotalChecks))
+	b.WriteString(fmt.Sprintf("\nValidation: %d/%d checks passed (%d errors, %d warnings, %d info)\n\n",
+		report.PassedChecks, report.TotalChecks, report.Errors, report.Warnings, report.Infos))
 	for _, c := range report.Checks {
-		symbol := "PASS"
-		if !c.Passed {
-			symbol = "FAIL"
-		}
-		line := fmt.Sprintf("  [%s] %s", symbol, c.Name)
-		if !c.Passed && c.Message != "" {
-			line += " -- " + c.Message
-		}
-		b.WriteString(line + "\n")
+		b.WriteString(formatCheckLine(c) + "\n")
 	}
 
 	if cr := report.CoverageReport; cr != nil {
-		b.WriteString(fmt.Sprintf("\nArgument Coverage: %.0f%%\n", cr.CoveragePercent))
-		b.WriteString(fmt.Sprintf("  Attributes: %d/%d\n", cr.GeneratedAttrCount, cr.SchemaAttrCount))
-		b.WriteString(fmt.Sprintf("  Blocks:     %d/%d\n", cr.GeneratedBlockCount, cr.SchemaBlockCount))
-		if len(cr.MissingAttrs) > 0 {
-			b.WriteString(fmt.Sprintf("  Missing attributes: %s\n", strings.Join(cr.MissingAttrs, ", ")))
-		}
-		if len(cr.MissingBlocks) > 0 {
-			b.WriteString(fmt.Sprintf("  Missing blocks: %s\n", strings.Join(cr.MissingBlocks, ", ")))
-		}
+		writeCoverageSummary(&b, cr)
 	}
 
 	if report.Passed {
@@ -180,21 +324,16 @@ func parseTestScenarios(raw string) []string {
 	return scenarios
 }
 
-// formatModule runs terraform fmt on the generated module
-func formatModule(modulePath string, logger *log.Logger) error {
-	// Run terraform fmt -recursive on the module directory
-	cmd := exec.Command("terraform", "fmt", "-recursive", modulePath)
-
-	// Capture output for logging
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("terraform fmt failed: %w\nOutput: %s", err, string(output))
-	}
-
-	// Log formatted files
-	if len(output) > 0 {
-		logger.Debugf("[dpaas] terraform fmt output:\n%s", string(output))
+// loadScenarioFile loads the scenario_file named in the request, else the
+// module's own .dpaas-scenarios.json when present. It returns nil when there
+// is neither.
+func loadScenarioFile(request mcp.CallToolRequest, modulePath string) (*generators.ScenarioFile, error) {
+	path := strings.TrimSpace(request.GetString("scenario_file", ""))
+	if path == "" {
+		path = filepath.Join(modulePath, generators.ScenarioFileName)
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
 	}
-
-	return nil
+	return generators.LoadScenarioFile(path)
 }
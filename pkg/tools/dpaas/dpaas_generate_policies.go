@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// DPaaSGeneratePolicies registers the dpaas_generate_policies tool.
+func DPaaSGeneratePolicies(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("dpaas_generate_policies",
+			mcp.WithDescription(`Generates a starter policy set for an innersource module, enforcing on a plan what the module promises:
+- allowed values of every enum argument
+- the tags every resource must carry (the template pack's tags and the required keys of its tag local)
+- the secure value of every public access, transport and encryption setting in the security baseline
+
+Sentinel and/or Rego policies are written with pass and fail test fixtures. Every rule is cross-checked against the module's variables, reporting arguments no variable sets, enum validations allowing other values and defaults the policy would deny.`),
+			mcp.WithTitleAnnotation("DPaaS: Generate Sentinel and Rego policies for a module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("The module directory the policies are generated for")),
+			mcp.WithString("resource_type",
+				mcp.Description("The azurerm resource type the policies govern (e.g. 'azurerm_storage_account'). Defaults to the first azurerm resource of the module")),
+			mcp.WithString("languages",
+				mcp.Description("Comma-separated policy languages: 'sentinel', 'rego' or both. Default: 'sentinel,rego'")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack whose tags and required tags the policies require: a built-in pack name or a pack directory. Default: 'experian'")),
+			mcp.WithString("output_path",
+				mcp.Description("Directory the policy set is written to. Default: the policies directory of the module")),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return dpaasGeneratePoliciesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func dpaasGeneratePoliciesHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	path, err := request.RequireString("path")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: path", err)
+	}
+
+	var opts generators.PolicyOptions
+	for _, lang := range strings.Split(request.GetString("languages", "sentinel,rego"), ",") {
+		switch strings.ToLower(strings.TrimSpace(lang)) {
+		case "sentinel":
+			opts.Sentinel = true
+		case "rego", "opa":
+			opts.Rego = true
+		case "":
+		default:
+			return DPaaSToolErrorf(logger, "invalid language %q, expected sentinel or rego", lang)
+		}
+	}
+
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+	opts.RequiredTags = pack.ResourceTags()
+
+	resourceType := strings.TrimSpace(request.GetString("resource_type", ""))
+	if resourceType == "" {
+		detected, err := validation.DetectResourceTypes(path)
+		if err != nil {
+			return DPaaSToolError(logger, "failed to read module for resource type detection", err)
+		}
+		for _, rt := range detected {
+			if strings.HasPrefix(rt, "azurerm_") {
+				resourceType = rt
+				break
+			}
+		}
+		if resourceType == "" {
+			return DPaaSToolErrorf(logger, "no azurerm resources found in %s; pass resource_type explicitly", path)
+		}
+		logger.Infof("[dpaas] generating policies for detected resource type %s", resourceType)
+	}
+
+	info, err := extractResourceInfo(resourceType, logger)
+	if err != nil {
+		return DPaaSToolError(logger, fmt.Sprintf("failed to extract schema for %s", resourceType), err)
+	}
+
+	opts.Security = validation.PolicySettings(info)
+	ps, err := generators.GeneratePolicies(info, opts)
+	if err != nil {
+		return DPaaSToolError(logger, "policy generation failed", err)
+	}
+	if err := validation.CrossCheckPolicies(path, info, ps); err != nil {
+		return DPaaSToolError(logger, "failed to cross-check the policies against the module", err)
+	}
+
+	outputPath := strings.TrimSpace(request.GetString("output_path", ""))
+	if outputPath == "" {
+		outputPath = filepath.Join(path, generators.PolicyDir)
+	}
+	written, err := generators.WriteModule(outputPath, ps.Files)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to write policy files", err)
+	}
+	return mcp.NewToolResultText(formatPolicySet(ps, outputPath, written)), nil
+}
+
+func formatPolicySet(ps *generators.PolicySet, outputPath string, written []string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Policy set %s: %d rules\n", ps.Name, len(ps.Rules)))
+	b.WriteString(fmt.Sprintf("Written to %s: %s\n", outputPath, strings.Join(written, ", ")))
+
+	findings := 0
+	b.WriteString("\nRules:\n")
+	for _, r := range ps.Rules {
+		variable := r.Variable
+		if variable == "" {
+			variable = "no variable"
+		}
+		b.WriteString(fmt.Sprintf("  [%s] %s (%s ← %s)\n", strings.ToUpper(r.Kind), r.Name, r.Path, variable))
+		for _, f := range r.Findings {
+			b.WriteString(fmt.Sprintf("      ! %s\n", f))
+			findings++
+		}
+	}
+	if findings > 0 {
+		b.WriteString(fmt.Sprintf("\n%d findings: the module accepts values its policies deny. Tighten the variable validations or defaults, or relax the rules.\n", findings))
+	}
+	return b.String()
+}
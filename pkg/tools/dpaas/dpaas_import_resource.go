@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// DPaaSImportResource registers the dpaas_import_resource tool.
+func DPaaSImportResource(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("dpaas_import_resource",
+			mcp.WithDescription(`Adopts an already deployed Azure resource into a DPaaS module. Reads the resource's current attributes from 'terraform show -json' output or a state file and returns:
+- terraform.tfvars with the deployed values (null and empty optional values keep the module defaults; sensitive arguments are listed for you to set)
+- <module_name>_variables.tf declaring a root variable for each value, since tfvars only set root variables
+- <module_name>.tf calling the DPaaS module and passing each variable to it
+- imports.tf with an import block addressing module.<module_name>.<resource_type>.this[0]
+
+Add them to the root module, then run terraform plan: the resource is imported instead of replaced.`),
+			mcp.WithTitleAnnotation("DPaaS: Import an existing resource into a module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("resource_type",
+				mcp.Required(),
+				mcp.Description("The Azure resource type the module wraps (e.g. 'azurerm_storage_account')")),
+			mcp.WithString("state_file",
+				mcp.Required(),
+				mcp.Description("Path to 'terraform show -json' output or a terraform.tfstate file holding the deployed resource")),
+			mcp.WithString("address",
+				mcp.Description("Address of the resource in the state (e.g. 'azurerm_storage_account.logs'). Required when the state holds several resources of the type")),
+			mcp.WithString("module_name",
+				mcp.Description("Label of the module call the resource is imported into, a valid identifier. Default: the resource short name, e.g. 'storage_account'")),
+			mcp.WithString("module_source",
+				mcp.Description("Source of the module call. Default: the source the template pack renders for the resource")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack rendering the module source: a built-in pack name or a pack directory. Default: 'experian'")),
+			mcp.WithString("output_path",
+				mcp.Description("Directory to write the files to, usually the root module adopting the resource. Nothing is written when any of the files already exists there. The files are only returned when omitted")),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return dpaasImportResourceHandler(ctx, request, logger)
+		},
+	}
+}
+
+func dpaasImportResourceHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	resourceType, err := request.RequireString("resource_type")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: resource_type", err)
+	}
+	resourceType = strings.TrimSpace(strings.ToLower(resourceType))
+	if !strings.HasPrefix(resourceType, "azurerm_") {
+		return DPaaSToolErrorf(logger, "resource_type must start with 'azurerm_' (got: %q)", resourceType)
+	}
+
+	statePath, err := request.RequireString("state_file")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: state_file", err)
+	}
+	raw, err := os.ReadFile(strings.TrimSpace(statePath))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to read state_file", err)
+	}
+	state, err := generators.ReadResourceState(raw, resourceType, strings.TrimSpace(request.GetString("address", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to read the resource from state_file", err)
+	}
+
+	info, err := schema.ExtractResourceSchema(resourceType, logger)
+	if err != nil {
+		return DPaaSToolError(logger, fmt.Sprintf("failed to extract schema for %s", resourceType), err)
+	}
+
+	source := strings.TrimSpace(request.GetString("module_source", ""))
+	if source == "" {
+		pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+		if err != nil {
+			return DPaaSToolError(logger, "failed to load template_pack", err)
+		}
+		if source, err = pack.ModuleSource(info); err != nil {
+			return DPaaSToolError(logger, "failed to render the module source", err)
+		}
+	}
+
+	moduleName := strings.TrimSpace(request.GetString("module_name", ""))
+	if moduleName == "" {
+		moduleName = info.ShortName
+	}
+	files, err := generators.GenerateImport(info, state, moduleName, source)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to map the resource state onto the module", err)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Import of %s (%s)\n", state.Address, state.ID()))
+	if outputPath := strings.TrimSpace(request.GetString("output_path", "")); outputPath != "" {
+		if _, err := os.Stat(outputPath); err != nil {
+			return DPaaSToolError(logger, "invalid output_path", err)
+		}
+		if existing := generators.ExistingFiles(outputPath, files); len(existing) > 0 {
+			return DPaaSToolErrorf(logger, "refusing to overwrite existing files in %s: %s; move them aside, or omit output_path to only get the files back",
+				outputPath, strings.Join(existing, ", "))
+		}
+		written, err := generators.WriteModule(outputPath, files)
+		if err != nil {
+			return DPaaSToolError(logger, "failed to write import files", err)
+		}
+		b.WriteString(fmt.Sprintf("Written to %s: %s\n", outputPath, strings.Join(written, ", ")))
+	}
+	for _, name := range []string{moduleName + ".tf", moduleName + "_variables.tf", "imports.tf", "terraform.tfvars"} {
+		b.WriteString(fmt.Sprintf("\n--- %s ---\n%s", name, files[name]))
+	}
+	return mcp.NewToolResultText(b.String()), nil
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// DPaaSMigrateResources registers the dpaas_migrate_resources tool.
+func DPaaSMigrateResources(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("dpaas_migrate_resources",
+			mcp.WithDescription(`Converts a Terraform root module built from bare azurerm_* resources into calls of DPaaS innersource modules, without replacing anything:
+- every resource whose module exists under modules_dir (or, with generate_missing, can be generated) becomes a module call with equivalent inputs: arguments as their module variables, the name as <short_name>_name, nested blocks as object or map values, depends_on kept and provider passed as providers
+- references to the resource elsewhere in the directory are rewritten to the module's outputs
+- moved.tf gets a moved block per resource, so the state follows it into the module
+
+Resources using count, for_each, dynamic blocks, lifecycle or provisioners, or with arguments the module does not take, are left alone and listed with the reason. The unified diff of every change is returned.`),
+			mcp.WithTitleAnnotation("DPaaS: Migrate bare resources to module calls"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("The Terraform directory to migrate")),
+			mcp.WithString("modules_dir",
+				mcp.Description("Directory holding existing modules named by the template pack (e.g. expn-tf-azure-storage-account). Their calls use a relative source")),
+			mcp.WithBoolean("generate_missing",
+				mcp.Description("Migrate resources without a module in modules_dir to the module the generator produces for them, using the template pack's module source. Generate those modules with dpaas_generate_innersource_module before applying. Default: true")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack naming the modules and rendering their source: a built-in pack name or a pack directory. Default: 'experian'")),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Return the diff without writing any file. Default: false")),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return dpaasMigrateResourcesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func dpaasMigrateResourcesHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	path, err := request.RequireString("path")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: path", err)
+	}
+
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+
+	opts := validation.MigrateOptions{
+		ModulesDir: strings.TrimSpace(request.GetString("modules_dir", "")),
+		Generate:   request.GetBool("generate_missing", true),
+		Pack:       pack,
+		DryRun:     request.GetBool("dry_run", false),
+	}
+	logger.Infof("[dpaas] migrating bare resources in %s", path)
+	result, err := validation.MigrateDirectory(path, newSchemaResolver(logger), opts)
+	if err != nil {
+		return DPaaSToolError(logger, "migration failed", err)
+	}
+	return mcp.NewToolResultText(formatMigration(path, result, opts.DryRun)), nil
+}
+
+func formatMigration(path string, result *validation.MigrationResult, dryRun bool) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Migration: %s\n", path))
+	if dryRun {
+		b.WriteString("Dry run: no files were written\n")
+	}
+
+	b.WriteString(fmt.Sprintf("\nMigrated (%d):\n", len(result.Migrated)))
+	var generate []string
+	seen := map[string]bool{}
+	for _, m := range result.Migrated {
+		b.WriteString(fmt.Sprintf("  [MOVED] %s → %s (%s, source %s)\n", m.Address, m.Module, m.File, m.Source))
+		if m.Generated && !seen[m.Source] {
+			seen[m.Source] = true
+			generate = append(generate, m.Source)
+		}
+	}
+	if len(result.Skipped) > 0 {
+		b.WriteString(fmt.Sprintf("\nLeft as resources (%d):\n", len(result.Skipped)))
+		for _, s := range result.Skipped {
+			b.WriteString(fmt.Sprintf("  [SKIP]  %s (%s): %s\n", s.Address, s.File, s.Reason))
+		}
+	}
+	if len(generate) > 0 {
+		b.WriteString("\nThese modules do not exist yet; generate and publish them before running terraform init:\n")
+		for _, source := range generate {
+			b.WriteString(fmt.Sprintf("  - %s\n", source))
+		}
+	}
+	if len(result.Migrated) > 0 {
+		b.WriteString("\nRun terraform plan to confirm every resource is moved, not replaced. Module tags are merged with the DPaaS tags, which shows as an in-place update.\n")
+	}
+	if result.Diff != "" {
+		b.WriteString("\nDiff:\n")
+		b.WriteString(result.Diff)
+	}
+	return b.String()
+}
@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// DPaaSPublishModule registers the dpaas_publish_module tool.
+func DPaaSPublishModule(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("dpaas_publish_module",
+			mcp.WithDescription(`Publishes an innersource module directory to the private registry of a Terraform Cloud/Enterprise organization:
+- the module is validated against DPaaS standards first and is not published unless validation passes
+- the registry module is created (without a VCS connection) when it does not exist
+- a version tagged with the latest release in CHANGELOG.md is created and the directory is uploaded to it as a tar.gz slug
+
+Publishing fails when the version already exists; add a release to CHANGELOG.md first. When the upload fails, the new version is deleted again so publishing can be retried. This tool requires a valid Terraform token to be configured.`),
+			mcp.WithTitleAnnotation("DPaaS: Publish module to the private registry"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization whose private registry the module is published to")),
+			mcp.WithString("module_path",
+				mcp.Required(),
+				mcp.Description("Filesystem path to the module directory to publish")),
+			mcp.WithString("module_name",
+				mcp.Description("Registry module name. Default: the module directory name (e.g. 'expn-tf-azure-storage-account')")),
+			mcp.WithString("module_provider",
+				mcp.Description("Registry module provider. Default: the provider of the module's primary resource, e.g. 'azurerm'")),
+			mcp.WithString("resource_type",
+				mcp.Description("The Azure resource type the module targets, or a comma-separated list, primary first. Detected from the module's resource blocks when omitted")),
+			withRESTAPISpec(),
+			mcp.WithString("rules_config",
+				mcp.Description("Path to a JSON rule configuration for the validation gate. Defaults to .dpaas-rules.json in the module directory when present")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack the module was generated with, whose conventions the validation gate checks: a built-in pack name or a pack directory. Default: 'experian'")),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return dpaasPublishModuleHandler(ctx, request, logger)
+		},
+	}
+}
+
+func dpaasPublishModuleHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	orgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: terraform_org_name", err)
+	}
+	orgName = strings.TrimSpace(orgName)
+
+	modulePath, err := request.RequireString("module_path")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: module_path", err)
+	}
+	if modulePath, err = filepath.Abs(strings.TrimSpace(modulePath)); err != nil {
+		return DPaaSToolError(logger, "invalid module_path", err)
+	}
+
+	infos, err := moduleSchemas(request, modulePath, logger)
+	if err != nil {
+		return DPaaSToolError(logger, "schema extraction failed (needed for coverage check)", err)
+	}
+
+	name := strings.TrimSpace(request.GetString("module_name", ""))
+	if name == "" {
+		name = filepath.Base(modulePath)
+	}
+	provider := strings.TrimSpace(request.GetString("module_provider", ""))
+	if provider == "" {
+		provider, _, _ = strings.Cut(infos[0].ResourceType, "_")
+	}
+
+	moduleVersion, err := validation.ChangelogVersion(modulePath)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to read the module version from CHANGELOG.md", err)
+	}
+
+	// only modules meeting the standards are published
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+	opts := validation.Options{Pack: pack}
+	if opts.Rules, err = ruleConfig(request); err != nil {
+		return DPaaSToolError(logger, "failed to load rules_config", err)
+	}
+	report, err := validation.ValidateResources(modulePath, infos, opts)
+	if err != nil {
+		return DPaaSToolError(logger, "validation failed", err)
+	}
+	if !report.Passed {
+		return DPaaSToolErrorf(logger, "%s was not published: it fails validation with %d errors\n\n%s",
+			modulePath, report.Errors, formatValidationReport(modulePath, report))
+	}
+
+	slug, err := validation.PackModule(modulePath)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to package the module", err)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return DPaaSToolError(logger, "failed to get Terraform client - ensure TFE_TOKEN and TFE_ADDRESS are configured", err)
+	}
+
+	moduleID := tfe.RegistryModuleID{
+		Organization: orgName,
+		Namespace:    orgName,
+		Name:         name,
+		Provider:     provider,
+		RegistryName: tfe.PrivateRegistry,
+	}
+	logger.WithFields(log.Fields{
+		"terraform_org_name": orgName,
+		"module":             name + "/" + provider,
+		"version":            moduleVersion,
+	}).Info("[dpaas] publishing module")
+
+	created := false
+	module, err := tfeClient.RegistryModules.Read(ctx, moduleID)
+	switch {
+	case errors.Is(err, tfe.ErrResourceNotFound):
+		module, err = tfeClient.RegistryModules.Create(ctx, orgName, tfe.RegistryModuleCreateOptions{
+			Name:         tfe.String(name),
+			Provider:     tfe.String(provider),
+			RegistryName: tfe.PrivateRegistry,
+			Namespace:    orgName,
+		})
+		if err != nil {
+			return DPaaSToolError(logger, fmt.Sprintf("failed to create registry module %s/%s", name, provider), err)
+		}
+		created = true
+	case err != nil:
+		return DPaaSToolError(logger, fmt.Sprintf("failed to read registry module %s/%s", name, provider), err)
+	}
+	if module.VCSRepo != nil {
+		return DPaaSToolErrorf(logger, "registry module %s/%s is published from VCS (%s); tag the repository instead", name, provider, module.VCSRepo.Identifier)
+	}
+	for _, vs := range module.VersionStatuses {
+		if vs.Version == moduleVersion {
+			return DPaaSToolErrorf(logger, "version %s of %s/%s already exists; add a release to CHANGELOG.md", moduleVersion, name, provider)
+		}
+	}
+
+	version, err := tfeClient.RegistryModules.CreateVersion(ctx, moduleID, tfe.RegistryModuleCreateVersionOptions{
+		Version: tfe.String(moduleVersion),
+	})
+	if err != nil {
+		return DPaaSToolError(logger, fmt.Sprintf("failed to create version %s", moduleVersion), err)
+	}
+	// a version that never gets its upload stays pending and blocks a retry
+	// with the same CHANGELOG.md release, so it is deleted again
+	discardVersion := func(cause error) (*mcp.CallToolResult, error) {
+		if err := tfeClient.RegistryModules.DeleteVersion(ctx, moduleID, moduleVersion); err != nil {
+			return DPaaSToolError(logger, fmt.Sprintf("failed to upload version %s, and deleting the pending version failed (%v); delete it from the registry before retrying", moduleVersion, err), cause)
+		}
+		return DPaaSToolError(logger, fmt.Sprintf("failed to upload version %s; the version was deleted, so publishing can be retried", moduleVersion), cause)
+	}
+	uploadURL, ok := version.Links["upload"].(string)
+	if !ok {
+		return discardVersion(errors.New("the registry returned no upload link"))
+	}
+	size := slug.Len()
+	if err := tfeClient.RegistryModules.UploadTarGzip(ctx, uploadURL, slug); err != nil {
+		return discardVersion(err)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Published %s/%s %s to the private registry of %s\n", name, provider, moduleVersion, orgName))
+	if created {
+		b.WriteString("Registry module created\n")
+	}
+	b.WriteString(fmt.Sprintf("Validation: passed (%d/%d checks, %d warnings)\n", report.PassedChecks, report.TotalChecks, report.Warnings))
+	b.WriteString(fmt.Sprintf("Uploaded slug: %d bytes\n", size))
+	b.WriteString("\nUsage:\n```hcl\n")
+	b.WriteString(fmt.Sprintf("module %q {\n", name))
+	b.WriteString(fmt.Sprintf("  source  = %q\n", path.Join(tfeClient.BaseURL().Host, orgName, name, provider)))
+	b.WriteString(fmt.Sprintf("  version = %q\n", moduleVersion))
+	b.WriteString("}\n```\n")
+	b.WriteString("\nThe registry ingests the upload asynchronously; get_private_module_details shows the version once it is ready.\n")
+	return mcp.NewToolResultText(b.String()), nil
+}
@@ -3,8 +3,12 @@ package tools
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
 	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/validation"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,17 +20,31 @@ import (
 func DPaaSValidateModule(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("dpaas_validate_module",
-			mcp.WithDescription("Validates an existing Terraform module against DPaaS innersource standards. Checks file structure, null-label markers, count patterns, tags, argument coverage, and more."),
+			mcp.WithDescription("Validates an existing Terraform module against DPaaS innersource standards. Checks file structure, null-label markers, count patterns, tags, argument coverage, and more. The wrapped resource types are detected from the module's HCL when resource_type is omitted, and coverage is reported for every wrapped resource. Coverage of an azapi_resource module is read from the REST API spec it was generated from, passed as rest_api_spec."),
 			mcp.WithTitleAnnotation("DPaaS: Validate module against innersource standards"),
 			mcp.WithOpenWorldHintAnnotation(true),
-			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(false),
 			mcp.WithString("module_path",
 				mcp.Required(),
 				mcp.Description("Filesystem path to the module directory to validate")),
 			mcp.WithString("resource_type",
-				mcp.Required(),
-				mcp.Description("The Azure resource type the module targets (e.g. 'azurerm_bastion_host')")),
+				mcp.Description("The Azure resource type the module targets (e.g. 'azurerm_bastion_host'), or a comma-separated list when the module wraps several resources, primary first. Detected from the module's resource blocks when omitted")),
+			withRESTAPISpec(),
+			mcp.WithString("rules_config",
+				mcp.Description("Path to a JSON rule configuration that disables rules, overrides severities or adds custom rules. Defaults to .dpaas-rules.json in the module directory when present")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack the module was generated with, whose tag local and required tags are checked and whose templates render missing files when fixing: a built-in pack name or a pack directory. Default: 'experian'")),
+			mcp.WithBoolean("run_terraform",
+				mcp.Description("Also run terraform init and validate in every tests/* scenario and terraform test against tests/*.tftest.hcl, and report the diagnostics. Default: false")),
+			mcp.WithString("provider_mirror",
+				mcp.Description("Filesystem provider mirror directory (as written by 'terraform providers mirror') used by terraform init so the terraform stage works offline. Only used when run_terraform is true")),
+			mcp.WithBoolean("fix",
+				mcp.Description("Apply safe, mechanical fixes before validating: add missing static files, the null-label section and create_ flag, tags = local.tags, and variables plus wiring for uncovered top-level arguments. Ambiguous findings are left alone. The diff of every change is returned. Default: false")),
+			mcp.WithBoolean("update_readme",
+				mcp.Description("Rewrite the README.md benchmark table and last review date from this validation run. Default: false")),
+			withOutputFormat(),
+			withURIBase(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return dpaasValidateModuleHandler(ctx, request, logger)
@@ -40,27 +58,215 @@ func dpaasValidateModuleHandler(_ context.Context, request mcp.CallToolRequest,
 		return DPaaSToolError(logger, "missing required input: module_path", err)
 	}
 
-	resourceType, err := request.RequireString("resource_type")
+	format, err := outputFormat(request)
+	if err != nil {
+		return DPaaSToolError(logger, "invalid output_format", err)
+	}
+
+	// extract schemas for coverage comparison
+	infos, err := moduleSchemas(request, modulePath, logger)
 	if err != nil {
-		return DPaaSToolError(logger, "missing required input: resource_type", err)
+		return DPaaSToolError(logger, "schema extraction failed (needed for coverage check)", err)
 	}
-	resourceType = strings.TrimSpace(strings.ToLower(resourceType))
 
-	// extract schema for coverage comparison
-	info, err := schema.ExtractResourceSchema(resourceType, logger)
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
 	if err != nil {
-		return DPaaSToolError(logger, fmt.Sprintf("failed to extract schema for %s (needed for coverage check)", resourceType), err)
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+
+	opts := validation.Options{Pack: pack}
+	if opts.Rules, err = ruleConfig(request); err != nil {
+		return DPaaSToolError(logger, "failed to load rules_config", err)
+	}
+	if request.GetBool("run_terraform", false) {
+		opts.Terraform = &validation.TerraformOptions{
+			PluginMirrorDir: strings.TrimSpace(request.GetString("provider_mirror", "")),
+		}
+		logger.Infof("[dpaas] running terraform stage (provider mirror: %q)", opts.Terraform.PluginMirrorDir)
+	}
+
+	var fix *validation.FixResult
+	if request.GetBool("fix", false) {
+		logger.Infof("[dpaas] applying safe fixes to %s", modulePath)
+		if fix, err = validation.FixModule(modulePath, infos[0], validation.FixOptions{Pack: pack}); err != nil {
+			return DPaaSToolError(logger, "fix failed", err)
+		}
 	}
 
-	report, err := validation.ValidateModule(modulePath, info)
+	report, err := validation.ValidateResources(modulePath, infos, opts)
 	if err != nil {
 		return DPaaSToolError(logger, "validation failed", err)
 	}
+	report.Fix = fix
+
+	if request.GetBool("update_readme", false) {
+		if err := validation.UpdateReadmeBenchmarks(modulePath, report, time.Now().Format("2006-01-02")); err != nil {
+			return DPaaSToolError(logger, "failed to update README benchmark table", err)
+		}
+	}
+
+	if format == validation.FormatText {
+		return mcp.NewToolResultText(formatValidationReport(modulePath, report)), nil
+	}
+	return renderValidationReport(logger, request, format, modulePath, report)
+}
+
+// ruleConfig loads the rules_config file named in the request, if any.
+func ruleConfig(request mcp.CallToolRequest) (*validation.RuleConfig, error) {
+	rulesPath := strings.TrimSpace(request.GetString("rules_config", ""))
+	if rulesPath == "" {
+		return nil, nil
+	}
+	return validation.LoadRuleConfig(rulesPath)
+}
+
+// withRESTAPISpec declares the rest_api_spec parameter of the tools that
+// validate azapi_resource modules.
+func withRESTAPISpec() mcp.ToolOption {
+	return mcp.WithString("rest_api_spec",
+		mcp.Description("Path to the Azure REST API spec (swagger JSON) an azapi_resource module was generated from. The resource type and API version are read from the module's azapi_resource type argument. Without it, azapi_resource has no schema and its coverage is not reported"))
+}
+
+// moduleSchemas returns the schemas of the resources a module wraps, primary
+// first: the types in resource_type, or the ones detected from the module's
+// resource blocks. azurerm schemas are extracted from the provider;
+// azapi_resource is read from rest_api_spec.
+func moduleSchemas(request mcp.CallToolRequest, modulePath string, logger *log.Logger) ([]*schema.ResourceInfo, error) {
+	spec, err := readRESTAPISpec(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceTypes := parseResourceTypes(request.GetString("resource_type", ""))
+	if len(resourceTypes) == 0 {
+		if resourceTypes, err = detectResourceTypes(modulePath, spec != nil, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	var infos []*schema.ResourceInfo
+	for _, rt := range resourceTypes {
+		if rt != schema.AzAPIResourceType {
+			info, err := schema.ExtractResourceSchema(rt, logger)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract schema for %s: %w", rt, err)
+			}
+			infos = append(infos, info)
+			continue
+		}
+		if spec == nil {
+			return nil, fmt.Errorf("%s has no provider schema; pass the REST API spec the module was generated from as rest_api_spec", rt)
+		}
+		azapi, err := validation.ReadAzAPIResource(modulePath, spec)
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("[dpaas] %s read from rest_api_spec", azapi.TypeVersion())
+		infos = append(infos, azapi.Info)
+	}
+	return infos, nil
+}
+
+// readRESTAPISpec reads the rest_api_spec file named in the request, if any.
+func readRESTAPISpec(request mcp.CallToolRequest) ([]byte, error) {
+	specPath := strings.TrimSpace(request.GetString("rest_api_spec", ""))
+	if specPath == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rest_api_spec: %w", err)
+	}
+	return raw, nil
+}
 
-	return mcp.NewToolResultText(formatValidationReport(modulePath, info, report)), nil
+// detectResourceTypes returns the resource types of a module coverage can be
+// checked for: its azurerm resources, and azapi_resource when a REST API spec
+// describes it.
+func detectResourceTypes(modulePath string, azapi bool, logger *log.Logger) ([]string, error) {
+	detected, err := validation.DetectResourceTypes(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module for resource type detection: %w", err)
+	}
+	var resourceTypes []string
+	for _, rt := range detected {
+		if strings.HasPrefix(rt, "azurerm_") || (azapi && rt == schema.AzAPIResourceType) {
+			resourceTypes = append(resourceTypes, rt)
+		} else {
+			logger.Infof("[dpaas] skipping %s: no schema for coverage", rt)
+		}
+	}
+	if len(resourceTypes) == 0 {
+		return nil, fmt.Errorf("no azurerm resources found in %s; pass resource_type explicitly, or rest_api_spec for an azapi_resource module", modulePath)
+	}
+	logger.Infof("[dpaas] detected resource types: %v", resourceTypes)
+	return resourceTypes, nil
 }
 
-func formatValidationReport(path string, info *schema.ResourceInfo, report *validation.ValidationReport) string {
+// parseResourceTypes splits a comma-separated resource_type value.
+func parseResourceTypes(raw string) []string {
+	var out []string
+	for _, rt := range strings.Split(raw, ",") {
+		if rt = strings.TrimSpace(strings.ToLower(rt)); rt != "" {
+			out = append(out, rt)
+		}
+	}
+	return out
+}
+
+// withOutputFormat declares the output_format parameter shared by the tools
+// that return a validation report.
+func withOutputFormat() mcp.ToolOption {
+	return mcp.WithString("output_format",
+		mcp.Enum(validation.OutputFormats...),
+		mcp.Description("Report format: 'text' (default, human-readable), 'json' (also returned as structured content), 'sarif' (SARIF 2.1.0 for code-scanning annotations) or 'junit' (JUnit XML for test result publishing)"))
+}
+
+// withURIBase declares the uri_base parameter locating SARIF results in the
+// repository.
+func withURIBase() mcp.ToolOption {
+	return mcp.WithString("uri_base",
+		mcp.Description("Path of the module directory relative to the repository root (e.g. 'modules/storage-account'). SARIF locations are module-relative and resolved against it through %SRCROOT%. Default: the module is the repository root"))
+}
+
+func outputFormat(request mcp.CallToolRequest) (string, error) {
+	format := strings.TrimSpace(strings.ToLower(request.GetString("output_format", validation.FormatText)))
+	if format == "" {
+		return validation.FormatText, nil
+	}
+	for _, f := range validation.OutputFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(validation.OutputFormats, ", "))
+}
+
+// renderValidationReport returns the report in a machine-readable format. JSON
+// is returned as structured content with the same JSON as its text fallback.
+func renderValidationReport(logger *log.Logger, request mcp.CallToolRequest, format, modulePath string, report *validation.ValidationReport) (*mcp.CallToolResult, error) {
+	var (
+		out []byte
+		err error
+	)
+	switch format {
+	case validation.FormatJSON:
+		out, err = report.JSON()
+		if err == nil {
+			return mcp.NewToolResultStructured(report, string(out)), nil
+		}
+	case validation.FormatSARIF:
+		out, err = report.SARIF(strings.TrimSpace(request.GetString("uri_base", "")))
+	case validation.FormatJUnit:
+		out, err = report.JUnit(filepath.Base(filepath.Clean(modulePath)))
+	}
+	if err != nil {
+		return DPaaSToolError(logger, fmt.Sprintf("failed to render %s report", format), err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+func formatValidationReport(path string, report *validation.ValidationReport) string {
 	var b strings.Builder
 
 	status := "PASSED"
@@ -69,33 +275,97 @@ func formatValidationReport(path string, info *schema.ResourceInfo, report *vali
 	}
 
 	b.WriteString(fmt.Sprintf("Validation Report: %s\n", path))
-	b.WriteString(fmt.Sprintf("Resource Type:     %s\n", info.ResourceType))
+	var types []string
+	for _, rc := range report.ResourceCoverage {
+		types = append(types, rc.ResourceType)
+	}
+	b.WriteString(fmt.Sprintf("Resource Type:     %s\n", strings.Join(types, ", ")))
 	b.WriteString(fmt.Sprintf("Result:            %s\n\n", status))
-	b.WriteString(fmt.Sprintf("Checks: %d/%d passed\n", report.PassedChecks, report.TotalChecks))
+	b.WriteString(fmt.Sprintf("Checks: %d/%d passed (%d errors, %d warnings, %d info)\n",
+		report.PassedChecks, report.TotalChecks, report.Errors, report.Warnings, report.Infos))
 
 	for _, c := range report.Checks {
-		symbol := "PASS"
-		if !c.Passed {
-			symbol = "FAIL"
-		}
-		line := fmt.Sprintf("  [%s] %s", symbol, c.Name)
-		if !c.Passed && c.Message != "" {
-			line += " -- " + c.Message
+		b.WriteString(formatCheckLine(c) + "\n")
+	}
+
+	if len(report.ResourceCoverage) > 1 {
+		for _, rc := range report.ResourceCoverage {
+			address := rc.Address
+			if address == "" {
+				address = "not declared"
+			}
+			b.WriteString(fmt.Sprintf("\n%s (%s)", rc.ResourceType, address))
+			writeCoverageSummary(&b, rc.Coverage)
 		}
-		b.WriteString(line + "\n")
+	} else if cr := report.CoverageReport; cr != nil {
+		writeCoverageSummary(&b, cr)
+	}
+
+	if report.Fix != nil {
+		writeFixSummary(&b, report.Fix)
 	}
 
-	if cr := report.CoverageReport; cr != nil {
-		b.WriteString(fmt.Sprintf("\nArgument Coverage: %.0f%%\n", cr.CoveragePercent))
-		b.WriteString(fmt.Sprintf("  Attributes: %d/%d\n", cr.GeneratedAttrCount, cr.SchemaAttrCount))
-		b.WriteString(fmt.Sprintf("  Blocks:     %d/%d\n", cr.GeneratedBlockCount, cr.SchemaBlockCount))
-		if len(cr.MissingAttrs) > 0 {
-			b.WriteString(fmt.Sprintf("  Missing attributes: %s\n", strings.Join(cr.MissingAttrs, ", ")))
+	return b.String()
+}
+
+// writeFixSummary lists the applied and skipped fixes followed by the diff.
+func writeFixSummary(b *strings.Builder, fix *validation.FixResult) {
+	b.WriteString(fmt.Sprintf("\nFixes applied (%d):\n", len(fix.Applied)))
+	for _, a := range fix.Applied {
+		b.WriteString(fmt.Sprintf("  [FIXED] %s %s: %s\n", a.RuleID, a.File, a.Description))
+	}
+	if len(fix.Skipped) > 0 {
+		b.WriteString(fmt.Sprintf("\nLeft for manual review (%d):\n", len(fix.Skipped)))
+		for _, s := range fix.Skipped {
+			b.WriteString(fmt.Sprintf("  [SKIP]  %s %s: %s\n", s.RuleID, s.File, s.Description))
 		}
-		if len(cr.MissingBlocks) > 0 {
-			b.WriteString(fmt.Sprintf("  Missing blocks: %s\n", strings.Join(cr.MissingBlocks, ", ")))
+	}
+	if fix.Diff != "" {
+		b.WriteString("\nDiff:\n")
+		b.WriteString(fix.Diff)
+	}
+}
+
+// formatCheckLine renders one check with its rule ID and, when known, its
+// file:line location. Failing checks are labelled by severity.
+func formatCheckLine(c validation.CheckResult) string {
+	symbol := "PASS"
+	if !c.Passed {
+		switch c.Severity {
+		case validation.SeverityWarning:
+			symbol = "WARN"
+		case validation.SeverityInfo:
+			symbol = "INFO"
+		default:
+			symbol = "FAIL"
 		}
 	}
+	line := fmt.Sprintf("  [%s] %s %s", symbol, c.RuleID, c.Name)
+	if c.File != "" {
+		line += fmt.Sprintf(" (%s:%d)", c.File, c.Line)
+	}
+	if !c.Passed && c.Message != "" {
+		line += " -- " + c.Message
+	}
+	return line
+}
 
-	return b.String()
+// writeCoverageSummary renders the argument coverage section shared by the
+// generation and validation reports.
+func writeCoverageSummary(b *strings.Builder, cr *validation.CoverageReport) {
+	b.WriteString(fmt.Sprintf("\nArgument Coverage: %.0f%%\n", cr.CoveragePercent))
+	b.WriteString(fmt.Sprintf("  Attributes: %d/%d\n", cr.GeneratedAttrCount, cr.SchemaAttrCount))
+	b.WriteString(fmt.Sprintf("  Blocks:     %d/%d\n", cr.GeneratedBlockCount, cr.SchemaBlockCount))
+	for _, lc := range cr.Levels {
+		b.WriteString(fmt.Sprintf("  Depth %d:    %d/%d (%.0f%%)\n", lc.Depth, lc.Wired, lc.Total, lc.Percent))
+	}
+	if len(cr.MissingAttrs) > 0 {
+		b.WriteString(fmt.Sprintf("  Missing attributes: %s\n", strings.Join(cr.MissingAttrs, ", ")))
+	}
+	if len(cr.MissingBlocks) > 0 {
+		b.WriteString(fmt.Sprintf("  Missing blocks: %s\n", strings.Join(cr.MissingBlocks, ", ")))
+	}
+	if len(cr.UnwiredObjectFields) > 0 {
+		b.WriteString(fmt.Sprintf("  Unwired object fields: %s\n", strings.Join(cr.UnwiredObjectFields, ", ")))
+	}
 }
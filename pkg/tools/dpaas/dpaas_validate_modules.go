@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/generators"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/schema"
+	"github.com/hashicorp/terraform-mcp-server/pkg/dpaas/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// DPaaSValidateModules registers the dpaas_validate_modules tool.
+func DPaaSValidateModules(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("dpaas_validate_modules",
+			mcp.WithDescription("Finds every innersource module directory under a root (e.g. a monorepo of expn-tf-azure-* modules) and validates each one in parallel against DPaaS standards. Returns a compliance dashboard with per-module pass/fail, coverage percentages and the most common failing rules. Resource types are detected from each module's HCL; azapi_resource modules are covered when rest_api_spec describes them."),
+			mcp.WithTitleAnnotation("DPaaS: Validate every module under a directory"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("root_path",
+				mcp.Required(),
+				mcp.Description("Directory to search for module directories")),
+			mcp.WithString("pattern",
+				mcp.Description("Glob that module directory names must match. Default: the template pack's module prefix followed by '*', e.g. 'expn-tf-azure-*'")),
+			withRESTAPISpec(),
+			mcp.WithNumber("workers",
+				mcp.Description("Maximum number of modules validated at once. Default: number of CPUs"),
+				mcp.Min(1)),
+			mcp.WithString("rules_config",
+				mcp.Description("Path to a JSON rule configuration applied to every module. Defaults to each module's own .dpaas-rules.json when present")),
+			mcp.WithString("template_pack",
+				mcp.Description("Template pack the modules were generated with: its module prefix finds them and its tag conventions are checked. A built-in pack name or a pack directory. Default: 'experian'")),
+			mcp.WithString("output_format",
+				mcp.Enum(validation.FormatText, validation.FormatJSON),
+				mcp.Description("Dashboard format: 'text' (default) or 'json' (also returned as structured content)")),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return dpaasValidateModulesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func dpaasValidateModulesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	root, err := request.RequireString("root_path")
+	if err != nil {
+		return DPaaSToolError(logger, "missing required input: root_path", err)
+	}
+
+	format := strings.TrimSpace(strings.ToLower(request.GetString("output_format", validation.FormatText)))
+	if format != validation.FormatText && format != validation.FormatJSON {
+		return DPaaSToolErrorf(logger, "invalid output_format %q, expected text or json", format)
+	}
+
+	pack, err := generators.LoadTemplatePack(strings.TrimSpace(request.GetString("template_pack", "")))
+	if err != nil {
+		return DPaaSToolError(logger, "failed to load template_pack", err)
+	}
+
+	opts := validation.BatchOptions{
+		Pattern: strings.TrimSpace(request.GetString("pattern", "")),
+		Workers: request.GetInt("workers", 0),
+		Resolve: newSchemaResolver(logger),
+	}
+	opts.Pack = pack
+	if opts.RESTAPISpec, err = readRESTAPISpec(request); err != nil {
+		return DPaaSToolError(logger, "failed to load rest_api_spec", err)
+	}
+	if opts.Rules, err = ruleConfig(request); err != nil {
+		return DPaaSToolError(logger, "failed to load rules_config", err)
+	}
+
+	logger.Infof("[dpaas] validating modules under %s", root)
+	dashboard, err := validation.ValidateBatch(ctx, root, opts)
+	if err != nil {
+		return DPaaSToolError(logger, "batch validation failed", err)
+	}
+	if dashboard.Total == 0 {
+		return DPaaSToolErrorf(logger, "no module directories matching %q found under %s", opts.ModulePattern(), root)
+	}
+
+	if format == validation.FormatJSON {
+		result, err := mcp.NewToolResultJSON(dashboard)
+		if err != nil {
+			return DPaaSToolError(logger, "failed to render dashboard", err)
+		}
+		return result, nil
+	}
+	return mcp.NewToolResultText(formatDashboard(dashboard)), nil
+}
+
+// newSchemaResolver returns a validation.SchemaResolver that extracts each
+// azurerm resource schema once and shares it between modules: modules
+// validated concurrently wait for the one extraction of a type in flight.
+// Other types have no provider schema and are left out; ValidateBatch reads
+// azapi_resource from the REST API spec instead.
+func newSchemaResolver(logger *log.Logger) validation.SchemaResolver {
+	type extraction struct {
+		once sync.Once
+		info *schema.ResourceInfo
+		err  error
+	}
+	var mu sync.Mutex
+	cache := map[string]*extraction{}
+
+	return func(resourceTypes []string) ([]*schema.ResourceInfo, error) {
+		var infos []*schema.ResourceInfo
+		for _, rt := range resourceTypes {
+			if !strings.HasPrefix(rt, "azurerm_") {
+				continue
+			}
+			mu.Lock()
+			e, ok := cache[rt]
+			if !ok {
+				e = &extraction{}
+				cache[rt] = e
+			}
+			mu.Unlock()
+
+			e.once.Do(func() {
+				e.info, e.err = schema.ExtractResourceSchema(rt, logger)
+			})
+			if e.err != nil {
+				return nil, fmt.Errorf("extract schema for %s: %w", rt, e.err)
+			}
+			infos = append(infos, e.info)
+		}
+		return infos, nil
+	}
+}
+
+func formatDashboard(d *validation.Dashboard) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Compliance Dashboard: %s\n", d.Root))
+	b.WriteString(fmt.Sprintf("Modules:          %d (%d passed, %d failed, %d errored)\n", d.Total, d.Passed, d.Failed, d.Errored))
+	b.WriteString(fmt.Sprintf("Pass rate:        %.0f%%\n", d.PassRate))
+	b.WriteString(fmt.Sprintf("Average coverage: %.0f%%\n\n", d.AverageCoverage))
+
+	b.WriteString("Modules:\n")
+	for _, m := range d.Modules {
+		if m.Report == nil {
+			b.WriteString(fmt.Sprintf("  [ERROR] %s -- %s\n", m.Path, m.Error))
+			continue
+		}
+		status := "PASS"
+		if !m.Report.Passed {
+			status = "FAIL"
+		}
+		coverage := "n/a"
+		if cr := m.Report.CoverageReport; cr != nil {
+			coverage = fmt.Sprintf("%.0f%%", cr.CoveragePercent)
+		}
+		b.WriteString(fmt.Sprintf("  [%s] %s  checks %d/%d  coverage %s  (%d errors, %d warnings)\n",
+			status, m.Path, m.Report.PassedChecks, m.Report.TotalChecks, coverage, m.Report.Errors, m.Report.Warnings))
+	}
+
+	if len(d.FailingRules) > 0 {
+		b.WriteString("\nMost common failing rules:\n")
+		for _, rf := range d.FailingRules {
+			b.WriteString(fmt.Sprintf("  %-9s %-7s failed in %d/%d modules\n", rf.RuleID, rf.Severity, rf.Modules, d.Total))
+		}
+	}
+	return b.String()
+}
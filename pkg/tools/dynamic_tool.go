@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	dpaasTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/dpaas"
 	tfeTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/tfe"
 	"github.com/hashicorp/terraform-mcp-server/pkg/toolsets"
 	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
@@ -282,6 +283,17 @@ func (r *DynamicToolRegistry) registerTFETools() {
 		r.mcpServer.AddTool(tool.Tool, tool.Handler)
 	}
 
+	// DPaaS toolset - publishing and comparison need the organization's private registry
+	if toolsets.IsToolEnabled("dpaas_publish_module", r.enabledToolsets) {
+		tool := r.createDynamicTFETool("dpaas_publish_module", dpaasTools.DPaaSPublishModule)
+		r.mcpServer.AddTool(tool.Tool, tool.Handler)
+	}
+
+	if toolsets.IsToolEnabled("dpaas_compare_private_module", r.enabledToolsets) {
+		tool := r.createDynamicTFETool("dpaas_compare_private_module", dpaasTools.DPaaSComparePrivateModule)
+		r.mcpServer.AddTool(tool.Tool, tool.Handler)
+	}
+
 	r.tfeToolsRegistered = true
 }
 
@@ -83,4 +83,24 @@ func RegisterTools(hcServer *server.MCPServer, logger *log.Logger, enabledToolse
 		tool := dpaasTools.DPaaSValidateModule(logger)
 		hcServer.AddTool(tool.Tool, tool.Handler)
 	}
+
+	if toolsets.IsToolEnabled("dpaas_validate_modules", enabledToolsets) {
+		tool := dpaasTools.DPaaSValidateModules(logger)
+		hcServer.AddTool(tool.Tool, tool.Handler)
+	}
+
+	if toolsets.IsToolEnabled("dpaas_import_resource", enabledToolsets) {
+		tool := dpaasTools.DPaaSImportResource(logger)
+		hcServer.AddTool(tool.Tool, tool.Handler)
+	}
+
+	if toolsets.IsToolEnabled("dpaas_migrate_resources", enabledToolsets) {
+		tool := dpaasTools.DPaaSMigrateResources(logger)
+		hcServer.AddTool(tool.Tool, tool.Handler)
+	}
+
+	if toolsets.IsToolEnabled("dpaas_generate_policies", enabledToolsets) {
+		tool := dpaasTools.DPaaSGeneratePolicies(logger)
+		hcServer.AddTool(tool.Tool, tool.Handler)
+	}
 }
@@ -58,6 +58,12 @@ var ToolToToolset = map[string]string{
 	"dpaas_extract_resource_schema":     DPaaS,
 	"dpaas_generate_innersource_module": DPaaS,
 	"dpaas_validate_module":             DPaaS,
+	"dpaas_validate_modules":            DPaaS,
+	"dpaas_import_resource":             DPaaS,
+	"dpaas_migrate_resources":           DPaaS,
+	"dpaas_generate_policies":           DPaaS,
+	"dpaas_publish_module":              DPaaS,
+	"dpaas_compare_private_module":      DPaaS,
 }
 
 // GetToolsetForTool returns the toolset name for a given tool name